@@ -0,0 +1,330 @@
+package gormcrypto
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Assigner is implemented by values which can take their plaintext from a plain Go value, such as the types in the cryptypes package.
+// GORM writes values given in maps, to Update and UpdateColumn, and in clause.Set exactly as they are given, so a Plugin uses it
+// to convert plain values given for those fields - as in Updates(map[string]interface{}{"email": "x@y"}) - before they're encrypted.
+type Assigner interface {
+	// Assign replaces the value's plaintext, failing if the plain value can't be converted to the value's type
+	Assign(value interface{}) error
+}
+
+// PRIVATE
+
+var assignerType = reflect.TypeOf((*Assigner)(nil)).Elem()
+
+// cryptoSerializers are the GORM serializers registered by the cryptypes package, whose fields also need plain values encrypted
+var cryptoSerializers = map[string]bool{"encrypted": true, "signed": true, "signedencrypted": true}
+
+// rowKeys returns the primary keys of the row being written, or false if there isn't exactly one row with known primary keys
+type rowKeys func() ([]interface{}, bool)
+
+// assignFunc converts a value given for a field in a map or clause.Set
+type assignFunc func(db *gorm.DB, field *schema.Field, value interface{}, row rowKeys) (interface{}, error)
+
+// assignCreated encrypts the plain values given for encrypted fields in maps passed to Create.
+// The maps are copied first, so the caller's maps never end up holding encrypted values.
+func (p *Plugin) assignCreated(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	switch dest := db.Statement.Dest.(type) {
+	case map[string]interface{}:
+		db.Statement.Dest = p.assignMap(db, dest, mapKeys(db, dest), p.encrypted, true)
+	case *map[string]interface{}:
+		assigned := p.assignMap(db, *dest, mapKeys(db, *dest), p.encrypted, true)
+		db.Statement.Dest = &assigned
+	case []map[string]interface{}:
+		db.Statement.Dest = p.assignMaps(db, dest)
+	case *[]map[string]interface{}:
+		assigned := p.assignMaps(db, *dest)
+		db.Statement.Dest = &assigned
+	}
+}
+
+// assignUpdated encrypts the plain values given for encrypted fields in maps passed to Updates, Update, and UpdateColumn, and in clause.Set.
+// Maps are converted into the SET clause here rather than by GORM, so the values assigned to the model are converted but not yet encrypted.
+func (p *Plugin) assignUpdated(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil || db.Statement.SQL.Len() > 0 {
+		return
+	}
+
+	row := modelKeys(db)
+	if _, ok := db.Statement.Clauses["SET"]; !ok {
+		values, ok := db.Statement.Dest.(map[string]interface{})
+		if !ok {
+			return
+		}
+
+		db.Statement.Dest = p.assignMap(db, values, row, p.typed, false)
+		if db.Error != nil {
+			return
+		}
+		set := callbacks.ConvertToAssignments(db.Statement)
+		if len(set) < 1 {
+			return
+		}
+		db.Statement.AddClause(set)
+	}
+
+	set, ok := db.Statement.Clauses["SET"].Expression.(clause.Set)
+	if !ok {
+		return
+	}
+
+	assigned := make(clause.Set, len(set))
+	for i, assignment := range set {
+		assigned[i] = assignment
+		field := db.Statement.Schema.LookUpField(assignment.Column.Name)
+		if field == nil {
+			continue
+		}
+
+		value, err := p.encrypted(db, field, assignment.Value, row)
+		if err != nil {
+			db.AddError(err)
+			return
+		}
+		assigned[i].Value = value
+	}
+	db.Statement.AddClause(assigned)
+}
+
+// assignMaps copies a slice of maps passed to Create, encrypting the plain values given for encrypted fields
+func (p *Plugin) assignMaps(db *gorm.DB, values []map[string]interface{}) []map[string]interface{} {
+	assigned := make([]map[string]interface{}, len(values))
+	for i, value := range values {
+		assigned[i] = p.assignMap(db, value, mapKeys(db, value), p.encrypted, true)
+	}
+
+	return assigned
+}
+
+// assignMap copies a map of values to write, converting the values given for encrypted fields, and adding the BlindIndexes of any fields they index
+func (p *Plugin) assignMap(db *gorm.DB, values map[string]interface{}, row rowKeys, assign assignFunc, create bool) map[string]interface{} {
+	assigned := make(map[string]interface{}, len(values))
+	for name, value := range values {
+		assigned[name] = value
+
+		field := db.Statement.Schema.LookUpField(name)
+		if field == nil {
+			continue
+		}
+		converted, err := assign(db, field, value, row)
+		if err != nil {
+			db.AddError(err)
+			continue
+		}
+		assigned[name] = converted
+	}
+
+	p.indexMap(db, values, assigned, create)
+
+	return assigned
+}
+
+// indexMap adds the BlindIndex of every indexed field given in a map of values to write, unless the index is given as well
+func (p *Plugin) indexMap(db *gorm.DB, values, assigned map[string]interface{}, create bool) {
+	specs, err := blindIndexSpecs(db.Statement.Schema)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	selected, restricted := db.Statement.SelectAndOmitColumns(create, !create)
+	for _, spec := range specs {
+		source, ok := mapValue(values, spec.source)
+		if !ok || expression(source) {
+			continue
+		}
+		if _, ok := mapValue(values, spec.field); ok {
+			continue
+		}
+
+		setup, err := p.Config.ActiveSetup()
+		if err != nil {
+			db.AddError(err)
+			return
+		}
+		index, err := spec.index(setup, source)
+		if err != nil {
+			db.AddError(err)
+			continue
+		}
+		assigned[spec.field.DBName] = index
+
+		if restricted && selected[spec.source.DBName] && !selected[spec.field.DBName] {
+			db.Statement.Selects = append(db.Statement.Selects, spec.field.DBName)
+		}
+	}
+}
+
+// typed converts a plain value given for an Assigner field into a value of the field's type, bound to the Plugin's Config and, if tagged, its row.
+// Values of other fields, and expressions, are returned as they are.
+func (p *Plugin) typed(db *gorm.DB, field *schema.Field, value interface{}, row rowKeys) (interface{}, error) {
+	if expression(value) || nullValue(value) || !assignable(field) {
+		return value, nil
+	}
+
+	typed := reflect.New(field.IndirectFieldType)
+	switch v := reflect.ValueOf(value); v.Type() {
+	case field.IndirectFieldType:
+		typed.Elem().Set(v)
+	case reflect.PtrTo(field.IndirectFieldType):
+		typed.Elem().Set(v.Elem())
+	default:
+		if err := typed.Interface().(Assigner).Assign(value); err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", db.Statement.Schema.Name, field.Name, err)
+		}
+	}
+
+	if binder, ok := typed.Interface().(Binder); ok {
+		binder.BindConfig(&p.Config)
+	}
+	if err := bindStorage(field, typed.Interface()); err != nil {
+		return nil, err
+	}
+	if binder, ok := typed.Interface().(ContextBinder); ok {
+		var context []byte
+		if contextFields(db.Statement.Schema)[field] {
+			keys, ok := row()
+			if !ok {
+				return nil, fmt.Errorf("%s.%s is bound to its rows, so it can only be assigned to a single row whose primary key is known", db.Statement.Schema.Name, field.Name)
+			}
+			context = RowContext(db.Statement.Table, field.DBName, keys...)
+		}
+		binder.BindContext(context)
+	}
+
+	if field.FieldType.Kind() == reflect.Ptr {
+		return typed.Interface(), nil
+	}
+
+	return typed.Elem().Interface(), nil
+}
+
+// encrypted converts a plain value given for an encrypted field into the value to store in the DB, refusing values it can't encrypt.
+// Values of other fields, and expressions, are returned as they are.
+func (p *Plugin) encrypted(db *gorm.DB, field *schema.Field, value interface{}, row rowKeys) (interface{}, error) {
+	if expression(value) || nullValue(value) {
+		return value, nil
+	}
+
+	if cryptoSerializers[field.TagSettings["SERIALIZER"]] {
+		serializer, ok := field.Serializer.(schema.SerializerValuerInterface)
+		if !ok {
+			return value, nil
+		}
+
+		return serializer.Value(db.Statement.Context, field, db.Statement.ReflectValue, value)
+	}
+
+	if !assignable(field) {
+		return value, nil
+	}
+
+	typed, err := p.typed(db, field, value, row)
+	if err != nil {
+		return nil, err
+	}
+
+	valuer, ok := typed.(driver.Valuer)
+	if !ok {
+		return value, nil
+	}
+	stored, err := valuer.Value()
+	if err != nil {
+		return nil, fmt.Errorf("%s.%s: %w", db.Statement.Schema.Name, field.Name, err)
+	}
+
+	return stored, nil
+}
+
+// assignable reports whether a field holds an Assigner, such as the types in the cryptypes package
+func assignable(field *schema.Field) bool {
+	return reflect.PtrTo(field.IndirectFieldType).Implements(assignerType)
+}
+
+// modelKeys finds the primary keys of the Statement's model, for Statements which update a single row
+func modelKeys(db *gorm.DB) rowKeys {
+	return func() ([]interface{}, bool) {
+		model := reflect.Indirect(db.Statement.ReflectValue)
+		if model.Kind() != reflect.Struct || len(db.Statement.Schema.PrimaryFields) < 1 {
+			return nil, false
+		}
+
+		keys := make([]interface{}, len(db.Statement.Schema.PrimaryFields))
+		for i, primary := range db.Statement.Schema.PrimaryFields {
+			key, zero := primary.ValueOf(db.Statement.Context, model)
+			if zero {
+				return nil, false
+			}
+			keys[i] = key
+		}
+
+		return keys, true
+	}
+}
+
+// mapKeys finds the primary keys given in a map of values to create
+func mapKeys(db *gorm.DB, values map[string]interface{}) rowKeys {
+	return func() ([]interface{}, bool) {
+		if len(db.Statement.Schema.PrimaryFields) < 1 {
+			return nil, false
+		}
+
+		keys := make([]interface{}, len(db.Statement.Schema.PrimaryFields))
+		for i, primary := range db.Statement.Schema.PrimaryFields {
+			key, ok := mapValue(values, primary)
+			if !ok || nullValue(key) || reflect.ValueOf(key).IsZero() {
+				return nil, false
+			}
+			keys[i] = key
+		}
+
+		return keys, true
+	}
+}
+
+// mapValue finds the value given for a field in a map, by either its name or its column
+func mapValue(values map[string]interface{}, field *schema.Field) (interface{}, bool) {
+	if value, ok := values[field.Name]; ok {
+		return value, true
+	}
+	value, ok := values[field.DBName]
+
+	return value, ok
+}
+
+// expression reports whether a value is computed by the DB, such as gorm.Expr or a subquery, rather than a plain value
+func expression(value interface{}) bool {
+	switch v := value.(type) {
+	case clause.Expression, *gorm.DB:
+		return true
+	case []interface{}:
+		if len(v) == 1 {
+			_, ok := v[0].(*gorm.DB)
+			return ok
+		}
+	}
+
+	return false
+}
+
+// nullValue reports whether a value is nil, or a nil pointer, which are stored as NULL
+func nullValue(value interface{}) bool {
+	v := reflect.ValueOf(value)
+
+	return !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil())
+}
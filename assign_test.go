@@ -0,0 +1,93 @@
+package gormcrypto_test
+
+import (
+	"testing"
+
+	gormcrypto "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/blindindex"
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+	"gorm.io/gorm/clause"
+)
+
+type assignTestModel struct {
+	ID       uint
+	Email    cryptypes.EncryptedString
+	EmailIdx gormcrypto.BlindIndex `gormcrypto:"blind_index:Email;normalize:lower"`
+	Age      cryptypes.NullEncryptedInt
+	Notes    cryptypes.SignedEncryptedString `gormcrypto:"bind_context"`
+	Nickname string                          `gorm:"serializer:encrypted"`
+}
+
+func TestAssignments(t *testing.T) {
+	setup := newTestSetup(t, "AssignmentEncryptionKeyIs32Bytes", "AssignmentSigningKeyIs32BytesLg!")
+	indexer, err := blindindex.NewHMACSHA256("AssignmentIndexKeyIs32BytesLong!")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setup.BlindIndexer = indexer
+	db := openTestDB(t, "", newTestConfig(setup), &assignTestModel{})
+
+	if err := db.Model(&assignTestModel{}).Create(map[string]interface{}{"ID": 1, "Email": "alice@example.com", "age": 30, "Notes": "Alice", "nickname": "Al"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, column := range []string{"email", "age", "notes", "nickname"} {
+		var stored []byte
+		if err := db.Table("assign_test_models").Select(column).Where("id = ?", 1).Row().Scan(&stored); err != nil {
+			t.Fatal(err)
+		}
+		if !cryptypes.IsEnvelope(stored) {
+			t.Errorf("Expected %s to be encrypted; got %q instead", column, stored)
+		}
+	}
+
+	var alice assignTestModel
+	if err := db.First(&alice, 1).Error; err != nil {
+		t.Fatal(err)
+	}
+	if alice.Email.Raw != "alice@example.com" || alice.Age.Raw != 30 || alice.Notes.Raw != "Alice" || !alice.Notes.Valid || alice.Nickname != "Al" {
+		t.Errorf("Expected the created values; got %+v instead", alice)
+	}
+
+	older := int64(31)
+	if err := db.Model(&alice).Updates(map[string]interface{}{"email": "ALICE@example.org", "age": &older, "notes": "Still Alice", "nickname": "Ally"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if alice.Email.Raw != "ALICE@example.org" || alice.Age.Raw != 31 {
+		t.Errorf("Expected the model to hold the updated values; got %+v instead", alice)
+	}
+	if err := db.Model(&alice).Update("Email", "alice@example.net").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&alice).UpdateColumn("age", nil).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&alice).Where("id = ?", alice.ID).Clauses(clause.Set{{Column: clause.Column{Name: "nickname"}, Value: "Lissy"}}).Updates(map[string]interface{}{}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var actual assignTestModel
+	if err := db.Where(gormcrypto.BlindEq("Email", "ALICE@EXAMPLE.NET")).First(&actual).Error; err != nil {
+		t.Fatal(err)
+	}
+	if actual.Email.Raw != "alice@example.net" || actual.Notes.Raw != "Still Alice" || !actual.Notes.Valid || actual.Nickname != "Lissy" || !actual.Age.Empty {
+		t.Errorf("Expected the updated values; got %+v instead", actual)
+	}
+
+	var age interface{}
+	if err := db.Table("assign_test_models").Select("age").Where("id = ?", 1).Row().Scan(&age); err != nil || age != nil {
+		t.Errorf("Expected a NULL age; got %v (%v) instead", age, err)
+	}
+
+	if err := db.Model(&alice).Update("age", "thirty").Error; err == nil {
+		t.Error("Expected an error assigning a string to an encrypted int; got none")
+	}
+	if err := db.Model(&assignTestModel{}).Where("id > ?", 0).Update("notes", "Anyone").Error; err == nil {
+		t.Error("Expected an error assigning a row-bound field without a primary key; got none")
+	}
+	if err := db.First(&actual, 1).Error; err != nil {
+		t.Fatal(err)
+	}
+	if actual.Age.Raw != 0 || actual.Notes.Raw != "Still Alice" {
+		t.Errorf("Expected refused updates to leave the row alone; got %+v instead", actual)
+	}
+}
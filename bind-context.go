@@ -0,0 +1,153 @@
+package gormcrypto
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ContextBinder is implemented by values which can be bound to the row they're stored in, such as the encrypted types in the cryptypes package.
+// A Plugin binds fields tagged with `gormcrypto:"bind_context"` to the table, column, and primary key of their rows as they're written,
+// and those are used as associated data when encrypting, so a ciphertext copied into another row or column fails to decrypt.
+// Every field is bound to its row as it's read, so bound ciphertexts copied into untagged columns fail to decrypt too.
+// Binding requires an Encrypter which implements encryption.AEADAlgorithm or encryption.DataKeyAlgorithm.
+type ContextBinder interface {
+	// BindContext binds the value to the row it's stored in, using associated data such as that built by RowContext
+	BindContext(associated []byte)
+	// BoundContext returns the associated data the value is bound to, or nil if it isn't bound to a row
+	BoundContext() []byte
+}
+
+// ContextRequirer is implemented by values which can be told to refuse ciphertexts which aren't bound to their rows, such as the encrypted types in the cryptypes package.
+// A Plugin tells fields tagged with bind_context to, so unbound ciphertexts copied into them - from other rows, columns, or older values - fail to decrypt.
+// Tag them with `gormcrypto:"bind_context:migrate"` instead to accept values written before they were bound, until they're written again.
+type ContextRequirer interface {
+	// RequireContext sets whether the value refuses ciphertexts which aren't bound to its row
+	RequireContext(bool)
+}
+
+// RowContext builds the associated data which binds a value to the table, column, and primary key of the row it's stored in
+func RowContext(table, column string, primaryKeys ...interface{}) []byte {
+	var out bytes.Buffer
+	out.WriteString(table)
+	out.WriteByte(0)
+	out.WriteString(column)
+	for _, key := range primaryKeys {
+		out.WriteByte(0)
+		fmt.Fprint(&out, key)
+	}
+
+	return out.Bytes()
+}
+
+// PRIVATE
+
+var contextFieldCache sync.Map
+
+// contextFields returns the fields of a Schema tagged with bind_context, caching the result since GORM caches Schemas too
+func contextFields(s *schema.Schema) map[*schema.Field]bool {
+	if cached, ok := contextFieldCache.Load(s); ok {
+		return cached.(map[*schema.Field]bool)
+	}
+
+	fields := make(map[*schema.Field]bool)
+	for _, field := range s.Fields {
+		if _, ok := schema.ParseTagSetting(field.Tag.Get("gormcrypto"), ";")["BIND_CONTEXT"]; ok {
+			fields[field] = true
+		}
+	}
+	contextFieldCache.Store(s, fields)
+
+	return fields
+}
+
+// requireContext tells a field whether to refuse ciphertexts which aren't bound to its row, following its bind_context tag
+func requireContext(field *schema.Field, value interface{}) {
+	requirer, ok := value.(ContextRequirer)
+	if !ok {
+		return
+	}
+
+	setting, tagged := schema.ParseTagSetting(field.Tag.Get("gormcrypto"), ";")["BIND_CONTEXT"]
+	requirer.RequireContext(tagged && !strings.EqualFold(setting, "migrate"))
+}
+
+// rowContext builds the associated data for a field of a model value, using the Statement's table
+func rowContext(db *gorm.DB, field *schema.Field, model reflect.Value) []byte {
+	keys := make([]interface{}, len(db.Statement.Schema.PrimaryFields))
+	for i, primary := range db.Statement.Schema.PrimaryFields {
+		keys[i], _ = primary.ValueOf(db.Statement.Context, model)
+	}
+
+	return RowContext(db.Statement.Table, field.DBName, keys...)
+}
+
+// bindContext binds a field to its row, and reports whether its context changed.
+// Fields are only bound for writing if they're tagged with bind_context, but are always bound for reading.
+func bindContext(db *gorm.DB, field *schema.Field, model reflect.Value, value interface{}, reading bool) bool {
+	binder, ok := value.(ContextBinder)
+	if !ok {
+		return false
+	}
+
+	var context []byte
+	if reading || contextFields(db.Statement.Schema)[field] {
+		context = rowContext(db, field, model)
+	}
+	if bytes.Equal(binder.BoundContext(), context) {
+		return false
+	}
+	binder.BindContext(context)
+
+	return true
+}
+
+// rebindCreated re-encrypts context-bound fields of newly created rows whose primary keys were only assigned by the DB as they were inserted.
+// It runs inside the create's transaction, so rows are never committed with values bound to the wrong primary key.
+func (p *Plugin) rebindCreated(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil || db.Statement.Dest == nil || len(contextFields(db.Statement.Schema)) < 1 {
+		return
+	}
+
+	writes := writesField(db.Statement, true)
+	eachModel(db, reflect.ValueOf(db.Statement.Dest), func(model reflect.Value) {
+		updates := make(map[string]interface{})
+		for field := range contextFields(db.Statement.Schema) {
+			fieldValue := field.ReflectValueOf(db.Statement.Context, model)
+			if !fieldValue.CanAddr() || !writes(field, fieldValue.IsZero()) {
+				continue
+			}
+			if !bindContext(db, field, model, fieldValue.Addr().Interface(), false) {
+				continue
+			}
+
+			valuer, ok := fieldValue.Interface().(driver.Valuer)
+			if !ok {
+				continue
+			}
+			value, err := valuer.Value()
+			if err != nil {
+				db.AddError(err)
+				return
+			}
+			updates[field.DBName] = value
+		}
+		if len(updates) < 1 {
+			return
+		}
+
+		conditions := make([]clause.Expression, len(db.Statement.Schema.PrimaryFields))
+		for i, primary := range db.Statement.Schema.PrimaryFields {
+			key, _ := primary.ValueOf(db.Statement.Context, model)
+			conditions[i] = clause.Eq{Column: primary.DBName, Value: key}
+		}
+		db.AddError(db.Session(&gorm.Session{NewDB: true}).Table(db.Statement.Table).Where(clause.And(conditions...)).UpdateColumns(updates).Error)
+	})
+}
@@ -0,0 +1,143 @@
+package gormcrypto_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	gormcrypto "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+)
+
+type bindContextTestModel struct {
+	ID     uint
+	SSN    cryptypes.EncryptedString       `gormcrypto:"bind_context"`
+	Notes  cryptypes.SignedEncryptedString `gormcrypto:"bind_context"`
+	Public cryptypes.EncryptedString
+	Scan   cryptypes.EncryptedStream `gormcrypto:"bind_context"`
+}
+
+func TestBindContext(t *testing.T) {
+	db := openTestDB(t, "", newTestConfig(newTestSetup(t, "BindContextEncryptionKeyIs32Byte", "BindContextSigningKeyIs32BytesLg")), &bindContextTestModel{})
+
+	alice := bindContextTestModel{SSN: cryptypes.EncryptedString{Raw: "111-11-1111"}, Notes: cryptypes.SignedEncryptedString{Raw: "Alice"}}
+	alice.Scan.SetReader(strings.NewReader("Alice's scanned ID card"))
+	bob := bindContextTestModel{SSN: cryptypes.EncryptedString{Raw: "222-22-2222"}, Notes: cryptypes.SignedEncryptedString{Raw: "Bob"}}
+	if err := db.Create(&alice).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&bob).Error; err != nil {
+		t.Fatal(err)
+	}
+	if expected := gormcrypto.RowContext("bind_context_test_models", "ssn", alice.ID); !bytes.Equal(alice.SSN.BoundContext(), expected) {
+		t.Errorf("Expected %q; got %q instead", expected, alice.SSN.BoundContext())
+	}
+
+	var actual bindContextTestModel
+	if err := db.First(&actual, bob.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if actual.SSN.Raw != bob.SSN.Raw || actual.Notes.Raw != bob.Notes.Raw || !actual.Notes.Valid {
+		t.Errorf("Expected %v and valid %v; got %v and %v (valid = %v) instead", bob.SSN.Raw, bob.Notes.Raw, actual.SSN.Raw, actual.Notes.Raw, actual.Notes.Valid)
+	}
+
+	var withScan bindContextTestModel
+	if err := db.First(&withScan, alice.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if expected := gormcrypto.RowContext("bind_context_test_models", "scan", alice.ID); !bytes.Equal(withScan.Scan.BoundContext(), expected) {
+		t.Errorf("Expected %q; got %q instead", expected, withScan.Scan.BoundContext())
+	}
+	scanned, err := withScan.Scan.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card, _ := io.ReadAll(scanned); string(card) != "Alice's scanned ID card" {
+		t.Errorf("Expected %v; got %v instead", "Alice's scanned ID card", string(card))
+	}
+
+	bob.SSN.Raw = "333-33-3333"
+	if err := db.Save(&bob).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.First(&actual, bob.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if actual.SSN.Raw != bob.SSN.Raw {
+		t.Errorf("Expected %v; got %v instead", bob.SSN.Raw, actual.SSN.Raw)
+	}
+
+	var stolen []byte
+	if err := db.Table("bind_context_test_models").Select("ssn").Where("id = ?", alice.ID).Row().Scan(&stolen); err != nil {
+		t.Fatal(err)
+	}
+	for _, column := range []string{"ssn", "public"} {
+		if err := db.Exec("UPDATE bind_context_test_models SET "+column+" = ? WHERE id = ?", stolen, bob.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Select("ssn").First(&bindContextTestModel{}, bob.ID).Error; err == nil {
+		t.Error("Expected an error decrypting a value copied from another row; got none")
+	}
+	if err := db.Select("public").First(&bindContextTestModel{}, bob.ID).Error; err == nil {
+		t.Error("Expected an error decrypting a value copied from another column; got none")
+	}
+	if err := db.First(&bindContextTestModel{}, alice.ID).Error; err != nil {
+		t.Errorf("Expected the original row to still decrypt; got %v", err)
+	}
+}
+
+type bindContextMigrateTestModel struct {
+	ID     uint
+	SSN    cryptypes.EncryptedString `gormcrypto:"bind_context"`
+	Legacy cryptypes.EncryptedString `gormcrypto:"bind_context:migrate"`
+	Public cryptypes.EncryptedString
+}
+
+func TestBindContextUnbound(t *testing.T) {
+	db := openTestDB(t, "", newTestConfig(newTestSetup(t, "BindContextEncryptionKeyIs32Byte", "BindContextSigningKeyIs32BytesLg")), &bindContextMigrateTestModel{})
+
+	row := bindContextMigrateTestModel{SSN: cryptypes.EncryptedString{Raw: "111-11-1111"}, Public: cryptypes.EncryptedString{Raw: "444-44-4444"}}
+	if err := db.Create(&row).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var unbound []byte
+	if err := db.Table("bind_context_migrate_test_models").Select("public").Where("id = ?", row.ID).Row().Scan(&unbound); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec("UPDATE bind_context_migrate_test_models SET ssn = ?, legacy = ? WHERE id = ?", unbound, unbound, row.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.Select("ssn").First(&bindContextMigrateTestModel{}, row.ID).Error; !errors.Is(err, cryptypes.ErrContextUnbound) {
+		t.Errorf("Expected %v; got %v instead", cryptypes.ErrContextUnbound, err)
+	}
+
+	var migrating bindContextMigrateTestModel
+	if err := db.Select("id", "legacy").First(&migrating, row.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if migrating.Legacy.Raw != row.Public.Raw {
+		t.Errorf("Expected %v; got %v instead", row.Public.Raw, migrating.Legacy.Raw)
+	}
+
+	if err := db.Model(&migrating).Select("legacy").Updates(&migrating).Error; err != nil {
+		t.Fatal(err)
+	}
+	var rewritten []byte
+	if err := db.Table("bind_context_migrate_test_models").Select("legacy").Where("id = ?", row.ID).Row().Scan(&rewritten); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(rewritten, unbound) {
+		t.Error("Expected the migrating value to be bound to its row once written again; got the unbound value instead")
+	}
+	if err := db.Exec("UPDATE bind_context_migrate_test_models SET ssn = ? WHERE id = ?", rewritten, row.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Select("ssn").First(&bindContextMigrateTestModel{}, row.ID).Error; err == nil || errors.Is(err, cryptypes.ErrContextUnbound) {
+		t.Errorf("Expected an error decrypting a value bound to another column; got %v instead", err)
+	}
+}
@@ -0,0 +1,328 @@
+package gormcrypto
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// BlindIndex stores a blind index of another field's plaintext - a keyed, one-way digest of it - so that field can be searched with BlindEq,
+// or constrained by a unique index, without being decrypted or deterministically encrypted.
+// Declare one alongside the field it indexes, naming that field in its gormcrypto tag, and AutoMigrate will create its column.
+// The indexed field has to be BlindIndexable, as the encrypted types in the cryptypes package are:
+//
+//	Email      cryptypes.EncryptedString
+//	EmailIndex gormcrypto.BlindIndex `gormcrypto:"blind_index:Email;bytes:16;normalize:trim,lower"`
+//
+// The index is computed by the active Setup's BlindIndexer whenever the indexed field is created or updated, which requires a Plugin.
+// The optional bytes setting truncates the index, so that matching indexes no longer prove matching values, at the cost of some false positives.
+// The optional normalize setting lists the Normalizers applied to the plaintext, in order, before it's indexed.
+type BlindIndex []byte
+
+// BlindIndexable is implemented by values which can be blind indexed, such as the encrypted types in the cryptypes package
+type BlindIndexable interface {
+	// BlindIndexValue returns the plaintext value to index, or false if the value is null
+	BlindIndexValue() (interface{}, bool)
+}
+
+// Normalizer transforms plaintext before it's blind indexed, so that equivalent values produce the same index
+type Normalizer func(string) string
+
+// RegisterNormalizer adds a Normalizer to the internal normalizers map so it can be used in BlindIndex tags.
+// The lower, upper, trim, and digits Normalizers are always available.
+func RegisterNormalizer(name string, normalizer Normalizer) {
+	normalizersMutex.Lock()
+	defer normalizersMutex.Unlock()
+
+	normalizers[name] = normalizer
+}
+
+// Scan converts the value from the DB into a usable BlindIndex value
+func (b *BlindIndex) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*b = nil
+	case []byte:
+		*b = append(BlindIndex(nil), v...)
+	case string:
+		*b = BlindIndex(v)
+	default:
+		return fmt.Errorf("unable to scan %T into a BlindIndex", value)
+	}
+
+	return nil
+}
+
+// Value converts a BlindIndex value into a value that can be stored in the DB; a nil BlindIndex is stored as NULL
+func (b BlindIndex) Value() (driver.Value, error) {
+	if b == nil {
+		return nil, nil
+	}
+
+	return []byte(b), nil
+}
+
+// GormDataType indicates the default type hint for GORM to use in migrations
+func (BlindIndex) GormDataType() string {
+	return "bytes"
+}
+
+// GormDBDataType indicates the actual type hint for GORM to use in migrations, based on the connected server dialect.
+// Fixed-size binary types are used where possible, so the column can be part of an index.
+func (BlindIndex) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "bigquery":
+		return "BYTES"
+	case "clickhouse":
+		return "String"
+	case "mysql":
+		return "VARBINARY(64)"
+	case "postgres":
+		return "BYTEA"
+	case "sqlite":
+		return "BLOB"
+	case "sqlserver":
+		return "varbinary(64)"
+	}
+	return ""
+}
+
+// BlindEq builds a condition matching rows whose BlindIndex for the named field matches the given value.
+// The field can be given by name or by column, and the value either as plaintext or as a BlindIndexable value.
+// The value is indexed with every readable Setup's BlindIndexer, so rows indexed before a key rotation are still found.
+// Truncated indexes can match rows which hold other values, so check the decrypted results if that matters.
+func BlindEq(field string, value interface{}) clause.Expression {
+	return blindEq{field: field, value: value}
+}
+
+// PRIVATE
+
+var (
+	normalizersMutex sync.RWMutex
+	normalizers      = map[string]Normalizer{
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+		"trim":  strings.TrimSpace,
+		"digits": func(s string) string {
+			return strings.Map(func(r rune) rune {
+				if unicode.IsDigit(r) {
+					return r
+				}
+				return -1
+			}, s)
+		},
+	}
+
+	blindIndexSpecCache sync.Map
+)
+
+type blindEq struct {
+	field string
+	value interface{}
+}
+
+// Build writes the condition to the Statement
+func (e blindEq) Build(builder clause.Builder) {
+	stmt, ok := builder.(*gorm.Statement)
+	if !ok || stmt.Schema == nil {
+		builder.WriteString("1 = 0")
+		return
+	}
+
+	spec, err := findBlindIndex(stmt.Schema, e.field)
+	if err != nil {
+		stmt.AddError(err)
+		builder.WriteString("1 = 0")
+		return
+	}
+
+	builder.WriteQuoted(clause.Column{Table: clause.CurrentTable, Name: spec.field.DBName})
+
+	indexes, err := spec.candidates(ConfigFor(stmt.DB), e.value)
+	if err != nil {
+		stmt.AddError(err)
+	}
+	if indexes == nil {
+		builder.WriteString(" IS NULL")
+		return
+	}
+
+	builder.WriteString(" IN ")
+	builder.AddVar(builder, indexes)
+}
+
+// blindIndexSpec describes a BlindIndex field, as declared by its gormcrypto tag
+type blindIndexSpec struct {
+	field       *schema.Field
+	source      *schema.Field
+	size        int
+	normalizers []Normalizer
+}
+
+// blindIndexSpecs returns every BlindIndex field declared by a Schema, caching the result since GORM caches Schemas too
+func blindIndexSpecs(s *schema.Schema) ([]blindIndexSpec, error) {
+	if cached, ok := blindIndexSpecCache.Load(s); ok {
+		return cached.([]blindIndexSpec), nil
+	}
+
+	specs := make([]blindIndexSpec, 0)
+	for _, field := range s.Fields {
+		settings := schema.ParseTagSetting(field.Tag.Get("gormcrypto"), ";")
+		sourceName, ok := settings["BLIND_INDEX"]
+		if !ok {
+			continue
+		}
+
+		spec := blindIndexSpec{field: field, source: s.LookUpField(sourceName)}
+		if spec.source == nil {
+			return nil, fmt.Errorf("blind index %s.%s: no field named %q", s.Name, field.Name, sourceName)
+		}
+		if _, ok := reflect.New(spec.source.IndirectFieldType).Interface().(BlindIndexable); !ok {
+			return nil, fmt.Errorf("blind index %s.%s: field %s isn't BlindIndexable", s.Name, field.Name, spec.source.Name)
+		}
+		if size, ok := settings["BYTES"]; ok {
+			var err error
+			if spec.size, err = strconv.Atoi(size); err != nil || spec.size < 1 {
+				return nil, fmt.Errorf("blind index %s.%s: invalid size %q", s.Name, field.Name, size)
+			}
+		}
+		if names, ok := settings["NORMALIZE"]; ok {
+			normalizersMutex.RLock()
+			for _, name := range strings.Split(names, ",") {
+				normalizer, ok := normalizers[strings.TrimSpace(name)]
+				if !ok {
+					normalizersMutex.RUnlock()
+					return nil, fmt.Errorf("blind index %s.%s: unknown normalizer %q", s.Name, field.Name, name)
+				}
+				spec.normalizers = append(spec.normalizers, normalizer)
+			}
+			normalizersMutex.RUnlock()
+		}
+
+		specs = append(specs, spec)
+	}
+
+	blindIndexSpecCache.Store(s, specs)
+
+	return specs, nil
+}
+
+// findBlindIndex finds the BlindIndex for a field, given either the field's name or its column
+func findBlindIndex(s *schema.Schema, name string) (blindIndexSpec, error) {
+	specs, err := blindIndexSpecs(s)
+	if err != nil {
+		return blindIndexSpec{}, err
+	}
+
+	for _, spec := range specs {
+		if spec.source.Name == name || spec.source.DBName == name {
+			return spec, nil
+		}
+	}
+
+	return blindIndexSpec{}, fmt.Errorf("no blind index declared for %s.%s", s.Name, name)
+}
+
+// plaintext converts a value into the normalized bytes to index, or nil if the value is null
+func (spec blindIndexSpec) plaintext(value interface{}) []byte {
+	if indexable, ok := value.(BlindIndexable); ok {
+		if value, ok = indexable.BlindIndexValue(); !ok {
+			return nil
+		}
+	}
+
+	var plain string
+	switch v := value.(type) {
+	case []byte:
+		plain = string(v)
+	case string:
+		plain = v
+	case time.Time:
+		plain = v.UTC().Format(time.RFC3339Nano)
+	default:
+		plain = fmt.Sprint(v)
+	}
+	for _, normalizer := range spec.normalizers {
+		plain = normalizer(plain)
+	}
+
+	// The indexed column is mixed in, so equal values in different columns get unrelated indexes
+	return append([]byte(spec.source.DBName+"\x00"), plain...)
+}
+
+// index computes the BlindIndex of a value using a single Setup; null values have a nil BlindIndex
+func (spec blindIndexSpec) index(setup Setup, value interface{}) (BlindIndex, error) {
+	plain := spec.plaintext(value)
+	if plain == nil {
+		return nil, nil
+	}
+	defer keyconfig.Wipe(plain)
+	if setup.BlindIndexer == nil {
+		return nil, fmt.Errorf("setup %q has no blind indexer", setup.Identifier())
+	}
+
+	full, err := setup.BlindIndexer.Index(plain)
+	if err != nil {
+		return nil, err
+	}
+	if spec.size == 0 {
+		return full, nil
+	}
+	if spec.size > len(full) {
+		return nil, fmt.Errorf("blind index %s can't be truncated to %d bytes; it's only %d bytes long", spec.field.Name, spec.size, len(full))
+	}
+
+	return full[:spec.size], nil
+}
+
+// candidates computes the BlindIndex of a value under every readable Setup with a BlindIndexer, returning nil for null values
+func (spec blindIndexSpec) candidates(c Config, value interface{}) ([]interface{}, error) {
+	plain := spec.plaintext(value)
+	if plain == nil {
+		return nil, nil
+	}
+	keyconfig.Wipe(plain)
+
+	times := make([]time.Time, 0, len(c.Setups))
+	for t, setup := range c.Setups {
+		if setup.BlindIndexer != nil && setup.CanRead() {
+			times = append(times, t)
+		}
+	}
+	if len(times) < 1 {
+		return []interface{}{}, fmt.Errorf("no Setup can compute blind index %s", spec.field.Name)
+	}
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].After(times[j])
+	})
+
+	indexes := make([]interface{}, 0, len(times))
+	for _, t := range times {
+		index, err := spec.index(c.Setups[t], value)
+		if err != nil {
+			return []interface{}{}, err
+		}
+
+		duplicate := false
+		for _, existing := range indexes {
+			duplicate = duplicate || bytes.Equal(existing.([]byte), index)
+		}
+		if !duplicate {
+			indexes = append(indexes, []byte(index))
+		}
+	}
+
+	return indexes, nil
+}
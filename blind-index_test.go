@@ -0,0 +1,125 @@
+package gormcrypto_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gormcrypto "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/blindindex"
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+	"gorm.io/gorm"
+)
+
+type blindIndexTestModel struct {
+	ID       uint
+	Email    cryptypes.EncryptedString
+	EmailIdx gormcrypto.BlindIndex `gormcrypto:"blind_index:Email;normalize:trim,lower" gorm:"uniqueIndex"`
+	SSN      cryptypes.NullEncryptedString
+	SSNIdx   gormcrypto.BlindIndex `gormcrypto:"blind_index:SSN;bytes:4;normalize:digits"`
+}
+
+func TestBlindIndex(t *testing.T) {
+	db, path := openBlindIndexTestDB(t, "", "IndexKeyNumberOneThatIs32BytesLg")
+
+	expected := blindIndexTestModel{
+		Email: cryptypes.EncryptedString{Raw: "User@Example.com"},
+		SSN:   cryptypes.NullEncryptedString{Raw: "123-45-6789"},
+	}
+	if err := db.Create(&expected).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(expected.EmailIdx) != 32 || len(expected.SSNIdx) != 4 {
+		t.Errorf("Expected indexes of 32 and 4 bytes; got %d and %d instead", len(expected.EmailIdx), len(expected.SSNIdx))
+	}
+
+	var actual blindIndexTestModel
+	if err := db.Where(gormcrypto.BlindEq("Email", " user@example.COM")).First(&actual).Error; err != nil {
+		t.Fatal(err)
+	}
+	if actual.ID != expected.ID {
+		t.Errorf("Expected %v; got %v instead", expected.ID, actual.ID)
+	}
+	if err := db.Where(gormcrypto.BlindEq("ssn", cryptypes.NullEncryptedString{Raw: "123456789"})).First(&blindIndexTestModel{}).Error; err != nil {
+		t.Errorf("Expected to find the SSN by its truncated index; got %v", err)
+	}
+	if err := db.Where(gormcrypto.BlindEq("Email", "other@example.com")).First(&blindIndexTestModel{}).Error; err != gorm.ErrRecordNotFound {
+		t.Errorf("Expected %v; got %v instead", gorm.ErrRecordNotFound, err)
+	}
+
+	duplicate := blindIndexTestModel{Email: cryptypes.EncryptedString{Raw: "user@example.com"}}
+	if err := db.Create(&duplicate).Error; err == nil {
+		t.Error("Expected the unique index to reject a duplicate email; got no error")
+	}
+
+	if err := db.Model(&actual).Updates(blindIndexTestModel{Email: cryptypes.EncryptedString{Raw: "new@example.com"}}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Where(gormcrypto.BlindEq("Email", "new@example.com")).First(&blindIndexTestModel{}).Error; err != nil {
+		t.Errorf("Expected to find the updated email; got %v", err)
+	}
+	if err := db.Where(gormcrypto.BlindEq("SSN", "123-45-6789")).First(&blindIndexTestModel{}).Error; err != nil {
+		t.Errorf("Expected the SSN index to survive an update to the email; got %v", err)
+	}
+
+	empty := blindIndexTestModel{Email: cryptypes.EncryptedString{Raw: "empty@example.com"}, SSN: cryptypes.NullEncryptedString{Empty: true}}
+	if err := db.Create(&empty).Error; err != nil {
+		t.Fatal(err)
+	}
+	if empty.SSNIdx != nil {
+		t.Errorf("Expected no index for a null value; got %x instead", empty.SSNIdx)
+	}
+
+	if err := db.Where(gormcrypto.BlindEq("Name", "nobody")).First(&blindIndexTestModel{}).Error; err == nil {
+		t.Error("Expected an error for a field without a blind index; got none")
+	}
+
+	rotated, _ := openBlindIndexTestDB(t, path, "IndexKeyNumberOneThatIs32BytesLg", "IndexKeyNumberTwoThatIs32BytesLg")
+	rotatedValue := blindIndexTestModel{Email: cryptypes.EncryptedString{Raw: "rotated@example.com"}}
+	if err := rotated.Create(&rotatedValue).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, email := range []string{"new@example.com", "rotated@example.com"} {
+		if err := rotated.Where(gormcrypto.BlindEq("Email", email)).First(&blindIndexTestModel{}).Error; err != nil {
+			t.Errorf("Expected to find %v after rotating index keys; got %v", email, err)
+		}
+	}
+}
+
+type blindIndexPlainTestModel struct {
+	ID         uint
+	Profile    cryptypes.SignedString
+	ProfileIdx gormcrypto.BlindIndex `gormcrypto:"blind_index:Profile"`
+}
+
+func TestBlindIndexNotIndexable(t *testing.T) {
+	db, _ := openBlindIndexTestDB(t, "", "IndexKeyNumberOneThatIs32BytesLg")
+	if err := db.AutoMigrate(&blindIndexPlainTestModel{}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := db.Create(&blindIndexPlainTestModel{Profile: cryptypes.SignedString{Raw: "Test"}}).Error
+	if err == nil || !strings.Contains(err.Error(), "isn't BlindIndexable") {
+		t.Errorf("Expected an error for a field which isn't BlindIndexable; got %v instead", err)
+	}
+}
+
+func openBlindIndexTestDB(t *testing.T, path string, indexKeys ...string) (*gorm.DB, string) {
+	setups := make([]gormcrypto.Setup, 0, len(indexKeys))
+	for _, key := range indexKeys {
+		indexer, err := blindindex.NewHMACSHA256(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		setup := newTestSetup(t, "BlindIndexEncryptionKeyIs32Bytes", "BlindIndexSigningKeyIs32BytesToo")
+		setup.ID = indexer.Config()["key"].(string)[:8]
+		setup.BlindIndexer = indexer
+		setups = append(setups, setup)
+	}
+
+	if path == "" {
+		path = filepath.Join(t.TempDir(), "blind.db")
+	}
+
+	return openTestDB(t, path, newTestConfig(setups...), &blindIndexTestModel{}), path
+}
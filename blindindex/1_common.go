@@ -0,0 +1,63 @@
+// Package blindindex defines the various blind indexing Algorithms supported by the gormcrypto package
+package blindindex
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Algorithm defines an interface that blind indexing types must implement to be usable with gormcrypto.
+// A type implementing blindindex.Algorithm will derive a keyed, one-way digest from a value, which can be stored alongside its encrypted form
+// and searched for without decrypting anything.
+type Algorithm interface {
+	// Name identifies the Algorithm as a string for exporting configurations
+	Name() string
+	// Config converts an Algorthim's internal configuration into a map for export
+	Config() map[string]interface{}
+	// Index computes the full-length blind index of the provided data; the same data always produces the same index under the same key.
+	Index([]byte) ([]byte, error)
+}
+
+// Creator configures an Algorithm based on a configuration map, reporting any problems with that configuration
+type Creator func(map[string]interface{}) (Algorithm, error)
+
+// Register adds an Algorithm to the internal algos map so it can be used in YAML configs
+func Register(name string, creator Creator) {
+	algos[name] = creator
+}
+
+// SupportedAlgos returns a list of registered Algorithms that can be used in YAML configs
+func SupportedAlgos() []string {
+	keys := make([]string, 0, len(algos))
+	for k := range algos {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// FromYaml configures an Algorithm automatically based on a name and a configuration map.
+// It returns nil if the Algorithm can't be configured; use New to find out why.
+func FromYaml(name string, config map[string]interface{}) Algorithm {
+	algo, _ := New(name, config)
+
+	return algo
+}
+
+// New configures an Algorithm automatically based on a name and a configuration map, reporting any problems it finds
+func New(name string, config map[string]interface{}) (Algorithm, error) {
+	creator, ok := algos[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownAlgorithm, name)
+	}
+
+	return creator(config)
+}
+
+// ErrUnknownAlgorithm is returned by New when no Algorithm has been registered under the requested name
+var ErrUnknownAlgorithm = errors.New("unknown blind index algorithm")
+
+var algos map[string]Creator
+
+func init() {
+	algos = make(map[string]Creator, 0)
+}
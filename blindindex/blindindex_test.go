@@ -0,0 +1,86 @@
+package blindindex_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/danhunsaker/gorm-crypto/blindindex"
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
+)
+
+func TestIndex(t *testing.T) {
+	// RFC 4231, test case 6
+	key := strings.Repeat("\xaa", 131)
+	expected, _ := hex.DecodeString("60e431591ee0b67f0d8a26aacbf5b77f8e0bc6213728c5140546040f0ee37f54")
+
+	indexer, err := blindindex.NewHMACSHA256(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := indexer.Index([]byte("Test Using Larger Than Block-Size Key - Hash Key First"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("Expected %x; got %x instead", expected, actual)
+	}
+}
+
+func TestExports(t *testing.T) {
+	indexer, _ := blindindex.NewHMACSHA256("BlindIndexKeyThatShouldBe32Bytes")
+	name, config := indexer.Name(), indexer.Config()
+	created := blindindex.FromYaml(name, config)
+
+	if !reflect.DeepEqual(indexer, created) {
+		t.Errorf("Expected %v; got %v instead", indexer, created)
+	}
+	if !reflect.DeepEqual(created.Config(), config) {
+		t.Errorf("Expected %v; got %v instead", config, created.Config())
+	}
+}
+
+func TestDestroy(t *testing.T) {
+	key := []byte("BlindIndexKeyThatShouldBe32Bytes")
+	indexer, err := blindindex.NewHMACSHA256FromBytes(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	indexer.Destroy()
+
+	if _, err := indexer.Index([]byte("Test")); !errors.Is(err, keyconfig.ErrDestroyed) {
+		t.Errorf("Expected %v; got %v instead", keyconfig.ErrDestroyed, err)
+	}
+	if !bytes.Equal(key, make([]byte, len(key))) {
+		t.Errorf("Expected %v; got %v instead", make([]byte, len(key)), key)
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	tests := map[string]struct {
+		name     string
+		config   map[string]interface{}
+		expected error
+	}{
+		"unknown":  {"bogus", nil, blindindex.ErrUnknownAlgorithm},
+		"missing":  {"hmacsha256", map[string]interface{}{}, keyconfig.ErrMissingKey},
+		"encoding": {"hmacsha256", map[string]interface{}{"key": "not hex"}, keyconfig.ErrKeyEncoding},
+		"length":   {"hmacsha256", map[string]interface{}{"key": "abcdef"}, keyconfig.ErrKeyLength},
+	}
+
+	for label, test := range tests {
+		t.Run(label, func(t *testing.T) {
+			algo, err := blindindex.New(test.name, test.config)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("Expected %v; got %v instead", test.expected, err)
+			}
+			if algo != nil {
+				t.Errorf("Expected nil; got %v instead", algo)
+			}
+		})
+	}
+}
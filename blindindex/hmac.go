@@ -0,0 +1,88 @@
+package blindindex
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
+)
+
+func init() {
+	Register("hmacsha256", func(m map[string]interface{}) (Algorithm, error) {
+		key, err := keyconfig.Hex(m, "key")
+		if err != nil {
+			return nil, err
+		}
+		if len(key) < MinKeySize {
+			return nil, keyconfig.Errorf("key", "%w: expected at least %d bytes; got %d instead", keyconfig.ErrKeyLength, MinKeySize, len(key))
+		}
+
+		return NewHMACSHA256FromBytes(key)
+	})
+}
+
+// MinKeySize is the shortest key HMACSHA256 accepts
+const MinKeySize = 32
+
+// HMACSHA256 supports blind indexes computed with HMAC-SHA256, which are 32 bytes long before any truncation
+type HMACSHA256 struct {
+	Algorithm
+	key         []byte
+	fingerprint []byte
+}
+
+// Name identifies the Algorithm as a string for exporting configurations
+func (HMACSHA256) Name() string {
+	return "hmacsha256"
+}
+
+// Config converts an Algorthim's internal configuration into a map for export
+func (b HMACSHA256) Config() map[string]interface{} {
+	if b.key == nil {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"key": hex.EncodeToString(b.key),
+	}
+}
+
+// KeyFingerprint returns a one-way hash of the key, computed as the Algorithm was created
+func (b HMACSHA256) KeyFingerprint() []byte {
+	return b.fingerprint
+}
+
+// NewHMACSHA256 creates a new HMACSHA256 value
+func NewHMACSHA256(key string) (*HMACSHA256, error) {
+	return NewHMACSHA256FromBytes([]byte(key))
+}
+
+// NewHMACSHA256FromBytes creates a new HMACSHA256 value.
+// The key is kept rather than copied, so that Destroy can wipe it.
+func NewHMACSHA256FromBytes(key []byte) (*HMACSHA256, error) {
+	if len(key) < MinKeySize {
+		return nil, errors.New("key length MUST be at least 32 bytes for HMACSHA256")
+	}
+
+	return &HMACSHA256{key: key, fingerprint: keyconfig.Fingerprint(key)}, nil
+}
+
+// Index computes the full-length blind index of the provided data
+func (b *HMACSHA256) Index(data []byte) ([]byte, error) {
+	if b.key == nil {
+		return nil, keyconfig.ErrDestroyed
+	}
+
+	mac := hmac.New(sha256.New, b.key)
+	mac.Write(data)
+
+	return mac.Sum(nil), nil
+}
+
+// Destroy wipes the key, after which the HMACSHA256 value refuses to compute any more indexes
+func (b *HMACSHA256) Destroy() {
+	keyconfig.Wipe(b.key)
+	b.key = nil
+}
@@ -0,0 +1,154 @@
+// Package compression defines the various compression Algorithms supported by the gormcrypto package
+package compression
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
+)
+
+// Algorithm defines an interface that compression types must implement to be usable with gormcrypto.
+// A type implementing compression.Algorithm will shrink a value's serialized representation before it's encrypted, and restore it after it's decrypted.
+// The types implemented here wrap the Go standard library's various compress packages.
+type Algorithm interface {
+	// Name identifies the Algorithm as a string for exporting configurations
+	Name() string
+	// Config converts an Algorthim's internal configuration into a map for export
+	Config() map[string]interface{}
+	// MinSize returns the size, in bytes, below which values aren't worth compressing, and are stored as-is.
+	MinSize() int
+	// Compress transforms a serialized value into its compressed representation.
+	Compress([]byte) ([]byte, error)
+	// Decompress transforms a compressed representation back into the serialized value.
+	Decompress([]byte) ([]byte, error)
+}
+
+// Creator configures an Algorithm based on a configuration map, reporting any problems with that configuration
+type Creator func(map[string]interface{}) (Algorithm, error)
+
+// RegisterAlgo adds an Algorithm to the internal algos map so it can be used in YAML configs
+func RegisterAlgo(name string, creator func(map[string]interface{}) Algorithm) {
+	Register(name, func(m map[string]interface{}) (Algorithm, error) {
+		return creator(m), nil
+	})
+}
+
+// Register adds an Algorithm to the internal algos map so it can be used in YAML configs, using a Creator which can report errors
+func Register(name string, creator Creator) {
+	algos[name] = creator
+}
+
+// SupportedAlgos returns a list of registered Algorithms that can be used in YAML configs
+func SupportedAlgos() []string {
+	keys := make([]string, 0, len(algos))
+	for k := range algos {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// FromYaml configures an Algorithm automatically based on a name and a configuration map.
+// It returns nil if the Algorithm can't be configured; use New to find out why.
+func FromYaml(name string, config map[string]interface{}) Algorithm {
+	algo, _ := New(name, config)
+
+	return algo
+}
+
+// New configures an Algorithm automatically based on a name and a configuration map, reporting any problems it finds
+func New(name string, config map[string]interface{}) (Algorithm, error) {
+	creator, ok := algos[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownAlgorithm, name)
+	}
+
+	return creator(config)
+}
+
+// ErrUnknownAlgorithm is returned by New when no Algorithm has been registered under the requested name
+var ErrUnknownAlgorithm = errors.New("unknown compression algorithm")
+
+var algos map[string]Creator
+
+func init() {
+	algos = make(map[string]Creator, 0)
+}
+
+// PRIVATE
+
+// settings reads the level and threshold shared by every Algorithm here from a configuration map
+func settings(m map[string]interface{}) (level int, threshold int, err error) {
+	if level, err = intSetting(m, "level"); err != nil {
+		return
+	}
+	if level < flate.HuffmanOnly || level > flate.BestCompression {
+		return 0, 0, keyconfig.Errorf("level", "expected a level from %d to %d; got %d instead", flate.HuffmanOnly, flate.BestCompression, level)
+	}
+
+	if threshold, err = intSetting(m, "threshold"); err != nil {
+		return
+	}
+	if threshold < 0 {
+		return 0, 0, keyconfig.Errorf("threshold", "expected a size of at least 0; got %d instead", threshold)
+	}
+
+	return
+}
+
+func intSetting(m map[string]interface{}, field string) (int, error) {
+	switch v := m[field].(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case uint64:
+		return int(v), nil
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), nil
+		}
+	}
+
+	return 0, keyconfig.Errorf(field, "expected an integer; got %v instead", m[field])
+}
+
+// levelOrDefault maps the zero level to the default compression level, since storing values uncompressed is what MinSize is for
+func levelOrDefault(level int) int {
+	if level == 0 {
+		return flate.DefaultCompression
+	}
+
+	return level
+}
+
+func compress(data []byte, writer func(io.Writer) (io.WriteCloser, error)) ([]byte, error) {
+	var out bytes.Buffer
+
+	w, err := writer(&out)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+func decompress(r io.ReadCloser, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
@@ -0,0 +1,103 @@
+package compression_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/danhunsaker/gorm-crypto/compression"
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
+)
+
+func TestCompression(t *testing.T) {
+	expected := bytes.Repeat([]byte("gormcrypto compresses repetitive values well. "), 32)
+
+	for _, compressor := range getAlgos() {
+		t.Run(reflect.TypeOf(compressor).String(), func(t *testing.T) {
+			compressed, err := compressor.Compress(expected)
+			if err != nil {
+				t.Error(err)
+			}
+			if len(compressed) >= len(expected) {
+				t.Errorf("Expected fewer than %d bytes; got %d instead", len(expected), len(compressed))
+			}
+
+			actual, err := compressor.Decompress(compressed)
+			if err != nil {
+				t.Error(err)
+			}
+			if !bytes.Equal(actual, expected) {
+				t.Errorf("Expected %v; got %v instead", expected, actual)
+			}
+
+			if _, err := compressor.Decompress([]byte("not compressed")); err == nil {
+				t.Error("Expected an error decompressing invalid data; got none")
+			}
+		})
+	}
+}
+
+func TestExports(t *testing.T) {
+	for _, compressor := range getAlgos() {
+		t.Run(reflect.TypeOf(compressor).String(), func(t *testing.T) {
+			name, config := compressor.Name(), compressor.Config()
+			created := compression.FromYaml(name, config)
+
+			if !reflect.DeepEqual(compressor, created) {
+				t.Errorf("Expected %v; got %v instead", compressor, created)
+			}
+			if created.Name() != name {
+				t.Errorf("Expected %v; got %v instead", name, created.Name())
+			}
+			if !reflect.DeepEqual(created.Config(), config) {
+				t.Errorf("Expected %v; got %v instead", config, created.Config())
+			}
+		})
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	for _, config := range []map[string]interface{}{
+		{"level": 10},
+		{"level": "best"},
+		{"threshold": -1},
+		{"threshold": 1.5},
+	} {
+		if _, err := compression.New("gzip", config); !errors.As(err, new(*keyconfig.FieldError)) {
+			t.Errorf("Expected a FieldError for %v; got %v instead", config, err)
+		}
+	}
+	if _, err := compression.New("nope", nil); !errors.Is(err, compression.ErrUnknownAlgorithm) {
+		t.Errorf("Expected %v; got %v instead", compression.ErrUnknownAlgorithm, err)
+	}
+}
+
+func TestAlgoSupportFuncs(t *testing.T) {
+	expected := append(compression.SupportedAlgos(), "test")
+	sort.Slice(expected, func(i, j int) bool {
+		return expected[i] < expected[j]
+	})
+
+	compression.RegisterAlgo("test", func(m map[string]interface{}) compression.Algorithm {
+		return nil
+	})
+
+	actual := compression.SupportedAlgos()
+	sort.Slice(actual, func(i, j int) bool {
+		return actual[i] < actual[j]
+	})
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v; got %v instead", expected, actual)
+	}
+}
+
+func getAlgos() []compression.Algorithm {
+	return []compression.Algorithm{
+		compression.Flate{Level: 9, Threshold: 0},
+		compression.Gzip{Level: 0, Threshold: 64},
+		compression.Zlib{Level: -1, Threshold: 128},
+	}
+}
@@ -0,0 +1,57 @@
+package compression
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+)
+
+func init() {
+	Register("flate", func(m map[string]interface{}) (Algorithm, error) {
+		level, threshold, err := settings(m)
+		if err != nil {
+			return nil, err
+		}
+
+		return Flate{Level: level, Threshold: threshold}, nil
+	})
+}
+
+// Flate supports flate (RFC 1951) compression of arbitrary data
+type Flate struct {
+	Algorithm
+	// Level is the compression level, from flate.HuffmanOnly to flate.BestCompression; zero means flate.DefaultCompression
+	Level int
+	// Threshold is the size, in bytes, below which values are stored uncompressed
+	Threshold int
+}
+
+// Name identifies the Algorithm as a string for exporting configurations
+func (Flate) Name() string {
+	return "flate"
+}
+
+// Config converts an Algorthim's internal configuration into a map for export
+func (c Flate) Config() map[string]interface{} {
+	return map[string]interface{}{
+		"level":     c.Level,
+		"threshold": c.Threshold,
+	}
+}
+
+// MinSize returns the size, in bytes, below which values are stored uncompressed
+func (c Flate) MinSize() int {
+	return c.Threshold
+}
+
+// Compress ::: Flate
+func (c Flate) Compress(raw []byte) ([]byte, error) {
+	return compress(raw, func(w io.Writer) (io.WriteCloser, error) {
+		return flate.NewWriter(w, levelOrDefault(c.Level))
+	})
+}
+
+// Decompress ::: Flate
+func (Flate) Decompress(compressed []byte) ([]byte, error) {
+	return decompress(flate.NewReader(bytes.NewReader(compressed)), nil)
+}
@@ -0,0 +1,64 @@
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+func init() {
+	Register("gzip", func(m map[string]interface{}) (Algorithm, error) {
+		level, threshold, err := settings(m)
+		if err != nil {
+			return nil, err
+		}
+
+		return Gzip{Level: level, Threshold: threshold}, nil
+	})
+}
+
+// Gzip supports gzip (RFC 1952) compression of arbitrary data
+type Gzip struct {
+	Algorithm
+	// Level is the compression level, from flate.HuffmanOnly to flate.BestCompression; zero means flate.DefaultCompression
+	Level int
+	// Threshold is the size, in bytes, below which values are stored uncompressed
+	Threshold int
+}
+
+// Name identifies the Algorithm as a string for exporting configurations
+func (Gzip) Name() string {
+	return "gzip"
+}
+
+// Config converts an Algorthim's internal configuration into a map for export
+func (c Gzip) Config() map[string]interface{} {
+	return map[string]interface{}{
+		"level":     c.Level,
+		"threshold": c.Threshold,
+	}
+}
+
+// MinSize returns the size, in bytes, below which values are stored uncompressed
+func (c Gzip) MinSize() int {
+	return c.Threshold
+}
+
+// Compress ::: Gzip
+func (c Gzip) Compress(raw []byte) ([]byte, error) {
+	return compress(raw, func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriterLevel(w, levelOrDefault(c.Level))
+	})
+}
+
+// Decompress ::: Gzip
+func (Gzip) Decompress(compressed []byte) ([]byte, error) {
+	return decompress(gzipReader(compressed))
+}
+
+// PRIVATE
+
+// gzipReader adapts gzip.NewReader to return an io.ReadCloser, as the other readers do
+func gzipReader(compressed []byte) (io.ReadCloser, error) {
+	return gzip.NewReader(bytes.NewReader(compressed))
+}
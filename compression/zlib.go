@@ -0,0 +1,57 @@
+package compression
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+)
+
+func init() {
+	Register("zlib", func(m map[string]interface{}) (Algorithm, error) {
+		level, threshold, err := settings(m)
+		if err != nil {
+			return nil, err
+		}
+
+		return Zlib{Level: level, Threshold: threshold}, nil
+	})
+}
+
+// Zlib supports zlib (RFC 1950) compression of arbitrary data
+type Zlib struct {
+	Algorithm
+	// Level is the compression level, from flate.HuffmanOnly to flate.BestCompression; zero means flate.DefaultCompression
+	Level int
+	// Threshold is the size, in bytes, below which values are stored uncompressed
+	Threshold int
+}
+
+// Name identifies the Algorithm as a string for exporting configurations
+func (Zlib) Name() string {
+	return "zlib"
+}
+
+// Config converts an Algorthim's internal configuration into a map for export
+func (c Zlib) Config() map[string]interface{} {
+	return map[string]interface{}{
+		"level":     c.Level,
+		"threshold": c.Threshold,
+	}
+}
+
+// MinSize returns the size, in bytes, below which values are stored uncompressed
+func (c Zlib) MinSize() int {
+	return c.Threshold
+}
+
+// Compress ::: Zlib
+func (c Zlib) Compress(raw []byte) ([]byte, error) {
+	return compress(raw, func(w io.Writer) (io.WriteCloser, error) {
+		return zlib.NewWriterLevel(w, levelOrDefault(c.Level))
+	})
+}
+
+// Decompress ::: Zlib
+func (Zlib) Decompress(compressed []byte) ([]byte, error) {
+	return decompress(zlib.NewReader(bytes.NewReader(compressed)))
+}
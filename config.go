@@ -0,0 +1,283 @@
+package gormcrypto
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/danhunsaker/gorm-crypto/blindindex"
+	"github.com/danhunsaker/gorm-crypto/compression"
+	"github.com/danhunsaker/gorm-crypto/encoding"
+	"github.com/danhunsaker/gorm-crypto/encryption"
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
+	"github.com/danhunsaker/gorm-crypto/keywrap"
+	"github.com/danhunsaker/gorm-crypto/serializing"
+	"github.com/danhunsaker/gorm-crypto/signing"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNoSetups is reported by LoadConfig when a configuration doesn't define any Setups at all
+var ErrNoSetups = errors.New("no setups defined")
+
+// ErrDuplicateID is reported by LoadConfig when more than one Setup claims the same ID
+var ErrDuplicateID = errors.New("duplicate setup id")
+
+// ErrNotDeterministic is reported by LoadConfig when a Setup's deterministic_encryption algorithm isn't deterministic
+var ErrNotDeterministic = errors.New("encryption algorithm is not deterministic")
+
+// ConfigError describes a single problem found while loading a configuration.
+// Path locates the problem within the YAML, starting with the Setup's timestamp, such as 2021-01-01T00:00:00Z.encryption.config.key
+type ConfigError struct {
+	Path string
+	Err  error
+}
+
+// Error describes the problem, prefixed by where it was found
+func (e *ConfigError) Error() string {
+	if e.Path == "" {
+		return e.Err.Error()
+	}
+
+	return e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap exposes the underlying problem to errors.Is and errors.As
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// ConfigErrors collects every problem LoadConfig found, so they can all be fixed at once
+type ConfigErrors []*ConfigError
+
+// Error describes every problem found, one after the other
+func (e ConfigErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Is reports whether any problem found matches the target, for errors.Is
+func (e ConfigErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first problem found which matches the target, for errors.As
+func (e ConfigErrors) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Unwrap exposes each problem found to errors.Is and errors.As, from Go 1.20 onwards
+func (e ConfigErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+
+	return errs
+}
+
+// Option changes how LoadConfig reads a configuration, or how ConfigToBytes writes one
+type Option func(*options)
+
+// WithResolver sets the Resolver used to look up keys given as References, such as key_env, key_file, or key_secret.
+// By default, a keyconfig.DefaultResolver reading secrets from keyconfig.DefaultSecretDir is used.
+func WithResolver(resolver keyconfig.Resolver) Option {
+	return func(o *options) {
+		o.resolver = resolver
+	}
+}
+
+// WithKeyWrapper sets the keywrap.Wrapper used to protect keys in exported configurations, and to unwrap them again when loading
+func WithKeyWrapper(wrapper keywrap.Wrapper) Option {
+	return func(o *options) {
+		o.wrapper = wrapper
+	}
+}
+
+// WithSecretDir sets the directory the default Resolver reads key_secret References from
+func WithSecretDir(dir string) Option {
+	return WithResolver(keyconfig.DefaultResolver{SecretDir: dir})
+}
+
+// LoadConfig parses a YAML configuration into a Config, reporting every problem it finds along the way.
+// Problems with the Setups themselves are reported together as ConfigErrors.
+func LoadConfig(contents []byte, options ...Option) (Config, error) {
+	opts := newOptions(options)
+
+	var parsed yamlContents
+	if err := yaml.Unmarshal(contents, &parsed); err != nil {
+		return Config{}, &ConfigError{Err: err}
+	}
+
+	times := make([]time.Time, 0, len(parsed))
+	for setupTime := range parsed {
+		times = append(times, setupTime)
+	}
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].Before(times[j])
+	})
+
+	var errs ConfigErrors
+	report := func(path string, err error) {
+		errs = append(errs, &ConfigError{Path: path, Err: err})
+	}
+
+	c := Config{Setups: make(map[time.Time]Setup, len(parsed))}
+	ids := make(map[string]time.Time, len(parsed))
+	for _, setupTime := range times {
+		setupValue := parsed[setupTime]
+		prefix := setupTime.Format(time.RFC3339Nano)
+		setup := Setup{
+			ID:        setupValue.ID,
+			State:     setupValue.State,
+			Storage:   setupValue.Storage,
+			NotBefore: setupValue.NotBefore,
+			NotAfter:  setupValue.NotAfter,
+		}
+
+		if setup.ID != "" {
+			if first, ok := ids[setup.ID]; ok {
+				report(prefix+".id", fmt.Errorf("%w %q; already used by %s", ErrDuplicateID, setup.ID, first.Format(time.RFC3339Nano)))
+			}
+			ids[setup.ID] = setupTime
+		}
+
+		components := []setupComponent{
+			{"encoding", setupValue.Encoding, func(name string, config map[string]interface{}) (err error) {
+				setup.Encoder, err = encoding.New(name, config)
+				return
+			}},
+			{"serializing", setupValue.Serializing, func(name string, config map[string]interface{}) (err error) {
+				setup.Serializer, err = serializing.New(name, config)
+				return
+			}},
+			{"encryption", setupValue.Encryption, func(name string, config map[string]interface{}) (err error) {
+				setup.Encrypter, err = encryption.New(name, config)
+				return
+			}},
+			{"signing", setupValue.Signing, func(name string, config map[string]interface{}) (err error) {
+				setup.Signer, err = signing.New(name, config)
+				return
+			}},
+		}
+		if setupValue.DeterministicEncryption != nil {
+			components = append(components, setupComponent{"deterministic_encryption", *setupValue.DeterministicEncryption, func(name string, config map[string]interface{}) error {
+				algo, err := encryption.New(name, config)
+				if err != nil {
+					return err
+				}
+
+				deterministic, ok := algo.(encryption.DeterministicAlgorithm)
+				if !ok {
+					return fmt.Errorf("%w: %s", ErrNotDeterministic, name)
+				}
+				setup.DeterministicEncrypter = deterministic
+
+				return nil
+			}})
+		}
+		if setupValue.BlindIndex != nil {
+			components = append(components, setupComponent{"blind_index", *setupValue.BlindIndex, func(name string, config map[string]interface{}) (err error) {
+				setup.BlindIndexer, err = blindindex.New(name, config)
+				return
+			}})
+		}
+		if setupValue.Compression != nil {
+			components = append(components, setupComponent{"compression", *setupValue.Compression, func(name string, config map[string]interface{}) (err error) {
+				setup.Compressor, err = compression.New(name, config)
+				return
+			}})
+		}
+		for _, component := range components {
+			path := prefix + "." + component.name
+
+			config, err := keywrap.UnwrapConfig(component.algo.Config, opts.wrapper, path)
+			if err != nil {
+				report(componentPath(path, err))
+				continue
+			}
+			config, refs, err := keyconfig.Resolve(config, opts.resolver)
+			if err != nil {
+				report(componentPath(path, err))
+				continue
+			}
+			if len(refs) > 0 {
+				if setup.References == nil {
+					setup.References = make(map[string][]keyconfig.Reference)
+				}
+				setup.References[component.name] = refs
+			}
+
+			if err := component.create(component.algo.Algorithm, config); err != nil {
+				report(componentPath(path, err))
+			}
+		}
+
+		c.Setups[setupTime] = setup
+	}
+
+	if len(c.Setups) < 1 {
+		report("", ErrNoSetups)
+	}
+	if len(errs) > 0 {
+		return Config{}, errs
+	}
+
+	return c, nil
+}
+
+// PRIVATE
+
+// setupComponent is a single algorithm of a Setup being loaded, along with where it's found in the YAML and how to create it
+type setupComponent struct {
+	name   string
+	algo   yamlSetupAlgorithm
+	create func(string, map[string]interface{}) error
+}
+
+type options struct {
+	resolver keyconfig.Resolver
+	wrapper  keywrap.Wrapper
+}
+
+func newOptions(given []Option) options {
+	opts := options{resolver: keyconfig.DefaultResolver{}}
+	for _, option := range given {
+		option(&opts)
+	}
+
+	return opts
+}
+
+// componentPath extends the path to a Setup component so it points at the part of the component the error is about
+func componentPath(path string, err error) (string, error) {
+	var field *keyconfig.FieldError
+	if errors.As(err, &field) {
+		return path + ".config." + field.Field, field.Err
+	}
+
+	for _, unknown := range []error{encoding.ErrUnknownAlgorithm, serializing.ErrUnknownAlgorithm, encryption.ErrUnknownAlgorithm, signing.ErrUnknownAlgorithm, blindindex.ErrUnknownAlgorithm, compression.ErrUnknownAlgorithm, ErrNotDeterministic} {
+		if errors.Is(err, unknown) {
+			return path + ".algorithm", err
+		}
+	}
+
+	return path, err
+}
@@ -0,0 +1,249 @@
+package gormcrypto_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	gormcrypto "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/encryption"
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
+	"github.com/danhunsaker/gorm-crypto/keywrap"
+)
+
+func TestLoadConfig(t *testing.T) {
+	config := getTestConfig()
+
+	yaml, err := config.ConfigToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := gormcrypto.LoadConfig(yaml)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(loaded, config) {
+		t.Errorf("Expected %v; got %v instead", config, loaded)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	const setup = `
+  encoding:
+    algorithm: hex
+  serializing:
+    algorithm: json
+`
+	tests := map[string]struct {
+		yaml     string
+		path     string
+		expected error
+	}{
+		"empty": {"{}", "", gormcrypto.ErrNoSetups},
+		"unknown": {"2021-01-01T00:00:00Z:" + setup + `
+  encryption:
+    algorithm: rot13
+  signing:
+    algorithm: ed25519
+    config:
+      key: abababababababababababababababababababababababababababababababab
+`, "2021-01-01T00:00:00Z.encryption.algorithm", encryption.ErrUnknownAlgorithm},
+		"missing": {"2021-01-01T00:00:00Z:" + setup + `
+  encryption:
+    algorithm: aes256gcm
+  signing:
+    algorithm: ed25519
+    config:
+      key: abababababababababababababababababababababababababababababababab
+`, "2021-01-01T00:00:00Z.encryption.config.key", keyconfig.ErrMissingKey},
+		"length": {"2021-01-01T00:00:00Z:" + setup + `
+  encryption:
+    algorithm: aes256gcm
+    config:
+      key: abababababababababababababababababababababababababababababababab
+  signing:
+    algorithm: ed25519
+    config:
+      key: abab
+`, "2021-01-01T00:00:00Z.signing.config.key", keyconfig.ErrKeyLength},
+		"deterministic": {"2021-01-01T00:00:00Z:" + setup + `
+  encryption:
+    algorithm: aes256gcm
+    config:
+      key: abababababababababababababababababababababababababababababababab
+  deterministic_encryption:
+    algorithm: aes256gcm
+    config:
+      key: abababababababababababababababababababababababababababababababab
+  signing:
+    algorithm: ed25519
+    config:
+      key: abababababababababababababababababababababababababababababababab
+`, "2021-01-01T00:00:00Z.deterministic_encryption.algorithm", gormcrypto.ErrNotDeterministic},
+		"encoding": {"2021-01-01T00:00:00Z:" + setup + `
+  encryption:
+    algorithm: aes256gcm
+    config:
+      key: not hex
+  signing:
+    algorithm: ed25519
+    config:
+      key: abababababababababababababababababababababababababababababababab
+`, "2021-01-01T00:00:00Z.encryption.config.key", keyconfig.ErrKeyEncoding},
+	}
+
+	for label, test := range tests {
+		t.Run(label, func(t *testing.T) {
+			_, err := gormcrypto.LoadConfig([]byte(test.yaml))
+			if !errors.Is(err, test.expected) {
+				t.Errorf("Expected %v; got %v instead", test.expected, err)
+			}
+
+			var configErr *gormcrypto.ConfigError
+			if !errors.As(err, &configErr) {
+				t.Fatalf("Expected a ConfigError; got %T instead", err)
+			}
+			if configErr.Path != test.path {
+				t.Errorf("Expected %v; got %v instead", test.path, configErr.Path)
+			}
+
+			if imported := gormcrypto.ConfigFromBytes([]byte(test.yaml)); len(imported.Setups) > 0 {
+				t.Errorf("Expected an empty Config; got %v instead", imported)
+			}
+		})
+	}
+
+	if _, err := gormcrypto.LoadConfig([]byte("- not: a map")); err == nil {
+		t.Error("Expected an error for invalid YAML; got none")
+	}
+}
+
+func TestLoadConfigDuplicateIDs(t *testing.T) {
+	config := getTestConfig()
+	for key, setup := range config.Setups {
+		setup.ID = "same"
+		config.Setups[key] = setup
+	}
+
+	yaml, err := config.ConfigToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = gormcrypto.LoadConfig(yaml)
+
+	var errs gormcrypto.ConfigErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected ConfigErrors; got %T instead", err)
+	}
+	if len(errs) != len(config.Setups)-1 {
+		t.Errorf("Expected %d errors; got %v instead", len(config.Setups)-1, errs)
+	}
+	if !errors.Is(err, gormcrypto.ErrDuplicateID) {
+		t.Errorf("Expected %v; got %v instead", gormcrypto.ErrDuplicateID, err)
+	}
+	// Go releases before 1.20 ignore Unwrap() []error, so ConfigErrors has to match by itself
+	if !errs.Is(gormcrypto.ErrDuplicateID) {
+		t.Errorf("Expected ConfigErrors to match %v; got %v instead", gormcrypto.ErrDuplicateID, errs)
+	}
+	var single *gormcrypto.ConfigError
+	if !errs.As(&single) || !errors.Is(single, gormcrypto.ErrDuplicateID) {
+		t.Errorf("Expected ConfigErrors to yield a *ConfigError; got %v instead", single)
+	}
+}
+
+func TestLoadConfigReferences(t *testing.T) {
+	const encryptionKey = "456e6372797074696f6e4b65795468617453686f756c64427933324279746573"
+	const signingKey = "5369676e696e674b65795468617453686f756c64426533324279746573546f6f"
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "signing-key"), []byte(signingKey+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GC_TEST_AES_KEY", encryptionKey)
+
+	contents := []byte(`"2021-01-01T00:00:00Z":
+  encoding:
+    algorithm: hex
+  serializing:
+    algorithm: json
+  encryption:
+    algorithm: aes256gcm
+    config:
+      key_env: GC_TEST_AES_KEY
+  signing:
+    algorithm: ed25519
+    config:
+      key_secret: signing-key
+`)
+	config, err := gormcrypto.LoadConfig(contents, gormcrypto.WithSecretDir(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	setup := config.CurrentSetup()
+	if key := setup.Encrypter.Config()["key"]; key != encryptionKey {
+		t.Errorf("Expected %v; got %v instead", encryptionKey, key)
+	}
+	if key := setup.Signer.Config()["key"]; key != signingKey {
+		t.Errorf("Expected %v; got %v instead", signingKey, key)
+	}
+
+	exported, err := config.ConfigToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(exported, []byte(encryptionKey)) || bytes.Contains(exported, []byte(signingKey)) {
+		t.Errorf("Expected exported config to hold references only; got %s instead", exported)
+	}
+
+	reloaded, err := gormcrypto.LoadConfig(exported, gormcrypto.WithSecretDir(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reloaded, config) {
+		t.Errorf("Expected %v; got %v instead", config, reloaded)
+	}
+
+	_, err = gormcrypto.LoadConfig(contents)
+	var configErr *gormcrypto.ConfigError
+	if !errors.As(err, &configErr) || configErr.Path != "2021-01-01T00:00:00Z.signing.config.key_secret" {
+		t.Errorf("Expected an error for 2021-01-01T00:00:00Z.signing.config.key_secret; got %v instead", err)
+	}
+	if !errors.Is(err, keyconfig.ErrUnresolved) {
+		t.Errorf("Expected %v; got %v instead", keyconfig.ErrUnresolved, err)
+	}
+}
+
+func TestConfigWrapped(t *testing.T) {
+	config := getTestConfig()
+	wrapper, err := keywrap.NewPassphrase("correct horse battery staple", keywrap.Argon2id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exported, err := config.ConfigToBytes(gormcrypto.WithKeyWrapper(wrapper))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, setup := range config.Setups {
+		if key := setup.Encrypter.Config()["key"].(string); bytes.Contains(exported, []byte(key)) {
+			t.Errorf("Expected exported config to hold wrapped keys only; got %s instead", exported)
+		}
+	}
+
+	reader, err := keywrap.NewPassphrase("correct horse battery staple", keywrap.Scrypt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported := gormcrypto.ConfigFromBytes(exported, gormcrypto.WithKeyWrapper(reader)); !reflect.DeepEqual(imported, config) {
+		t.Errorf("Expected %v; got %v instead", config, imported)
+	}
+
+	if _, err := gormcrypto.LoadConfig(exported); !errors.Is(err, keywrap.ErrNoWrapper) {
+		t.Errorf("Expected %v; got %v instead", keywrap.ErrNoWrapper, err)
+	}
+}
@@ -2,107 +2,664 @@
 package cryptypes
 
 import (
+	"bytes"
+	"context"
+	"database/sql"
 	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
 	"time"
+	"unicode/utf8"
 
 	gc "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/encryption"
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/schema"
 )
 
 // Field defines some common features of every supported type, specifically those which are implemented the same way on every type.
-type Field struct{}
+// It also tracks which Config a value should use, so that each *gorm.DB can have its own via gormcrypto.Plugin,
+// the row context encrypted values are bound to, if any, the Storage and SignaturePolicy values use, if their fields are tagged with them,
+// and the context of the statement reading or writing the value, which is passed on to any KMS its Setup uses.
+type Field struct {
+	state *fieldState
+}
+
+// BindConfig attaches a specific Config to the value, which is then used instead of the global one
+func (f *Field) BindConfig(c *gc.Config) {
+	state := f.copyState()
+	state.config = c
+	f.state = &state
+}
+
+// BindContext binds the value to the row it's stored in, using associated data such as that built by gormcrypto.RowContext.
+// Encrypted values are then encrypted with the associated data, and can only be decrypted with the same associated data again.
+// Deterministically encrypted values ignore it, as binding them to their rows would stop them from matching each other.
+func (f *Field) BindContext(associated []byte) {
+	state := f.copyState()
+	state.context = associated
+	f.state = &state
+}
+
+// RequireContext sets whether the value refuses ciphertexts which aren't bound to its row, returning ErrContextUnbound as it's read
+func (f *Field) RequireContext(required bool) {
+	state := f.copyState()
+	state.required = required
+	f.state = &state
+}
+
+// BindStorage sets the Storage the value is written in, instead of that of the Setup writing it
+func (f *Field) BindStorage(storage gc.Storage) {
+	state := f.copyState()
+	state.storage = &storage
+	f.state = &state
+}
+
+// BoundStorage returns the Storage the value is bound to, if any
+func (f Field) BoundStorage() (gc.Storage, bool) {
+	if bound := f.boundStorage(); bound != nil {
+		return *bound, true
+	}
+
+	return gc.StorageBinary, false
+}
+
+// BindSignaturePolicy sets the SignaturePolicy the value follows when its signature fails verification, instead of that of its Config
+func (f *Field) BindSignaturePolicy(policy gc.SignaturePolicy) {
+	state := f.copyState()
+	state.policy = &policy
+	f.state = &state
+}
+
+// BoundSignaturePolicy returns the SignaturePolicy the value is bound to, if any
+func (f Field) BoundSignaturePolicy() (gc.SignaturePolicy, bool) {
+	if f.state != nil && f.state.policy != nil {
+		return *f.state.policy, true
+	}
+
+	return gc.SignatureFlag, false
+}
+
+// SignatureFailed reports whether the signature of the value most recently scanned failed verification; values which aren't signed never fail
+func (f Field) SignatureFailed() bool {
+	return f.state != nil && f.state.failed
+}
+
+// BindStatementContext sets the context passed on to any KMS the value's Setup uses to encrypt or decrypt it; nil unbinds it from any context
+func (f *Field) BindStatementContext(ctx context.Context) {
+	state := f.copyState()
+	state.statement = ctx
+	f.state = &state
+}
+
+// StatementContext returns the context the value is bound to, or context.Background() if it isn't bound to one
+func (f Field) StatementContext() context.Context {
+	if f.state == nil || f.state.statement == nil {
+		return context.Background()
+	}
+
+	return f.state.statement
+}
+
+// BoundContext returns the associated data the value is bound to, or nil if it isn't bound to a row
+func (f Field) BoundContext() []byte {
+	if f.state == nil {
+		return nil
+	}
+
+	return f.state.context
+}
+
+// BoundConfig returns the Config attached to the value, or nil if it uses the global one
+func (f Field) BoundConfig() *gc.Config {
+	if f.state == nil {
+		return nil
+	}
+
+	return f.state.config
+}
+
+// ScannedValue returns the DB value most recently passed to Scan, exactly as its column held it, or nil if there wasn't one
+func (f Field) ScannedValue() interface{} {
+	if f.state == nil {
+		return nil
+	}
+
+	return f.state.stored
+}
+
+// BindPending marks the value as waiting for a Plugin to bind it to its row, so errors only a row context would fix are held back until then
+func (f *Field) BindPending(pending bool) {
+	state := f.copyState()
+	state.pending = pending
+	f.state = &state
+}
+
+// HeldBack reports whether an error was held back the last time the value was scanned, while pending
+func (f Field) HeldBack() bool {
+	return f.state != nil && f.state.held
+}
+
+// QueryClauses is called by GORM as it parses each schema the value is used in; it adds no clauses, but prepares the field with gormcrypto.BindScans,
+// so values are scanned with the Config of the statement reading them
+func (Field) QueryClauses(field *schema.Field) []clause.Interface {
+	gc.BindScans(field)
+
+	return nil
+}
 
 // GormDataType indicates the default type hint for GORM to use in migrations
 func (Field) GormDataType() string {
 	return "blob"
 }
 
-// GormDBDataType indicates the actual type hint for GORM to use in migrations, based on the connected server dialect
+// GormDBDataType indicates the actual type hint for GORM to use in migrations, based on the connected server dialect,
+// and the Storage the field is tagged with, or that of the active Setup
 func (Field) GormDBDataType(db *gorm.DB, field *schema.Field) string {
-	switch db.Dialector.Name() {
-	case "bigquery":
-		return "BYTES"
-	case "clickhouse":
-		return "String"
-	case "mysql":
-		return "BLOB"
-	case "postgres":
-		return "BYTEA"
-	case "sqlite":
-		return "BLOB"
-	case "sqlserver":
-		return "varbinary(max)"
-	}
-	return ""
+	storage, err := gc.StorageFor(db, field)
+	if err != nil {
+		db.AddError(err)
+	}
+
+	return storage.ColumnType(db.Dialector.Name())
+}
+
+// ScanError is returned by Scan when the DB gives it a value of a type it can't read.
+// Values can be read from []byte, string, and sql.RawBytes; nil is read as SQL NULL.
+type ScanError struct {
+	Value  interface{}
+	Target string
+}
+
+// Error describes the value which couldn't be read, and what it was being read into
+func (e *ScanError) Error() string {
+	return fmt.Sprintf("can't scan %T into %s", e.Value, e.Target)
 }
 
 // PRIVATE
 
+var errNoConfig = errors.New("no database cryptography configuration available; use gormcrypto.Init or gormcrypto.Plugin")
+
+// ErrContextUnbound is returned when reading a ciphertext which isn't bound to its row into a field which requires it to be; see gormcrypto.ContextRequirer
+var ErrContextUnbound = errors.New("value isn't bound to the row it was stored in, but its field requires it to be")
+
+var errNoContext = errors.New("value is bound to the row it was stored in, but no row context is available; read it with a gormcrypto.Plugin")
+
+type fieldState struct {
+	config    *gc.Config
+	source    []byte
+	stored    interface{}
+	context   []byte
+	statement context.Context
+	required  bool
+	storage   *gc.Storage
+	policy    *gc.SignaturePolicy
+	failed    bool
+	pending   bool
+	held      bool
+}
+
+// internalStruct is the serialized wrapper used to store values before Envelopes were introduced
 type internalStruct struct {
 	Raw       []byte
 	Signature []byte
 	At        time.Time
 }
 
-func encrypt(value interface{}) (driver.Value, error) {
-	setup := gc.GlobalConfig().CurrentSetup()
-	out := internalStruct{At: time.Now()}
+// scanSource converts a value from the DB into the bytes Scan reads, accepting the types drivers return for both binary and text columns.
+// SQL NULL gives nil; any other type can't be read.
+func scanSource(value interface{}) ([]byte, bool) {
+	switch v := value.(type) {
+	case nil:
+		return nil, true
+	case []byte:
+		return binaryForm(v), true
+	case sql.RawBytes:
+		return binaryForm(v), true
+	case string:
+		return binaryForm([]byte(v)), true
+	}
+
+	return nil, false
+}
 
-	serial, err := setup.Serializer.Serialize(value)
+// scanValue records the DB value passed to Scan, exactly as its column held it, then converts it into the bytes Scan reads, as scanSource does
+func (f *Field) scanValue(value interface{}) ([]byte, bool) {
+	source, ok := scanSource(value)
+	if !ok {
+		return nil, false
+	}
+
+	state := f.copyState()
+	switch v := value.(type) {
+	case []byte:
+		state.stored = append([]byte(nil), v...)
+	case sql.RawBytes:
+		state.stored = append([]byte(nil), v...)
+	default:
+		state.stored = v
+	}
+	f.state = &state
+
+	return source, true
+}
+
+// binaryForm converts a value written in a text Storage back into the binary form it was written from.
+// Other values, including any which merely look like they were written in a text Storage, are returned as they are.
+func binaryForm(source []byte) []byte {
+	text := source
+	if len(text) > 1 && text[0] == '"' && text[len(text)-1] == '"' {
+		var unquoted string
+		if err := json.Unmarshal(text, &unquoted); err != nil {
+			return source
+		}
+		text = []byte(unquoted)
+	}
+	if bytes.HasPrefix(text, []byte(gc.TextHeaderPrefix)) {
+		if binary, ok := binaryEnvelope(text[len(gc.TextHeaderPrefix):]); ok {
+			return binary
+		}
+		return source
+	}
+	if !bytes.HasPrefix(text, []byte(gc.TextPrefix)) {
+		return source
+	}
+
+	binary, err := base64.StdEncoding.DecodeString(string(text[len(gc.TextPrefix):]))
+	if err != nil {
+		return source
+	}
+
+	return binary
+}
+
+// stored converts a value's binary form into the form its Storage writes it in.
+// Text Storages write Envelopes whose payloads are text already in their text form, so those payloads aren't encoded a second time.
+func stored(storage gc.Storage, value driver.Value) driver.Value {
+	binary, ok := value.([]byte)
+	if !ok {
+		return value
+	}
+	if storage.Text() {
+		if text, ok := textEnvelope(binary); ok {
+			return storage.TextValue(text)
+		}
+	}
+
+	return storage.Value(binary)
+}
+
+// textEnvelope converts an Envelope's binary form into its text form, if its Raw and Signature values are text already.
+// Anything else - including Envelopes followed by an encrypted stream - has no text form.
+func textEnvelope(binary []byte) (string, bool) {
+	var envelope Envelope
+	if err := envelope.UnmarshalBinary(binary); err != nil || !textSafe(envelope.Raw) || !textSafe(envelope.Signature) {
+		return "", false
+	}
+	if check, err := envelope.MarshalBinary(); err != nil || !bytes.Equal(check, binary) {
+		return "", false
+	}
+
+	raw, signature := envelope.Raw, envelope.Signature
+	envelope.Raw, envelope.Signature = nil, nil
+	header, err := envelope.MarshalBinary()
+	if err != nil {
+		return "", false
+	}
+
+	return gc.TextHeaderPrefix + base64.StdEncoding.EncodeToString(header) + ":" + strconv.Itoa(len(raw)) + ":" + string(raw) + string(signature), true
+}
+
+// binaryEnvelope converts the text form of an Envelope, after its prefix, back into its binary form
+func binaryEnvelope(text []byte) ([]byte, bool) {
+	fields := bytes.SplitN(text, []byte(":"), 3)
+	if len(fields) < 3 {
+		return nil, false
+	}
+
+	header, err := base64.StdEncoding.DecodeString(string(fields[0]))
+	if err != nil {
+		return nil, false
+	}
+	size, err := strconv.Atoi(string(fields[1]))
+	if err != nil || size < 0 || size > len(fields[2]) {
+		return nil, false
+	}
+
+	var envelope Envelope
+	if err := envelope.UnmarshalBinary(header); err != nil {
+		return nil, false
+	}
+	envelope.Raw, envelope.Signature = fields[2][:size], fields[2][size:]
+	binary, err := envelope.MarshalBinary()
+	if err != nil {
+		return nil, false
+	}
+
+	return binary, true
+}
+
+// textSafe reports whether a value can be written in a text column as it is: valid UTF-8, with no NUL characters
+func textSafe(value []byte) bool {
+	return utf8.Valid(value) && bytes.IndexByte(value, 0) < 0
+}
+
+// storage returns the Storage the value is written in; the one it's bound to, or else that of the Setup writing it
+func (f Field) storage(config gc.Config) gc.Storage {
+	if bound, ok := f.BoundStorage(); ok {
+		return bound
+	}
+
+	return config.CurrentSetup().Storage
+}
+
+func (f Field) boundStorage() *gc.Storage {
+	if f.state == nil {
+		return nil
+	}
+
+	return f.state.storage
+}
+
+// copyState returns a copy of the value's state, to change before replacing it, since copies of the value share the same state
+func (f Field) copyState() fieldState {
+	if f.state == nil {
+		return fieldState{}
+	}
+
+	return *f.state
+}
+
+func (f Field) source() []byte {
+	if f.state == nil {
+		return nil
+	}
+
+	return f.state.source
+}
+
+// config returns the Config the value should use, and whether one is actually available
+func (f Field) config() (gc.Config, bool) {
+	if bound := f.BoundConfig(); bound != nil {
+		return *bound, true
+	}
+
+	global := gc.GlobalConfig()
+	return global, len(global.Setups) > 0
+}
+
+// scanned records the source of a Scan, and returns the Config to use to read it, if any.
+// The row context is kept only if the source is unchanged, since a different source may well come from a different row.
+func (f *Field) scanned(source []byte) (gc.Config, bool) {
+	state := f.copyState()
+	if !bytes.Equal(source, state.source) {
+		state.context = nil
+	}
+	state.source, state.failed, state.held = append([]byte(nil), source...), false, false
+	f.state = &state
+
+	return f.config()
+}
+
+// scanError decides whether an error reading a value should be reported now.
+// Pending values are read again once a Plugin has bound them to their rows, so errors from a missing row context are held back until then;
+// every other error is reported straight away.
+func (f *Field) scanError(err error) error {
+	if errors.Is(err, errNoContext) && f.state != nil && f.state.pending {
+		f.state.held = true
+		return nil
+	}
+
+	return err
+}
+
+// missingConfig reports that a value can't be read without a Config; NULLs need none
+func missingConfig(source []byte) error {
+	if len(source) < 1 {
+		return nil
+	}
+
+	return errNoConfig
+}
+
+// checkSignature applies the SignaturePolicy of the value, or of its Config, once its signature has failed verification.
+// value is the signed value itself, passed to any OnInvalidSignature hook, and raw points to its Raw value, which SignatureZero clears.
+func (f Field) checkSignature(value, raw interface{}) error {
+	config, ok := f.config()
+	if !f.SignatureFailed() || !ok {
+		return nil
+	}
+
+	switch f.signaturePolicy() {
+	case gc.SignatureError:
+		return ErrSignatureInvalid
+	case gc.SignatureZero:
+		target := reflect.ValueOf(raw).Elem()
+		target.Set(reflect.Zero(target.Type()))
+	case gc.SignatureCallback:
+		return signatureHook(config, value)
+	}
+
+	return nil
+}
+
+// signaturePolicy returns the SignaturePolicy the value is bound to, or that of its Config
+func (f Field) signaturePolicy() gc.SignaturePolicy {
+	if policy, ok := f.BoundSignaturePolicy(); ok {
+		return policy
+	}
+	config, _ := f.config()
+
+	return config.SignaturePolicy
+}
+
+// signatureHook passes a value whose signature failed verification to the Config's OnInvalidSignature hook
+func signatureHook(config gc.Config, value interface{}) error {
+	if config.OnInvalidSignature == nil {
+		return errors.New("the SignatureCallback policy needs an OnInvalidSignature hook in the Config")
+	}
+
+	return config.OnInvalidSignature(value)
+}
+
+func (f Field) encrypt(value interface{}) (driver.Value, error) {
+	config, ok := f.config()
+	if !ok {
+		return nil, errNoConfig
+	}
+
+	out, err := encrypt(f.StatementContext(), config, value, f.BoundContext())
+	return stored(f.storage(config), out), err
+}
+
+func (f *Field) decrypt(source []byte, dest interface{}) error {
+	config, ok := f.scanned(source)
+	if !ok {
+		return missingConfig(source)
+	}
+
+	if err := f.checkBound(source); err != nil {
+		return err
+	}
+
+	return f.scanError(decrypt(f.StatementContext(), config, source, dest, f.BoundContext()))
+}
+
+// checkBound refuses a value's source if it isn't bound to its row, but the value requires it to be
+func (f Field) checkBound(source []byte) error {
+	if len(source) < 1 || !f.contextRequired() {
+		return nil
+	}
+
+	var in Envelope
+	if err := in.UnmarshalBinary(source); err != nil || in.Flags&FlagContextBound == 0 {
+		return ErrContextUnbound
+	}
+
+	return nil
+}
+
+func (f Field) contextRequired() bool {
+	return f.state != nil && f.state.required
+}
+
+func (f Field) encryptDeterministic(value interface{}) (driver.Value, error) {
+	config, ok := f.config()
+	if !ok {
+		return nil, errNoConfig
+	}
+
+	out, err := encryptDeterministic(config, value)
+	return stored(f.storage(config), out), err
+}
+
+func (f *Field) decryptDeterministic(source []byte, dest interface{}) error {
+	config, ok := f.scanned(source)
+	if !ok {
+		return missingConfig(source)
+	}
+
+	return f.scanError(decryptDeterministic(config, source, dest))
+}
+
+// bindDB binds the value to the DB's Config, unless it's already bound to one.
+// Only used on copies of values, so the caller's value is never changed.
+func (f *Field) bindDB(db *gorm.DB) {
+	if f.BoundConfig() == nil {
+		config := gc.ConfigFor(db)
+		f.BindConfig(&config)
+	}
+}
+
+// gormValue converts a value into the expression GORM uses for it in queries and writes, reporting any errors to the DB
+func gormValue(db *gorm.DB, valuer driver.Valuer) clause.Expr {
+	value, err := valuer.Value()
+	if err != nil {
+		db.AddError(err)
+	}
+
+	return clause.Expr{SQL: "?", Vars: []interface{}{value}}
+}
+
+func (f Field) sign(value interface{}) (driver.Value, error) {
+	config, ok := f.config()
+	if !ok {
+		return nil, errNoConfig
+	}
+
+	out, err := sign(config, value)
+	return stored(f.storage(config), out), err
+}
+
+func (f *Field) verify(source []byte, dest interface{}) (bool, error) {
+	config, ok := f.scanned(source)
+	if !ok {
+		return false, missingConfig(source)
+	}
+
+	valid, err := verify(config, source, dest)
+	f.state.failed = err == nil && len(source) > 0 && !valid
+	return valid, f.scanError(err)
+}
+
+func (f Field) encryptSign(value interface{}) (driver.Value, error) {
+	config, ok := f.config()
+	if !ok {
+		return nil, errNoConfig
+	}
+
+	out, err := encryptSign(f.StatementContext(), config, value, f.BoundContext())
+	return stored(f.storage(config), out), err
+}
+
+func (f *Field) decryptVerify(source []byte, dest interface{}) (bool, error) {
+	config, ok := f.scanned(source)
+	if !ok {
+		return false, missingConfig(source)
+	}
+
+	if err := f.checkBound(source); err != nil {
+		return false, err
+	}
+
+	valid, err := decryptVerify(f.StatementContext(), config, source, dest, f.BoundContext())
+	f.state.failed = err == nil && len(source) > 0 && !valid
+	return valid, f.scanError(err)
+}
+
+func encrypt(ctx context.Context, config gc.Config, value interface{}, associated []byte) (driver.Value, error) {
+	return encryptEnvelope(ctx, config, value, associated, Envelope{Kind: KindEncrypted})
+}
+
+// encryptEnvelope encrypts a value as encrypt does, storing it in an Envelope which may already describe it further
+func encryptEnvelope(ctx context.Context, config gc.Config, value interface{}, associated []byte, out Envelope) (driver.Value, error) {
+	setup, err := config.ActiveSetup()
 	if err != nil {
 		return nil, err
 	}
+	out.SetupID, out.At = setup.Identifier(), time.Now()
 
-	crypted, err := setup.Encrypter.Encrypt(serial)
+	serial, err := setup.Serializer.Serialize(value)
 	if err != nil {
 		return nil, err
 	}
 
-	out.Raw, err = setup.Encoder.Encode(crypted)
+	crypted, err := encryptWith(ctx, setup, serial, associated, &out)
+	keyconfig.Wipe(serial)
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := setup.Serializer.Serialize(out)
+	out.Raw, err = setup.Encoder.Encode(crypted)
 	if err != nil {
 		return nil, err
 	}
 
-	return result, nil
+	return out.MarshalBinary()
 }
 
-func decrypt(source []byte, dest interface{}) error {
-	var err error
-	var binary, decrypted []byte
-	var in internalStruct
-
+func decrypt(ctx context.Context, config gc.Config, source []byte, dest interface{}, associated []byte) error {
 	if len(source) < 1 {
 		return nil
 	}
 
-	for _, setup := range gc.GlobalConfig().Setups {
-		err = setup.Serializer.Unserialize(source, &in)
-		if err == nil {
-			break
-		}
-	}
+	return decryptEnvelope(ctx, config, source, associated, func(Envelope) (interface{}, error) {
+		return dest, nil
+	})
+}
+
+// decryptEnvelope decrypts a value as decrypt does, using its Envelope to choose where it's unserialized to
+func decryptEnvelope(ctx context.Context, config gc.Config, source []byte, associated []byte, dest func(Envelope) (interface{}, error)) error {
+	var binary, decrypted []byte
+
+	in, setup, err := openEnvelope(config, source, KindEncrypted)
 	if err != nil {
 		return err
 	}
-	setup := gc.GlobalConfig().UsedSetup(in.At)
 
 	binary, err = setup.Encoder.Decode(in.Raw)
 	if err != nil {
 		return err
 	}
 
-	decrypted, err = setup.Encrypter.Decrypt(binary)
+	decrypted, err = decryptWith(ctx, setup, binary, in, associated)
+	if err != nil {
+		return err
+	}
+	defer keyconfig.Wipe(decrypted)
+
+	target, err := dest(in)
 	if err != nil {
 		return err
 	}
 
-	err = setup.Serializer.Unserialize(decrypted, &dest)
+	err = setup.Serializer.Unserialize(decrypted, target)
 	if err != nil {
 		return err
 	}
@@ -110,52 +667,100 @@ func decrypt(source []byte, dest interface{}) error {
 	return nil
 }
 
-func sign(value interface{}) (driver.Value, error) {
-	setup := gc.GlobalConfig().CurrentSetup()
+// encryptDeterministic encrypts a value so that the same value always produces the same result under the same Setup.
+// That makes the value usable in WHERE clauses and unique indexes, but also reveals which stored values are equal to each other.
+// To keep the result stable, the Envelope records no timestamp, and no signature is added.
+func encryptDeterministic(config gc.Config, value interface{}) (driver.Value, error) {
+	setup, err := config.ActiveSetup()
+	if err != nil {
+		return nil, err
+	}
+	if setup.DeterministicEncrypter == nil {
+		return nil, fmt.Errorf("setup %q has no deterministic encrypter", setup.Identifier())
+	}
 
 	serial, err := setup.Serializer.Serialize(value)
 	if err != nil {
 		return nil, err
 	}
 
-	signature, err := setup.Signer.Sign(serial)
+	crypted, err := setup.DeterministicEncrypter.Encrypt(serial)
+	keyconfig.Wipe(serial)
 	if err != nil {
 		return nil, err
 	}
 
-	encoded, err := setup.Encoder.Encode(signature)
+	encoded, err := setup.Encoder.Encode(crypted)
+	if err != nil {
+		return nil, err
+	}
+
+	return Envelope{Kind: KindDeterministic, SetupID: setup.Identifier(), Raw: encoded}.MarshalBinary()
+}
+
+func decryptDeterministic(config gc.Config, source []byte, dest interface{}) error {
+	if len(source) < 1 {
+		return nil
+	}
+
+	in, setup, err := openEnvelope(config, source, KindDeterministic)
+	if err != nil {
+		return err
+	}
+	if setup.DeterministicEncrypter == nil {
+		return fmt.Errorf("setup %q has no deterministic encrypter", setup.Identifier())
+	}
+
+	binary, err := setup.Encoder.Decode(in.Raw)
+	if err != nil {
+		return err
+	}
+
+	decrypted, err := setup.DeterministicEncrypter.Decrypt(binary)
+	if err != nil {
+		return err
+	}
+	defer keyconfig.Wipe(decrypted)
+
+	return setup.Serializer.Unserialize(decrypted, dest)
+}
+
+func sign(config gc.Config, value interface{}) (driver.Value, error) {
+	setup, err := config.ActiveSetup()
+	if err != nil {
+		return nil, err
+	}
+
+	serial, err := setup.Serializer.Serialize(value)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := setup.Signer.Sign(serial)
 	if err != nil {
 		return nil, err
 	}
 
-	signed, err := setup.Serializer.Serialize(internalStruct{Raw: serial, Signature: encoded, At: time.Now()})
+	encoded, err := setup.Encoder.Encode(signature)
 	if err != nil {
 		return nil, err
 	}
 
-	return signed, nil
+	return Envelope{Kind: KindSigned, SetupID: setup.Identifier(), At: time.Now(), Raw: serial, Signature: encoded}.MarshalBinary()
 }
 
-func verify(source []byte, dest interface{}) (bool, error) {
-	var signed internalStruct
+func verify(config gc.Config, source []byte, dest interface{}) (bool, error) {
 	var signature []byte
 	var valid bool
-	var err error
 
 	if len(source) < 1 {
 		return false, nil
 	}
 
-	for _, setup := range gc.GlobalConfig().Setups {
-		err = setup.Serializer.Unserialize(source, &signed)
-		if err == nil {
-			break
-		}
-	}
+	signed, setup, err := openEnvelope(config, source, KindSigned)
 	if err != nil {
 		return false, err
 	}
-	setup := gc.GlobalConfig().UsedSetup(signed.At)
 
 	signature, err = setup.Encoder.Decode(signed.Signature)
 	if err != nil {
@@ -166,8 +771,9 @@ func verify(source []byte, dest interface{}) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	valid = valid && setup.State != gc.StateCompromised
 
-	err = setup.Serializer.Unserialize(signed.Raw, &dest)
+	err = setup.Serializer.Unserialize(signed.Raw, dest)
 	if err != nil {
 		return false, err
 	}
@@ -175,72 +781,66 @@ func verify(source []byte, dest interface{}) (bool, error) {
 	return valid, nil
 }
 
-func encryptSign(value interface{}) (driver.Value, error) {
-	setup := gc.GlobalConfig().CurrentSetup()
-
-	serial, err := setup.Serializer.Serialize(value)
+func encryptSign(ctx context.Context, config gc.Config, value interface{}, associated []byte) (driver.Value, error) {
+	setup, err := config.ActiveSetup()
 	if err != nil {
 		return nil, err
 	}
 
-	crypted, err := setup.Encrypter.Encrypt(serial)
+	serial, err := setup.Serializer.Serialize(value)
 	if err != nil {
 		return nil, err
 	}
 
-	encodedData, err := setup.Encoder.Encode(crypted)
+	defer keyconfig.Wipe(serial)
+
+	out := Envelope{Kind: KindSignedEncrypted, SetupID: setup.Identifier(), At: time.Now()}
+	crypted, err := encryptWith(ctx, setup, serial, associated, &out)
 	if err != nil {
 		return nil, err
 	}
 
-	signature, err := setup.Signer.Sign(serial)
+	out.Raw, err = setup.Encoder.Encode(crypted)
 	if err != nil {
 		return nil, err
 	}
 
-	encodedSign, err := setup.Encoder.Encode(signature)
+	signature, err := setup.Signer.Sign(serial)
 	if err != nil {
 		return nil, err
 	}
 
-	signed, err := setup.Serializer.Serialize(internalStruct{Raw: encodedData, Signature: encodedSign, At: time.Now()})
+	out.Signature, err = setup.Encoder.Encode(signature)
 	if err != nil {
 		return nil, err
 	}
 
-	return signed, nil
+	return out.MarshalBinary()
 }
 
-func decryptVerify(source []byte, dest interface{}) (bool, error) {
-	var signed internalStruct
+func decryptVerify(ctx context.Context, config gc.Config, source []byte, dest interface{}, associated []byte) (bool, error) {
 	var decoded, decrypted, signature []byte
 	var valid bool
-	var err error
 
 	if len(source) < 1 {
 		return false, nil
 	}
 
-	for _, setup := range gc.GlobalConfig().Setups {
-		err = setup.Serializer.Unserialize(source, &signed)
-		if err == nil {
-			break
-		}
-	}
+	signed, setup, err := openEnvelope(config, source, KindSignedEncrypted)
 	if err != nil {
 		return false, err
 	}
-	setup := gc.GlobalConfig().UsedSetup(signed.At)
 
 	decoded, err = setup.Encoder.Decode(signed.Raw)
 	if err != nil {
 		return false, err
 	}
 
-	decrypted, err = setup.Encrypter.Decrypt(decoded)
+	decrypted, err = decryptWith(ctx, setup, decoded, signed, associated)
 	if err != nil {
 		return false, err
 	}
+	defer keyconfig.Wipe(decrypted)
 
 	signature, err = setup.Encoder.Decode(signed.Signature)
 	if err != nil {
@@ -251,11 +851,163 @@ func decryptVerify(source []byte, dest interface{}) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	valid = valid && setup.State != gc.StateCompromised
 
-	err = setup.Serializer.Unserialize(decrypted, &dest)
+	err = setup.Serializer.Unserialize(decrypted, dest)
 	if err != nil {
 		return false, err
 	}
 
 	return valid, nil
 }
+
+// openEnvelope reads the Envelope around a DB value, and finds the Setup which produced it
+func openEnvelope(config gc.Config, source []byte, kind EnvelopeKind) (Envelope, gc.Setup, error) {
+	var in Envelope
+
+	if !IsEnvelope(source) {
+		return openLegacy(config, source, kind)
+	}
+
+	if err := in.UnmarshalBinary(source); err != nil {
+		return in, gc.Setup{}, err
+	}
+
+	setup, err := envelopeSetup(config, in, kind)
+	return in, setup, err
+}
+
+// envelopeSetup checks an Envelope holds the expected kind of value, and finds the Setup which produced it
+func envelopeSetup(config gc.Config, in Envelope, kind EnvelopeKind) (gc.Setup, error) {
+	if in.Kind != kind {
+		return gc.Setup{}, fmt.Errorf("expected a %s value; got a %s value instead", kind, in.Kind)
+	}
+
+	setup, ok := config.SetupByID(in.SetupID)
+	if !ok {
+		return gc.Setup{}, fmt.Errorf("no Setup matches ID %q", in.SetupID)
+	}
+
+	return setup, checkReadable(config, setup)
+}
+
+// encryptWith encrypts a value using the Setup's Encrypter, storing the wrapped data key in the Envelope if the Encrypter uses them.
+// The value is compressed first if the Setup has a Compressor, the value is at least its MinSize, and compressing actually makes it smaller.
+// Any associated data binds the value to its row, which requires an Encrypter that supports associated data, and is flagged in the Envelope.
+// The compressed value is wiped once it's encrypted; the caller wipes the serialized value itself.
+func encryptWith(ctx context.Context, setup gc.Setup, serial []byte, associated []byte, out *Envelope) ([]byte, error) {
+	if setup.Compressor != nil && len(serial) >= setup.Compressor.MinSize() {
+		compressed, err := setup.Compressor.Compress(serial)
+		if err != nil {
+			return nil, err
+		}
+		defer keyconfig.Wipe(compressed)
+		if len(compressed) < len(serial) {
+			serial = compressed
+			out.Flags |= FlagCompressed
+		}
+	}
+
+	if associated != nil {
+		out.Flags |= FlagContextBound
+	}
+
+	if dek, ok := setup.Encrypter.(encryption.DataKeyAlgorithm); ok {
+		crypted, wrappedKey, err := dek.EncryptWithDataKey(ctx, serial, associated)
+		out.DataKey = wrappedKey
+
+		return crypted, err
+	}
+
+	if associated != nil {
+		aead, ok := setup.Encrypter.(encryption.AEADAlgorithm)
+		if !ok {
+			return nil, fmt.Errorf("setup %q can't bind values to their rows; its encrypter doesn't support associated data", setup.Identifier())
+		}
+
+		return aead.EncryptWithAD(serial, associated)
+	}
+
+	return setup.Encrypter.Encrypt(serial)
+}
+
+// decryptWith decrypts a value using the Setup's Encrypter, along with the Envelope's wrapped data key if it has one,
+// then decompresses it if the Envelope says it was compressed.
+// The compressed plaintext is wiped once it's decompressed; the caller wipes the plaintext returned.
+func decryptWith(ctx context.Context, setup gc.Setup, crypted []byte, in Envelope, associated []byte) ([]byte, error) {
+	decrypted, err := decryptBound(ctx, setup, crypted, in, associated)
+	if err != nil || in.Flags&FlagCompressed == 0 {
+		return decrypted, err
+	}
+	defer keyconfig.Wipe(decrypted)
+	if setup.Compressor == nil {
+		return nil, fmt.Errorf("setup %q has no compressor, but the value is compressed", setup.Identifier())
+	}
+
+	return setup.Compressor.Decompress(decrypted)
+}
+
+// decryptBound decrypts a value as decryptWith does, without decompressing it.
+// The associated data is only used if the Envelope says the value is bound to its row, in which case it's required.
+func decryptBound(ctx context.Context, setup gc.Setup, crypted []byte, in Envelope, associated []byte) ([]byte, error) {
+	if in.Flags&FlagContextBound == 0 {
+		associated = nil
+	} else if associated == nil {
+		return nil, errNoContext
+	}
+
+	if len(in.DataKey) > 0 {
+		dek, ok := setup.Encrypter.(encryption.DataKeyAlgorithm)
+		if !ok {
+			return nil, fmt.Errorf("setup %q can't decrypt values with data keys", setup.Identifier())
+		}
+
+		return dek.DecryptWithDataKey(ctx, crypted, in.DataKey, associated)
+	}
+
+	if associated != nil {
+		aead, ok := setup.Encrypter.(encryption.AEADAlgorithm)
+		if !ok {
+			return nil, fmt.Errorf("setup %q can't decrypt values bound to their rows; its encrypter doesn't support associated data", setup.Identifier())
+		}
+
+		return aead.DecryptWithAD(crypted, associated)
+	}
+
+	return setup.Encrypter.Decrypt(crypted)
+}
+
+// checkReadable refuses to read values from retired Setups, and warns about reading them from compromised ones
+func checkReadable(config gc.Config, setup gc.Setup) error {
+	if !setup.CanRead() {
+		return fmt.Errorf("setup %q is %s", setup.Identifier(), setup.State)
+	}
+	if setup.State == gc.StateCompromised {
+		config.WarnSetup(setup, "reading a value written by compromised Setup %q", setup.Identifier())
+	}
+
+	return nil
+}
+
+// openLegacy reads values stored before Envelopes were introduced.
+// These don't record which Setup produced them, so it has to be inferred from the serialization and the time they were stored.
+func openLegacy(config gc.Config, source []byte, kind EnvelopeKind) (Envelope, gc.Setup, error) {
+	var in internalStruct
+	var err error
+
+	for _, setup := range config.Setups {
+		err = setup.Serializer.Unserialize(source, &in)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return Envelope{}, gc.Setup{}, err
+	}
+	setup := config.UsedSetup(in.At)
+	if setup.Encrypter == nil {
+		return Envelope{}, setup, errors.New("no Setup can read this value")
+	}
+
+	return Envelope{Kind: kind, SetupID: setup.Identifier(), At: in.At, Raw: in.Raw, Signature: in.Signature}, setup, checkReadable(config, setup)
+}
@@ -73,6 +73,11 @@ func TestMain(m *testing.M) {
 		panic(err)
 	}
 
+	siv, err := encryption.NewAESSIV(eKey + eKey)
+	if err != nil {
+		panic(err)
+	}
+
 	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
 	if err != nil {
 		panic(err)
@@ -81,10 +86,11 @@ func TestMain(m *testing.M) {
 	gc.Init(gc.Config{
 		Setups: map[time.Time]gc.Setup{
 			time.Now().Add(-1 * time.Minute): {
-				Encoder:    encoding.Base64{},
-				Serializer: serializing.JSON{},
-				Encrypter:  xchacha,
-				Signer:     signing.NewED25519FromSeed(sKey),
+				Encoder:                encoding.Base64{},
+				Serializer:             serializing.JSON{},
+				Encrypter:              xchacha,
+				Signer:                 signing.NewED25519FromSeed(sKey),
+				DeterministicEncrypter: siv,
 			},
 			time.Now().Add(1 * time.Hour): {
 				Encoder:    encoding.Hex{},
@@ -126,13 +132,13 @@ func (actual testStruct) Equals(expected testStruct) bool {
 }
 
 func tamperWith(signed driver.Value, attack []byte) []byte {
-	var unserial internalStruct
+	var unserial cryptypes.Envelope
 
-	gc.GlobalConfig().CurrentSetup().Serializer.Unserialize(signed.([]byte), &unserial)
+	unserial.UnmarshalBinary(signed.([]byte))
 
 	unserial.Raw = attack
 
-	reserial, _ := gc.GlobalConfig().CurrentSetup().Serializer.Serialize(unserial)
+	reserial, _ := unserial.MarshalBinary()
 
 	return reserial
 }
@@ -152,9 +158,9 @@ func rawComplexValue(in interface{}) []byte {
 }
 
 func unwrapValue(in driver.Valuer) []byte {
-	var out internalStruct
+	var out cryptypes.Envelope
 	wrapped := suppressError(in.Value())
-	gc.GlobalConfig().CurrentSetup().Serializer.Unserialize(wrapped, &out)
+	out.UnmarshalBinary(wrapped)
 
 	return out.Raw
 }
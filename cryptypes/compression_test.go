@@ -0,0 +1,70 @@
+package cryptypes_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	gc "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/compression"
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+	"github.com/danhunsaker/gorm-crypto/encoding"
+	"github.com/danhunsaker/gorm-crypto/encryption"
+	"github.com/danhunsaker/gorm-crypto/serializing"
+	"github.com/danhunsaker/gorm-crypto/signing"
+)
+
+func TestCompression(t *testing.T) {
+	enc, _ := encryption.NewAES256GCM("EncryptionKeyThatShouldBe32Bytes")
+	setup := gc.Setup{
+		ID:         "compressed",
+		Encoder:    encoding.Base64{},
+		Serializer: serializing.JSON{},
+		Encrypter:  enc,
+		Signer:     signing.NewED25519FromSeed("SigningKeyThatShouldBe32BytesToo"),
+		Compressor: compression.Gzip{Threshold: 64},
+	}
+	config := gc.Config{Setups: map[time.Time]gc.Setup{time.Now(): setup}}
+
+	for _, test := range []struct {
+		raw        string
+		compressed bool
+	}{
+		{"Short", false},
+		{strings.Repeat("Long enough to be worth compressing. ", 8), true},
+	} {
+		expected := cryptypes.SignedEncryptedString{Raw: test.raw}
+		expected.BindConfig(&config)
+		sealed, err := expected.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var envelope cryptypes.Envelope
+		if err = envelope.UnmarshalBinary(sealed.([]byte)); err != nil {
+			t.Fatal(err)
+		}
+		if actual := envelope.Flags&cryptypes.FlagCompressed != 0; actual != test.compressed {
+			t.Errorf("Expected compressed = %v; got %v instead", test.compressed, actual)
+		}
+
+		var actual cryptypes.SignedEncryptedString
+		actual.BindConfig(&config)
+		if err = actual.Scan(sealed); err != nil {
+			t.Fatal(err)
+		}
+		if actual.Raw != expected.Raw || !actual.Valid {
+			t.Errorf("Expected %v; got %v instead", expected.Raw, actual.Raw)
+		}
+
+		if test.compressed {
+			setup.Compressor = nil
+			uncompressing := gc.Config{Setups: map[time.Time]gc.Setup{time.Now(): setup}}
+			var failed cryptypes.SignedEncryptedString
+			failed.BindConfig(&uncompressing)
+			if err = failed.Scan(sealed); err == nil || !strings.Contains(err.Error(), "compressor") {
+				t.Errorf("Expected an error reading a compressed value without a Compressor; got %v instead", err)
+			}
+		}
+	}
+}
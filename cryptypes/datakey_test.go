@@ -0,0 +1,70 @@
+package cryptypes_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gc "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+	"github.com/danhunsaker/gorm-crypto/encoding"
+	"github.com/danhunsaker/gorm-crypto/encryption"
+	"github.com/danhunsaker/gorm-crypto/kms"
+	"github.com/danhunsaker/gorm-crypto/serializing"
+	"github.com/danhunsaker/gorm-crypto/signing"
+)
+
+func TestDataKeys(t *testing.T) {
+	provider := kms.NewFake()
+	config := gc.Config{Setups: map[time.Time]gc.Setup{
+		time.Now(): {
+			Encoder:    encoding.Base64{},
+			Serializer: serializing.JSON{},
+			Encrypter:  encryption.NewDEK(provider),
+			Signer:     signing.NewED25519FromSeed("SigningKeyThatShouldBe32BytesToo"),
+		},
+	}}
+
+	expected := cryptypes.SignedEncryptedString{Raw: "Test"}
+	expected.BindConfig(&config)
+	sealed, err := expected.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var envelope cryptypes.Envelope
+	if err = envelope.UnmarshalBinary(sealed.([]byte)); err != nil {
+		t.Fatal(err)
+	}
+	if envelope.Version != 2 || len(envelope.DataKey) < 1 {
+		t.Fatalf("Expected a version 2 envelope with a data key; got %+v instead", envelope)
+	}
+
+	provider.Rotate()
+	raw := envelope.Raw
+	if err = envelope.RewrapDataKey(context.Background(), provider); err != nil {
+		t.Fatal(err)
+	}
+	if string(envelope.Raw) != string(raw) {
+		t.Error("Expected re-wrapping to leave the encrypted value untouched")
+	}
+	rewrapped, err := envelope.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, source := range [][]byte{sealed.([]byte), rewrapped} {
+		var actual cryptypes.SignedEncryptedString
+		actual.BindConfig(&config)
+		if err = actual.Scan(source); err != nil {
+			t.Fatal(err)
+		}
+		if actual.Raw != expected.Raw || !actual.Valid {
+			t.Errorf("Expected %v; got %v instead", expected.Raw, actual.Raw)
+		}
+	}
+
+	if wraps, unwraps := provider.Calls(); wraps != 2 || unwraps != 3 {
+		t.Errorf("Expected 2 wraps and 3 unwraps; got %d and %d instead", wraps, unwraps)
+	}
+}
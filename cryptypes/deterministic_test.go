@@ -0,0 +1,87 @@
+package cryptypes_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gc "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type deterministicTestModel struct {
+	ID    uint
+	Email cryptypes.DeterministicEncryptedString `gorm:"uniqueIndex"`
+}
+
+func TestDeterministicValues(t *testing.T) {
+	first, err := cryptypes.DeterministicEncryptedString{Raw: "Test"}.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := cryptypes.DeterministicEncryptedString{Raw: "Test"}.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := cryptypes.DeterministicEncryptedString{Raw: "Other"}.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(first.([]byte), second.([]byte)) {
+		t.Errorf("Expected %v; got %v instead", first, second)
+	}
+	if bytes.Equal(first.([]byte), other.([]byte)) {
+		t.Error("Expected different values to encrypt differently")
+	}
+
+	config := gc.Config{Setups: make(map[time.Time]gc.Setup)}
+	for at, setup := range gc.GlobalConfig().Setups {
+		setup.DeterministicEncrypter = nil
+		config.Setups[at] = setup
+	}
+	unsupported := cryptypes.DeterministicEncryptedString{Raw: "Test"}
+	unsupported.BindConfig(&config)
+	if _, err := unsupported.Value(); err == nil {
+		t.Error("Expected an error without a deterministic encrypter; got none")
+	}
+}
+
+func TestDeterministicQueries(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "deterministic.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&deterministicTestModel{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, email := range []string{"first@example.com", "second@example.com"} {
+		if err := db.Create(&deterministicTestModel{Email: cryptypes.DeterministicEncryptedString{Raw: email}}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var found deterministicTestModel
+	if err := db.Where("email = ?", cryptypes.DeterministicEncryptedString{Raw: "second@example.com"}).First(&found).Error; err != nil {
+		t.Fatal(err)
+	}
+	if found.ID != 2 || found.Email.Raw != "second@example.com" {
+		t.Errorf("Expected row 2 with second@example.com; got %+v instead", found)
+	}
+
+	var count int64
+	if err := db.Model(&deterministicTestModel{}).Where(&deterministicTestModel{Email: cryptypes.DeterministicEncryptedString{Raw: "first@example.com"}}).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 match; got %d instead", count)
+	}
+
+	if err := db.Create(&deterministicTestModel{Email: cryptypes.DeterministicEncryptedString{Raw: "first@example.com"}}).Error; err == nil {
+		t.Error("Expected the unique index to reject a duplicate; got no error")
+	}
+}
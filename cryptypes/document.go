@@ -0,0 +1,238 @@
+package cryptypes
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// DocumentUpgrade converts a document from one version of its type into the next version
+type DocumentUpgrade func(old interface{}) (interface{}, error)
+
+// RegisterDocument registers a Go type as a version of a named document type, so EncryptedDocument can store and restore it.
+// Every version which might still be stored needs its type registered, unless an upgrade handles it as a map or other generic value;
+// the highest version registered is the current one, which older versions are upgraded to as they're read.
+func RegisterDocument(name string, version uint, prototype interface{}) {
+	documentsMutex.Lock()
+	defer documentsMutex.Unlock()
+
+	doc := registeredDocument(name)
+	doc.versions[uint64(version)] = reflect.TypeOf(prototype)
+	if uint64(version) > doc.latest {
+		doc.latest = uint64(version)
+	}
+	documentTypes[reflect.TypeOf(prototype)] = documentVersion{name: name, version: uint64(version)}
+}
+
+// RegisterDocumentUpgrade registers the function which converts version `from` of a document type into version `from`+1
+func RegisterDocumentUpgrade(name string, from uint, upgrade DocumentUpgrade) {
+	documentsMutex.Lock()
+	defer documentsMutex.Unlock()
+
+	registeredDocument(name).upgrades[uint64(from)] = upgrade
+}
+
+// RenameDocument lets documents stored under an old document type name be read as the new one, keeping their versions
+func RenameDocument(oldName, newName string) {
+	documentsMutex.Lock()
+	defer documentsMutex.Unlock()
+
+	documentRenames[oldName] = newName
+}
+
+// EncryptedDocument supports encrypting values of registered document types, which are restored as exactly the Go type they were stored as.
+// Each value records the name and version of its document type, so older versions are upgraded as they're read; see RegisterDocument.
+// Values stored without a document type - such as by EncryptedAny - are read the same way EncryptedAny reads them.
+type EncryptedDocument struct {
+	Field
+	Raw interface{}
+}
+
+// Scan converts the value from the DB into a usable EncryptedDocument value
+func (s *EncryptedDocument) Scan(value interface{}) error {
+	source, ok := s.scanValue(value)
+	if !ok {
+		return &ScanError{Value: value, Target: fmt.Sprintf("%T", s)}
+	}
+	if source == nil {
+		s.Raw = nil
+		s.scanned(nil)
+		return nil
+	}
+
+	return s.decryptDocument(source)
+}
+
+// Value converts an initialized EncryptedDocument value into a value that can safely be stored in the DB.
+// A nil document is stored as NULL.
+func (s EncryptedDocument) Value() (driver.Value, error) {
+	if s.Raw == nil {
+		return nil, nil
+	}
+
+	return s.encryptDocument(s.Raw)
+}
+
+// Assign replaces the document with a plain Go value, which must be of a registered document type when it's stored
+func (s *EncryptedDocument) Assign(value interface{}) error {
+	s.Raw = value
+	if nullValue(value) {
+		s.Raw = nil
+	}
+
+	return nil
+}
+
+// PRIVATE
+
+type documentType struct {
+	name     string
+	versions map[uint64]reflect.Type
+	upgrades map[uint64]DocumentUpgrade
+	latest   uint64
+}
+
+type documentVersion struct {
+	name    string
+	version uint64
+}
+
+var documentsMutex sync.RWMutex
+var documents = make(map[string]*documentType)
+var documentTypes = make(map[reflect.Type]documentVersion)
+var documentRenames = make(map[string]string)
+
+// registeredDocument finds or creates a document type; the caller must hold documentsMutex
+func registeredDocument(name string) *documentType {
+	doc, ok := documents[name]
+	if !ok {
+		doc = &documentType{name: name, versions: make(map[uint64]reflect.Type), upgrades: make(map[uint64]DocumentUpgrade)}
+		documents[name] = doc
+	}
+
+	return doc
+}
+
+// documentOf finds the registered document type of a value, dereferencing pointers to registered types
+func documentOf(value interface{}) (documentVersion, interface{}, error) {
+	documentsMutex.RLock()
+	defer documentsMutex.RUnlock()
+
+	if found, ok := documentTypes[reflect.TypeOf(value)]; ok {
+		return found, value, nil
+	}
+
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && !v.IsNil() {
+		if found, ok := documentTypes[v.Type().Elem()]; ok {
+			return found, v.Elem().Interface(), nil
+		}
+	}
+
+	return documentVersion{}, nil, fmt.Errorf("%T isn't a registered document type; use cryptypes.RegisterDocument", value)
+}
+
+// documentFor finds the registered document type of a stored document, following any renames
+func documentFor(name string, version uint64) (*documentType, reflect.Type, error) {
+	documentsMutex.RLock()
+	defer documentsMutex.RUnlock()
+
+	for renames := 0; renames <= len(documentRenames); renames++ {
+		newName, ok := documentRenames[name]
+		if !ok {
+			break
+		}
+		name = newName
+	}
+
+	doc, ok := documents[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("document type %q isn't registered", name)
+	}
+	stored, ok := doc.versions[version]
+	if !ok {
+		return nil, nil, fmt.Errorf("version %d of document type %q isn't registered", version, name)
+	}
+
+	return doc, stored, nil
+}
+
+// upgrade converts a stored document into the current version of its type
+func (doc *documentType) upgrade(value interface{}, version uint64) (interface{}, error) {
+	documentsMutex.RLock()
+	current := doc.versions[doc.latest]
+	upgrades := make([]DocumentUpgrade, 0, doc.latest-version)
+	for ; version < doc.latest; version++ {
+		upgrade, ok := doc.upgrades[version]
+		if !ok {
+			documentsMutex.RUnlock()
+			return nil, fmt.Errorf("no upgrade registered from version %d of document type %q", version, doc.name)
+		}
+		upgrades = append(upgrades, upgrade)
+	}
+	documentsMutex.RUnlock()
+
+	for _, upgrade := range upgrades {
+		var err error
+		if value, err = upgrade(value); err != nil {
+			return nil, err
+		}
+	}
+
+	if reflect.TypeOf(value) != current {
+		return nil, fmt.Errorf("expected upgrades of document type %q to produce %v; got %T instead", doc.name, current, value)
+	}
+
+	return value, nil
+}
+
+func (f Field) encryptDocument(value interface{}) (driver.Value, error) {
+	config, ok := f.config()
+	if !ok {
+		return nil, errNoConfig
+	}
+
+	found, value, err := documentOf(value)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := encryptEnvelope(f.StatementContext(), config, value, f.BoundContext(), Envelope{Kind: KindEncrypted, Document: found.name, DocumentVersion: found.version})
+	return stored(f.storage(config), out), err
+}
+
+func (s *EncryptedDocument) decryptDocument(source []byte) error {
+	config, ok := s.scanned(source)
+	if !ok {
+		return missingConfig(source)
+	}
+	if len(source) < 1 {
+		return nil
+	}
+	if err := s.checkBound(source); err != nil {
+		return err
+	}
+
+	var doc *documentType
+	var stored reflect.Value
+	var version uint64
+	err := decryptEnvelope(s.StatementContext(), config, source, s.BoundContext(), func(in Envelope) (interface{}, error) {
+		if in.Document == "" {
+			return &s.Raw, nil
+		}
+
+		found, storedType, err := documentFor(in.Document, in.DocumentVersion)
+		if err != nil {
+			return nil, err
+		}
+		doc, stored, version = found, reflect.New(storedType), in.DocumentVersion
+
+		return stored.Interface(), nil
+	})
+	if err != nil || doc == nil {
+		return s.scanError(err)
+	}
+
+	s.Raw, err = doc.upgrade(stored.Elem().Interface(), version)
+	return s.scanError(err)
+}
@@ -0,0 +1,126 @@
+package cryptypes_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	gc "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+	"github.com/danhunsaker/gorm-crypto/encoding"
+	"github.com/danhunsaker/gorm-crypto/encryption"
+	"github.com/danhunsaker/gorm-crypto/serializing"
+	"github.com/danhunsaker/gorm-crypto/signing"
+)
+
+type profileV1 struct {
+	Name string
+	Age  int
+}
+
+type profileAddress struct {
+	City    string
+	Country string
+}
+
+type profile struct {
+	FullName string
+	Age      int
+	Address  profileAddress
+}
+
+func init() {
+	cryptypes.RenameDocument("person", "profile")
+	cryptypes.RegisterDocument("profile", 1, profileV1{})
+	cryptypes.RegisterDocument("profile", 2, profile{})
+	cryptypes.RegisterDocumentUpgrade("profile", 1, func(old interface{}) (interface{}, error) {
+		v1 := old.(profileV1)
+		return profile{FullName: v1.Name, Age: v1.Age}, nil
+	})
+}
+
+func TestEncryptedDocument(t *testing.T) {
+	expected := profile{FullName: "Test User", Age: 42, Address: profileAddress{City: "Springfield", Country: "US"}}
+
+	for _, serializer := range []serializing.Algorithm{serializing.JSON{}, serializing.GOB{}} {
+		t.Run(reflect.TypeOf(serializer).String(), func(t *testing.T) {
+			enc, _ := encryption.NewAES256GCM("EncryptionKeyThatShouldBe32Bytes")
+			config := gc.Config{Setups: map[time.Time]gc.Setup{
+				time.Now(): {
+					Encoder:    encoding.Base64{},
+					Serializer: serializer,
+					Encrypter:  enc,
+					Signer:     signing.NewED25519FromSeed("SigningKeyThatShouldBe32BytesToo"),
+				},
+			}}
+
+			for _, raw := range []interface{}{expected, &expected} {
+				stored := cryptypes.EncryptedDocument{Raw: raw}
+				stored.BindConfig(&config)
+				var actual cryptypes.EncryptedDocument
+				actual.BindConfig(&config)
+
+				roundTrip(t, stored, &actual)
+				if !reflect.DeepEqual(actual.Raw, expected) {
+					t.Errorf("Expected %#v; got %#v instead", expected, actual.Raw)
+				}
+			}
+
+			old := cryptypes.EncryptedDocument{Raw: profileV1{Name: "Old User", Age: 7}}
+			old.BindConfig(&config)
+			var upgraded cryptypes.EncryptedDocument
+			upgraded.BindConfig(&config)
+			roundTrip(t, old, &upgraded)
+			if expected := (profile{FullName: "Old User", Age: 7}); !reflect.DeepEqual(upgraded.Raw, expected) {
+				t.Errorf("Expected %#v; got %#v instead", expected, upgraded.Raw)
+			}
+		})
+	}
+}
+
+func TestEncryptedDocumentTypes(t *testing.T) {
+	stored := cryptypes.EncryptedDocument{Raw: profileV1{Name: "Renamed", Age: 1}}
+	sealed, err := stored.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var envelope cryptypes.Envelope
+	if err := envelope.UnmarshalBinary(sealed.([]byte)); err != nil {
+		t.Fatal(err)
+	}
+	if envelope.Document != "profile" || envelope.DocumentVersion != 1 {
+		t.Errorf("Expected document type profile version 1; got %v version %d instead", envelope.Document, envelope.DocumentVersion)
+	}
+
+	envelope.Document = "person"
+	renamed, _ := envelope.MarshalBinary()
+	var actual cryptypes.EncryptedDocument
+	if err := actual.Scan(renamed); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := actual.Raw.(profile); !ok {
+		t.Errorf("Expected a renamed document to be upgraded to %T; got %T instead", profile{}, actual.Raw)
+	}
+
+	envelope.Document = "unknown"
+	unknown, _ := envelope.MarshalBinary()
+	if err := actual.Scan(unknown); err == nil {
+		t.Error("Expected an error reading an unregistered document type; got none")
+	}
+
+	if _, err := (cryptypes.EncryptedDocument{Raw: testStruct{}}).Value(); err == nil {
+		t.Error("Expected an error storing an unregistered document type; got none")
+	}
+
+	untyped, err := cryptypes.EncryptedAny{Raw: map[string]interface{}{"Name": "Any"}}.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := actual.Scan(untyped); err != nil {
+		t.Fatal(err)
+	}
+	if expected := map[string]interface{}{"Name": "Any"}; !reflect.DeepEqual(actual.Raw, expected) {
+		t.Errorf("Expected %v; got %v instead", expected, actual.Raw)
+	}
+}
@@ -0,0 +1,313 @@
+package cryptypes
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/danhunsaker/gorm-crypto/kms"
+)
+
+// EnvelopeVersion is the newest Envelope format version this package knows how to read and write.
+// Version 2 adds the wrapped DataKey, version 3 adds Flags, and version 4 adds the Document type;
+// Envelopes are always written with the oldest version that can hold them.
+const EnvelopeVersion byte = 4
+
+// EnvelopeKind indicates which operations were applied to the value held in an Envelope
+type EnvelopeKind byte
+
+// The EnvelopeKinds gormcrypto currently produces
+const (
+	KindEncrypted EnvelopeKind = iota + 1
+	KindSigned
+	KindSignedEncrypted
+	KindDeterministic
+	KindEncryptedStream
+)
+
+// EnvelopeFlags records optional features used to produce the value held in an Envelope
+type EnvelopeFlags byte
+
+// The EnvelopeFlags gormcrypto currently produces
+const (
+	// FlagContextBound marks values encrypted with the table, column, and primary key of their row as associated data
+	FlagContextBound EnvelopeFlags = 1 << iota
+	// FlagCompressed marks values compressed by their Setup's Compressor before they were encrypted
+	FlagCompressed
+)
+
+// ErrNotEnvelope is returned when decoding a value which doesn't start with the Envelope magic prefix, such as those written by older versions
+var ErrNotEnvelope = errors.New("value is not a gormcrypto envelope")
+
+// Envelope is the self-describing binary wrapper stored in the DB around every encrypted and/or signed value.
+// It records the ID of the Setup used to produce the value, so it can be read back without guessing.
+// When the Setup uses envelope encryption, the value's wrapped data key is kept in DataKey.
+// Envelopes of KindEncryptedStream hold no Raw value; the encrypted stream follows the Envelope instead.
+// Values stored by EncryptedDocument record the registered name and version of their type in Document and DocumentVersion.
+type Envelope struct {
+	Version         byte
+	Kind            EnvelopeKind
+	SetupID         string
+	At              time.Time
+	Raw             []byte
+	Signature       []byte
+	DataKey         []byte
+	Flags           EnvelopeFlags
+	Document        string
+	DocumentVersion uint64
+}
+
+// String converts the EnvelopeKind to a human-readable name
+func (k EnvelopeKind) String() string {
+	switch k {
+	case KindEncrypted:
+		return "encrypted"
+	case KindSigned:
+		return "signed"
+	case KindSignedEncrypted:
+		return "signed+encrypted"
+	case KindDeterministic:
+		return "deterministic"
+	case KindEncryptedStream:
+		return "encrypted stream"
+	}
+	return fmt.Sprintf("unknown(%d)", byte(k))
+}
+
+// IsEnvelope reports whether a DB value starts with the Envelope magic prefix
+func IsEnvelope(data []byte) bool {
+	return bytes.HasPrefix(data, envelopeMagic)
+}
+
+// MarshalBinary converts the Envelope into the binary form stored in the DB
+func (e Envelope) MarshalBinary() ([]byte, error) {
+	if e.Version == 0 {
+		e.Version = 1
+		if len(e.DataKey) > 0 {
+			e.Version = 2
+		}
+		if e.Flags != 0 {
+			e.Version = 3
+		}
+		if e.Document != "" {
+			e.Version = 4
+		}
+	}
+	if e.Version > EnvelopeVersion {
+		return nil, fmt.Errorf("unsupported envelope version %d", e.Version)
+	}
+	if e.Version < 2 && len(e.DataKey) > 0 {
+		return nil, fmt.Errorf("envelope version %d can't hold a data key", e.Version)
+	}
+	if e.Version < 3 && e.Flags != 0 {
+		return nil, fmt.Errorf("envelope version %d can't hold flags", e.Version)
+	}
+	if e.Version < 4 && e.Document != "" {
+		return nil, fmt.Errorf("envelope version %d can't hold a document type", e.Version)
+	}
+
+	var out bytes.Buffer
+	out.Write(envelopeMagic)
+	out.WriteByte(e.Version)
+	out.WriteByte(byte(e.Kind))
+	writeChunk(&out, []byte(e.SetupID))
+	if e.At.IsZero() {
+		writeVarint(&out, 0)
+	} else {
+		writeVarint(&out, e.At.UnixNano())
+	}
+	writeChunk(&out, e.Raw)
+	writeChunk(&out, e.Signature)
+	if e.Version >= 2 {
+		writeChunk(&out, e.DataKey)
+	}
+	if e.Version >= 3 {
+		out.WriteByte(byte(e.Flags))
+	}
+	if e.Version >= 4 {
+		writeChunk(&out, []byte(e.Document))
+		writeUvarint(&out, e.DocumentVersion)
+	}
+
+	return out.Bytes(), nil
+}
+
+// UnmarshalBinary reads an Envelope from the binary form stored in the DB
+func (e *Envelope) UnmarshalBinary(data []byte) error {
+	if !IsEnvelope(data) {
+		return ErrNotEnvelope
+	}
+
+	return e.readFrom(bytes.NewReader(data[len(envelopeMagic):]))
+}
+
+// ReadEnvelope reads an Envelope from the start of r, leaving r just past it, where an encrypted stream's contents begin
+func ReadEnvelope(r io.Reader) (Envelope, io.Reader, error) {
+	var e Envelope
+
+	in, ok := r.(envelopeReader)
+	if !ok {
+		in = bufio.NewReader(r)
+	}
+
+	magic := make([]byte, len(envelopeMagic))
+	if _, err := io.ReadFull(in, magic); err != nil || !IsEnvelope(magic) {
+		return e, in, ErrNotEnvelope
+	}
+
+	err := e.readFrom(in)
+
+	return e, in, err
+}
+
+// RewrapDataKey re-wraps the Envelope's data key with the KeyProvider's current key-encryption key, leaving the value itself untouched
+func (e *Envelope) RewrapDataKey(ctx context.Context, provider kms.KeyProvider) error {
+	if len(e.DataKey) < 1 {
+		return errors.New("envelope has no data key")
+	}
+
+	wrapped, err := kms.Rewrap(ctx, provider, e.DataKey)
+	if err != nil {
+		return err
+	}
+	e.DataKey = wrapped
+
+	return nil
+}
+
+// PRIVATE
+
+var envelopeMagic = []byte("GCE")
+
+// maxStreamedChunk limits the size of the chunks read by ReadEnvelope, which can't know how much data is left
+const maxStreamedChunk = 1 << 20
+
+type envelopeReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// readFrom reads an Envelope from just past its magic prefix
+func (e *Envelope) readFrom(in envelopeReader) error {
+	version, err := in.ReadByte()
+	if err != nil {
+		return errTruncated
+	}
+	if version < 1 || version > EnvelopeVersion {
+		return fmt.Errorf("unsupported envelope version %d", version)
+	}
+
+	kind, err := in.ReadByte()
+	if err != nil {
+		return errTruncated
+	}
+
+	setupID, err := readChunk(in)
+	if err != nil {
+		return err
+	}
+
+	at, err := binary.ReadVarint(in)
+	if err != nil {
+		return errTruncated
+	}
+
+	raw, err := readChunk(in)
+	if err != nil {
+		return err
+	}
+
+	signature, err := readChunk(in)
+	if err != nil {
+		return err
+	}
+
+	var dataKey []byte
+	if version >= 2 {
+		if dataKey, err = readChunk(in); err != nil {
+			return err
+		}
+		if len(dataKey) < 1 {
+			dataKey = nil
+		}
+	}
+
+	var flags byte
+	if version >= 3 {
+		if flags, err = in.ReadByte(); err != nil {
+			return errTruncated
+		}
+	}
+
+	var document []byte
+	var documentVersion uint64
+	if version >= 4 {
+		if document, err = readChunk(in); err != nil {
+			return err
+		}
+		if documentVersion, err = binary.ReadUvarint(in); err != nil {
+			return errTruncated
+		}
+	}
+
+	var when time.Time
+	if at != 0 {
+		when = time.Unix(0, at)
+	}
+
+	*e = Envelope{
+		Version:         version,
+		Kind:            EnvelopeKind(kind),
+		SetupID:         string(setupID),
+		At:              when,
+		Raw:             raw,
+		Signature:       signature,
+		DataKey:         dataKey,
+		Flags:           EnvelopeFlags(flags),
+		Document:        string(document),
+		DocumentVersion: documentVersion,
+	}
+
+	return nil
+}
+
+var errTruncated = errors.New("envelope is truncated")
+
+func writeVarint(out *bytes.Buffer, value int64) {
+	buf := make([]byte, binary.MaxVarintLen64)
+	out.Write(buf[:binary.PutVarint(buf, value)])
+}
+
+func writeUvarint(out *bytes.Buffer, value uint64) {
+	buf := make([]byte, binary.MaxVarintLen64)
+	out.Write(buf[:binary.PutUvarint(buf, value)])
+}
+
+func writeChunk(out *bytes.Buffer, chunk []byte) {
+	writeUvarint(out, uint64(len(chunk)))
+	out.Write(chunk)
+}
+
+func readChunk(in envelopeReader) ([]byte, error) {
+	limit := uint64(maxStreamedChunk)
+	if sized, ok := in.(*bytes.Reader); ok {
+		limit = uint64(sized.Len())
+	}
+
+	size, err := binary.ReadUvarint(in)
+	if err != nil || size > limit {
+		return nil, errTruncated
+	}
+
+	chunk := make([]byte, size)
+	if _, err := io.ReadFull(in, chunk); err != nil {
+		return nil, errTruncated
+	}
+
+	return chunk, nil
+}
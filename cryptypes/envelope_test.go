@@ -0,0 +1,109 @@
+package cryptypes_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	gc "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+)
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	expected := cryptypes.Envelope{
+		Version:   cryptypes.EnvelopeVersion,
+		Kind:      cryptypes.KindSignedEncrypted,
+		SetupID:   "test",
+		At:        time.Unix(0, time.Now().UnixNano()),
+		Raw:       []byte("Raw"),
+		Signature: []byte("Signature"),
+		Flags:     cryptypes.FlagContextBound,
+	}
+	var actual cryptypes.Envelope
+
+	sealed, err := expected.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cryptypes.IsEnvelope(sealed) {
+		t.Error("Expected sealed value to be recognized as an envelope")
+	}
+	if err = actual.UnmarshalBinary(sealed); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual.Version != expected.Version || actual.Kind != expected.Kind || actual.SetupID != expected.SetupID || !actual.At.Equal(expected.At) || actual.Flags != expected.Flags {
+		t.Errorf("Expected %v; got %v instead", expected, actual)
+	}
+	if !bytes.Equal(actual.Raw, expected.Raw) || !bytes.Equal(actual.Signature, expected.Signature) {
+		t.Errorf("Expected %v; got %v instead", expected, actual)
+	}
+}
+
+func TestEnvelopeInvalid(t *testing.T) {
+	var actual cryptypes.Envelope
+
+	if err := actual.UnmarshalBinary([]byte(`{"Raw":""}`)); err != cryptypes.ErrNotEnvelope {
+		t.Errorf("Expected %v; got %v instead", cryptypes.ErrNotEnvelope, err)
+	}
+
+	sealed, _ := cryptypes.Envelope{Kind: cryptypes.KindEncrypted, Raw: []byte("Raw")}.MarshalBinary()
+	if err := actual.UnmarshalBinary(sealed[:len(sealed)-2]); err == nil {
+		t.Error("Expected an error for a truncated envelope; got none")
+	}
+}
+
+func TestEnvelopeSetupID(t *testing.T) {
+	var actual cryptypes.Envelope
+
+	sealed, err := cryptypes.EncryptedString{Raw: "Test"}.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = actual.UnmarshalBinary(sealed.([]byte)); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := gc.GlobalConfig().CurrentSetup().Identifier(); actual.SetupID != expected {
+		t.Errorf("Expected setup ID %v; got %v instead", expected, actual.SetupID)
+	}
+
+	actual.SetupID = "unknown"
+	resealed, _ := actual.MarshalBinary()
+	if err = new(cryptypes.EncryptedString).Scan(resealed); err == nil {
+		t.Error("Expected an error for an unknown setup ID; got none")
+	}
+	if err = new(cryptypes.SignedString).Scan(sealed); err == nil {
+		t.Error("Expected an error for a mismatched envelope kind; got none")
+	}
+}
+
+func TestLegacyValues(t *testing.T) {
+	setup := gc.GlobalConfig().UsedSetup(time.Now())
+	expected := "Test"
+
+	serial, _ := setup.Serializer.Serialize(expected)
+	crypted, _ := setup.Encrypter.Encrypt(serial)
+	encoded, _ := setup.Encoder.Encode(crypted)
+	legacy, _ := setup.Serializer.Serialize(internalStruct{Raw: encoded, At: time.Now()})
+
+	var actual cryptypes.EncryptedString
+	if err := actual.Scan(legacy); err != nil {
+		t.Fatal(err)
+	}
+	if actual.Raw != expected {
+		t.Errorf("Expected raw = %v; got %v", expected, actual.Raw)
+	}
+
+	signature, _ := setup.Signer.Sign(serial)
+	encodedSign, _ := setup.Encoder.Encode(signature)
+	legacy, _ = setup.Serializer.Serialize(internalStruct{Raw: serial, Signature: encodedSign, At: time.Now()})
+
+	var signed cryptypes.SignedString
+	if err := signed.Scan(legacy); err != nil {
+		t.Fatal(err)
+	}
+	if signed.Raw != expected || !signed.Valid {
+		t.Errorf("Expected valid raw = %v; got %v (valid = %v)", expected, signed.Raw, signed.Valid)
+	}
+}
@@ -0,0 +1,520 @@
+package cryptypes
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Encrypted supports encrypting data of any type T the Setup's Serializer can handle
+type Encrypted[T any] struct {
+	Field
+	Raw T
+}
+
+// Scan converts the value from the DB into a usable Encrypted value
+func (s *Encrypted[T]) Scan(value interface{}) error {
+	source, ok := s.scanValue(value)
+	if !ok {
+		return &ScanError{Value: value, Target: fmt.Sprintf("%T", s)}
+	}
+	if source == nil {
+		var zero T
+		s.Raw = zero
+	}
+
+	dest, finish := serialTarget(reflect.ValueOf(&s.Raw))
+	if err := s.decrypt(source, dest); err != nil {
+		return err
+	}
+
+	return finish()
+}
+
+// Value converts an initialized Encrypted value into a value that can safely be stored in the DB
+func (s Encrypted[T]) Value() (driver.Value, error) {
+	serial, err := serialValue(reflect.TypeOf(&s.Raw).Elem(), s.Raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.encrypt(serial)
+}
+
+// Assign replaces the raw value with a plain Go value, converting it to T if needed
+func (s *Encrypted[T]) Assign(value interface{}) error {
+	return assignRaw(&s.Raw, value)
+}
+
+// BlindIndexValue returns the raw value for computing a gormcrypto.BlindIndex
+func (s Encrypted[T]) BlindIndexValue() (interface{}, bool) {
+	return s.Raw, true
+}
+
+// NullEncrypted supports encrypting nullable data of any type T the Setup's Serializer can handle
+type NullEncrypted[T any] struct {
+	Field
+	Raw   T
+	Empty bool
+}
+
+// Scan converts the value from the DB into a usable NullEncrypted value
+func (s *NullEncrypted[T]) Scan(value interface{}) error {
+	source, ok := s.scanValue(value)
+	if !ok {
+		return &ScanError{Value: value, Target: fmt.Sprintf("%T", s)}
+	}
+	if source == nil {
+		var zero T
+		s.Raw = zero
+		s.Empty = true
+		s.scanned(nil)
+		return nil
+	}
+	s.Empty = false
+
+	dest, finish := serialTarget(reflect.ValueOf(&s.Raw))
+	if err := s.decrypt(source, dest); err != nil {
+		return err
+	}
+
+	return finish()
+}
+
+// Value converts an initialized NullEncrypted value into a value that can safely be stored in the DB
+func (s NullEncrypted[T]) Value() (driver.Value, error) {
+	if s.Empty {
+		return nil, nil
+	}
+
+	serial, err := serialValue(reflect.TypeOf(&s.Raw).Elem(), s.Raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.encrypt(serial)
+}
+
+// Assign replaces the raw value with a plain Go value, converting it to T if needed; nil values are null
+func (s *NullEncrypted[T]) Assign(value interface{}) error {
+	s.Empty = nullValue(value)
+
+	return assignRaw(&s.Raw, value)
+}
+
+// BlindIndexValue returns the raw value for computing a gormcrypto.BlindIndex, or false if the value is null
+func (s NullEncrypted[T]) BlindIndexValue() (interface{}, bool) {
+	return s.Raw, !s.Empty
+}
+
+// DeterministicEncrypted supports deterministically encrypting data of any type T the Setup's Serializer can handle
+type DeterministicEncrypted[T any] struct {
+	Field
+	Raw T
+}
+
+// Scan converts the value from the DB into a usable DeterministicEncrypted value
+func (s *DeterministicEncrypted[T]) Scan(value interface{}) error {
+	source, ok := s.scanValue(value)
+	if !ok {
+		return &ScanError{Value: value, Target: fmt.Sprintf("%T", s)}
+	}
+	if source == nil {
+		var zero T
+		s.Raw = zero
+	}
+
+	dest, finish := serialTarget(reflect.ValueOf(&s.Raw))
+	if err := s.decryptDeterministic(source, dest); err != nil {
+		return err
+	}
+
+	return finish()
+}
+
+// Value converts an initialized DeterministicEncrypted value into a value that can safely be stored in the DB
+func (s DeterministicEncrypted[T]) Value() (driver.Value, error) {
+	serial, err := serialValue(reflect.TypeOf(&s.Raw).Elem(), s.Raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.encryptDeterministic(serial)
+}
+
+// Assign replaces the raw value with a plain Go value, converting it to T if needed
+func (s *DeterministicEncrypted[T]) Assign(value interface{}) error {
+	return assignRaw(&s.Raw, value)
+}
+
+// GormValue converts an initialized DeterministicEncrypted value into a value that can safely be stored in - or compared against - the DB, using the DB's own Config
+func (s DeterministicEncrypted[T]) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	s.bindDB(db)
+
+	return gormValue(db, s)
+}
+
+// NullDeterministicEncrypted supports deterministically encrypting nullable data of any type T the Setup's Serializer can handle
+type NullDeterministicEncrypted[T any] struct {
+	Field
+	Raw   T
+	Empty bool
+}
+
+// Scan converts the value from the DB into a usable NullDeterministicEncrypted value
+func (s *NullDeterministicEncrypted[T]) Scan(value interface{}) error {
+	source, ok := s.scanValue(value)
+	if !ok {
+		return &ScanError{Value: value, Target: fmt.Sprintf("%T", s)}
+	}
+	if source == nil {
+		var zero T
+		s.Raw = zero
+		s.Empty = true
+		s.scanned(nil)
+		return nil
+	}
+	s.Empty = false
+
+	dest, finish := serialTarget(reflect.ValueOf(&s.Raw))
+	if err := s.decryptDeterministic(source, dest); err != nil {
+		return err
+	}
+
+	return finish()
+}
+
+// Value converts an initialized NullDeterministicEncrypted value into a value that can safely be stored in the DB
+func (s NullDeterministicEncrypted[T]) Value() (driver.Value, error) {
+	if s.Empty {
+		return nil, nil
+	}
+
+	serial, err := serialValue(reflect.TypeOf(&s.Raw).Elem(), s.Raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.encryptDeterministic(serial)
+}
+
+// Assign replaces the raw value with a plain Go value, converting it to T if needed; nil values are null
+func (s *NullDeterministicEncrypted[T]) Assign(value interface{}) error {
+	s.Empty = nullValue(value)
+
+	return assignRaw(&s.Raw, value)
+}
+
+// GormValue converts an initialized NullDeterministicEncrypted value into a value that can safely be stored in - or compared against - the DB, using the DB's own Config
+func (s NullDeterministicEncrypted[T]) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	s.bindDB(db)
+
+	return gormValue(db, s)
+}
+
+// Signed supports signing data of any type T the Setup's Serializer can handle
+type Signed[T any] struct {
+	Field
+	Raw   T
+	Valid bool
+}
+
+// Scan converts the value from the DB into a usable Signed value
+func (s *Signed[T]) Scan(value interface{}) (err error) {
+	source, ok := s.scanValue(value)
+	if !ok {
+		return &ScanError{Value: value, Target: fmt.Sprintf("%T", s)}
+	}
+	if source == nil {
+		var zero T
+		s.Raw = zero
+	}
+
+	dest, finish := serialTarget(reflect.ValueOf(&s.Raw))
+	if s.Valid, err = s.verify(source, dest); err != nil {
+		return err
+	}
+	if err = finish(); err != nil {
+		return err
+	}
+
+	return s.checkSignature(s, &s.Raw)
+}
+
+// Value converts an initialized Signed value into a value that can safely be stored in the DB
+func (s Signed[T]) Value() (driver.Value, error) {
+	serial, err := serialValue(reflect.TypeOf(&s.Raw).Elem(), s.Raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.sign(serial)
+}
+
+// Assign replaces the raw value with a plain Go value, converting it to T if needed
+func (s *Signed[T]) Assign(value interface{}) error {
+	return assignRaw(&s.Raw, value)
+}
+
+// NullSigned supports signing nullable data of any type T the Setup's Serializer can handle
+type NullSigned[T any] struct {
+	Field
+	Raw   T
+	Empty bool
+	Valid bool
+}
+
+// Scan converts the value from the DB into a usable NullSigned value
+func (s *NullSigned[T]) Scan(value interface{}) (err error) {
+	source, ok := s.scanValue(value)
+	if !ok {
+		return &ScanError{Value: value, Target: fmt.Sprintf("%T", s)}
+	}
+	if source == nil {
+		var zero T
+		s.Raw = zero
+		s.Empty = true
+		s.Valid = true
+		s.scanned(nil)
+		return nil
+	}
+	s.Empty = false
+
+	dest, finish := serialTarget(reflect.ValueOf(&s.Raw))
+	if s.Valid, err = s.verify(source, dest); err != nil {
+		return err
+	}
+	if err = finish(); err != nil {
+		return err
+	}
+
+	return s.checkSignature(s, &s.Raw)
+}
+
+// Value converts an initialized NullSigned value into a value that can safely be stored in the DB
+func (s NullSigned[T]) Value() (driver.Value, error) {
+	if s.Empty {
+		return nil, nil
+	}
+
+	serial, err := serialValue(reflect.TypeOf(&s.Raw).Elem(), s.Raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.sign(serial)
+}
+
+// Assign replaces the raw value with a plain Go value, converting it to T if needed; nil values are null
+func (s *NullSigned[T]) Assign(value interface{}) error {
+	s.Empty = nullValue(value)
+
+	return assignRaw(&s.Raw, value)
+}
+
+// SignedEncrypted supports signing and encrypting data of any type T the Setup's Serializer can handle
+type SignedEncrypted[T any] struct {
+	Field
+	Raw   T
+	Valid bool
+}
+
+// Scan converts the value from the DB into a usable SignedEncrypted value
+func (s *SignedEncrypted[T]) Scan(value interface{}) (err error) {
+	source, ok := s.scanValue(value)
+	if !ok {
+		return &ScanError{Value: value, Target: fmt.Sprintf("%T", s)}
+	}
+	if source == nil {
+		var zero T
+		s.Raw = zero
+	}
+
+	dest, finish := serialTarget(reflect.ValueOf(&s.Raw))
+	if s.Valid, err = s.decryptVerify(source, dest); err != nil {
+		return err
+	}
+	if err = finish(); err != nil {
+		return err
+	}
+
+	return s.checkSignature(s, &s.Raw)
+}
+
+// Value converts an initialized SignedEncrypted value into a value that can safely be stored in the DB
+func (s SignedEncrypted[T]) Value() (driver.Value, error) {
+	serial, err := serialValue(reflect.TypeOf(&s.Raw).Elem(), s.Raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.encryptSign(serial)
+}
+
+// Assign replaces the raw value with a plain Go value, converting it to T if needed
+func (s *SignedEncrypted[T]) Assign(value interface{}) error {
+	return assignRaw(&s.Raw, value)
+}
+
+// BlindIndexValue returns the raw value for computing a gormcrypto.BlindIndex
+func (s SignedEncrypted[T]) BlindIndexValue() (interface{}, bool) {
+	return s.Raw, true
+}
+
+// NullSignedEncrypted supports signing and encrypting nullable data of any type T the Setup's Serializer can handle
+type NullSignedEncrypted[T any] struct {
+	Field
+	Raw   T
+	Empty bool
+	Valid bool
+}
+
+// Scan converts the value from the DB into a usable NullSignedEncrypted value
+func (s *NullSignedEncrypted[T]) Scan(value interface{}) (err error) {
+	source, ok := s.scanValue(value)
+	if !ok {
+		return &ScanError{Value: value, Target: fmt.Sprintf("%T", s)}
+	}
+	if source == nil {
+		var zero T
+		s.Raw = zero
+		s.Empty = true
+		s.Valid = true
+		s.scanned(nil)
+		return nil
+	}
+	s.Empty = false
+
+	dest, finish := serialTarget(reflect.ValueOf(&s.Raw))
+	if s.Valid, err = s.decryptVerify(source, dest); err != nil {
+		return err
+	}
+	if err = finish(); err != nil {
+		return err
+	}
+
+	return s.checkSignature(s, &s.Raw)
+}
+
+// Value converts an initialized NullSignedEncrypted value into a value that can safely be stored in the DB
+func (s NullSignedEncrypted[T]) Value() (driver.Value, error) {
+	if s.Empty {
+		return nil, nil
+	}
+
+	serial, err := serialValue(reflect.TypeOf(&s.Raw).Elem(), s.Raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.encryptSign(serial)
+}
+
+// Assign replaces the raw value with a plain Go value, converting it to T if needed; nil values are null
+func (s *NullSignedEncrypted[T]) Assign(value interface{}) error {
+	s.Empty = nullValue(value)
+
+	return assignRaw(&s.Raw, value)
+}
+
+// BlindIndexValue returns the raw value for computing a gormcrypto.BlindIndex, or false if the value is null
+func (s NullSignedEncrypted[T]) BlindIndexValue() (interface{}, bool) {
+	return s.Raw, !s.Empty
+}
+
+// PRIVATE
+
+// assignRaw sets the raw value raw points to from a plain Go value, for gormcrypto.Assigner.
+// Values are only converted between types of the same kind, between numeric types, and between strings and byte or rune slices.
+func assignRaw(raw interface{}, value interface{}) error {
+	dest := reflect.ValueOf(raw).Elem()
+	if nullValue(value) {
+		dest.Set(reflect.Zero(dest.Type()))
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr && !v.Type().AssignableTo(dest.Type()) && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Type().AssignableTo(dest.Type()):
+		dest.Set(v)
+	case v.Type().ConvertibleTo(dest.Type()) && sameKind(v.Type(), dest.Type()):
+		dest.Set(v.Convert(dest.Type()))
+	default:
+		return fmt.Errorf("can't assign %T to %v", value, dest.Type())
+	}
+
+	return nil
+}
+
+// nullValue reports whether a plain Go value is nil, or a nil pointer
+func nullValue(value interface{}) bool {
+	v := reflect.ValueOf(value)
+
+	return !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil())
+}
+
+// sameKind reports whether converting between two types keeps the meaning of the value, unlike converting an int into a string
+func sameKind(from, to reflect.Type) bool {
+	kindOf := func(t reflect.Type) reflect.Kind {
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+			reflect.Float32, reflect.Float64:
+			return reflect.Float64
+		case reflect.Complex64, reflect.Complex128:
+			return reflect.Complex128
+		case reflect.Slice:
+			if k := t.Elem().Kind(); k == reflect.Uint8 || k == reflect.Int32 {
+				return reflect.String
+			}
+		}
+		return t.Kind()
+	}
+
+	return kindOf(from) == kindOf(to)
+}
+
+// serialValue converts a raw value of the given type into the form handed to the Serializer.
+// Not every Serializer handles complex numbers, so those are converted to little-endian binary first.
+func serialValue(t reflect.Type, raw interface{}) (interface{}, error) {
+	switch t.Kind() {
+	case reflect.Complex64, reflect.Complex128:
+		var bin bytes.Buffer
+		if err := binary.Write(&bin, binary.LittleEndian, raw); err != nil {
+			return nil, err
+		}
+
+		return bin.Bytes(), nil
+	}
+
+	return raw, nil
+}
+
+// serialTarget returns the destination to hand to the Serializer for the raw value ptr points to,
+// and a function which finishes converting it into the raw value once it's been unserialized
+func serialTarget(ptr reflect.Value) (interface{}, func() error) {
+	switch ptr.Type().Elem().Kind() {
+	case reflect.Complex64, reflect.Complex128:
+		var bin []byte
+		return &bin, func() error {
+			if len(bin) == 0 {
+				ptr.Elem().Set(reflect.Zero(ptr.Type().Elem()))
+				return nil
+			}
+
+			return binary.Read(bytes.NewBuffer(bin), binary.LittleEndian, ptr.Interface())
+		}
+	}
+
+	return ptr.Interface(), func() error { return nil }
+}
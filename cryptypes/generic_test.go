@@ -0,0 +1,115 @@
+package cryptypes_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+)
+
+func TestGenericEncrypted(t *testing.T) {
+	var actual cryptypes.Encrypted[testStruct]
+	var null cryptypes.NullEncrypted[testStruct]
+	var deterministic cryptypes.DeterministicEncrypted[testStruct]
+	var nullDeterministic cryptypes.NullDeterministicEncrypted[testStruct]
+
+	roundTrip(t, cryptypes.Encrypted[testStruct]{Raw: in}, &actual)
+	roundTrip(t, cryptypes.NullEncrypted[testStruct]{Raw: in}, &null)
+	roundTrip(t, cryptypes.DeterministicEncrypted[testStruct]{Raw: in}, &deterministic)
+	roundTrip(t, cryptypes.NullDeterministicEncrypted[testStruct]{Raw: in}, &nullDeterministic)
+
+	for _, raw := range []testStruct{actual.Raw, null.Raw, deterministic.Raw, nullDeterministic.Raw} {
+		if !raw.Equals(in) {
+			t.Errorf("Expected raw = %v; got %v", in, raw)
+		}
+	}
+	if null.Empty || nullDeterministic.Empty {
+		t.Error("Expected non-empty values")
+	}
+
+	roundTrip(t, cryptypes.NullEncrypted[testStruct]{Empty: true}, &null)
+	if !null.Empty || null.Raw.Name != "" {
+		t.Errorf("Expected an empty value; got %v", null.Raw)
+	}
+}
+
+func TestGenericSigned(t *testing.T) {
+	var signed cryptypes.Signed[testStruct]
+	var null cryptypes.NullSigned[testStruct]
+	var signedEncrypted cryptypes.SignedEncrypted[testStruct]
+	var nullSignedEncrypted cryptypes.NullSignedEncrypted[testStruct]
+
+	roundTrip(t, cryptypes.Signed[testStruct]{Raw: in}, &signed)
+	roundTrip(t, cryptypes.NullSigned[testStruct]{Raw: in}, &null)
+	roundTrip(t, cryptypes.SignedEncrypted[testStruct]{Raw: in}, &signedEncrypted)
+	roundTrip(t, cryptypes.NullSignedEncrypted[testStruct]{Raw: in}, &nullSignedEncrypted)
+
+	for _, raw := range []testStruct{signed.Raw, null.Raw, signedEncrypted.Raw, nullSignedEncrypted.Raw} {
+		if !raw.Equals(in) {
+			t.Errorf("Expected raw = %v; got %v", in, raw)
+		}
+	}
+	for _, valid := range []bool{signed.Valid, null.Valid, signedEncrypted.Valid, nullSignedEncrypted.Valid} {
+		if !valid {
+			t.Error("Expected valid signatures")
+		}
+	}
+}
+
+func TestGenericAliases(t *testing.T) {
+	var expected cryptypes.EncryptedComplex64 = cryptypes.Encrypted[complex64]{Raw: 1 + 2i}
+	var actual cryptypes.Encrypted[complex64]
+
+	roundTrip(t, expected, &actual)
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+
+	var binary cryptypes.EncryptedByteSlice
+	roundTrip(t, expected, &binary)
+	if len(binary.Raw) != 8 {
+		t.Errorf("Expected complex values to be stored as 8 bytes of binary; got %v", binary.Raw)
+	}
+}
+
+// Internal Support
+
+func TestGenericAssign(t *testing.T) {
+	var count cryptypes.EncryptedInt
+	if err := count.Assign(int64(42)); err != nil || count.Raw != 42 {
+		t.Errorf("Expected %v; got %v (%v) instead", 42, count.Raw, err)
+	}
+	if err := count.Assign("42"); err == nil {
+		t.Error("Expected an error assigning a string to an int; got none")
+	}
+
+	var name cryptypes.EncryptedString
+	if err := name.Assign(1); err == nil {
+		t.Errorf("Expected an error assigning an int to a string; got %q instead", name.Raw)
+	}
+	if err := name.Assign([]byte("Alice")); err != nil || name.Raw != "Alice" {
+		t.Errorf("Expected %v; got %v (%v) instead", "Alice", name.Raw, err)
+	}
+
+	nickname := "Al"
+	var nullable cryptypes.NullSignedString
+	if err := nullable.Assign(&nickname); err != nil || nullable.Raw != nickname || nullable.Empty {
+		t.Errorf("Expected %v; got %v (empty = %v, %v) instead", nickname, nullable.Raw, nullable.Empty, err)
+	}
+	if err := nullable.Assign(nil); err != nil || !nullable.Empty {
+		t.Errorf("Expected an empty value; got %v (empty = %v, %v) instead", nullable.Raw, nullable.Empty, err)
+	}
+}
+
+func roundTrip(t *testing.T, in driver.Valuer, out sql.Scanner) {
+	t.Helper()
+
+	crypted, err := in.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = out.Scan(crypted); err != nil {
+		t.Fatal(err)
+	}
+}
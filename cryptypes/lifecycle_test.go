@@ -0,0 +1,76 @@
+package cryptypes_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	gc "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+)
+
+func TestRetiredSetup(t *testing.T) {
+	config := lifecycleConfig(gc.StateRetired, "")
+
+	expected := cryptypes.SignedEncryptedString{Raw: "Test"}
+	expected.BindConfig(lifecycleConfig(gc.StateActive, ""))
+	signed, err := expected.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual cryptypes.SignedEncryptedString
+	actual.BindConfig(config)
+	if err = actual.Scan(signed); err == nil {
+		t.Error("Expected an error reading from a retired setup; got none")
+	}
+
+	expected.BindConfig(config)
+	if _, err = expected.Value(); err == nil {
+		t.Error("Expected an error writing with only a retired setup; got none")
+	}
+}
+
+func TestCompromisedSetup(t *testing.T) {
+	// Warnings are only sent once per Setup, so each run needs a Setup of its own
+	id := fmt.Sprintf("compromised-%d", time.Now().UnixNano())
+	var warnings []string
+	compromised := lifecycleConfig(gc.StateCompromised, id)
+	compromised.OnWarning = func(message string) { warnings = append(warnings, message) }
+
+	expected := cryptypes.SignedString{Raw: "Test"}
+	expected.BindConfig(lifecycleConfig(gc.StateActive, id))
+	signed, err := expected.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual cryptypes.SignedString
+	actual.BindConfig(compromised)
+	for i := 0; i < 3; i++ {
+		if err = actual.Scan(signed); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Valid {
+		t.Error("Expected valid = false for a compromised setup; got true")
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "compromised") {
+		t.Errorf("Expected a single warning about the compromised setup; got %v", warnings)
+	}
+}
+
+func lifecycleConfig(state gc.SetupState, id string) *gc.Config {
+	setup := gc.GlobalConfig().CurrentSetup()
+	setup.State = state
+	if id != "" {
+		setup.ID = id
+	}
+
+	return &gc.Config{Setups: map[time.Time]gc.Setup{time.Now().Add(-1 * time.Minute): setup}}
+}
@@ -0,0 +1,689 @@
+package cryptypes
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	gc "github.com/danhunsaker/gorm-crypto"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrSignatureInvalid is returned when a signed value is used in a way that requires its signature to be valid, but it isn't
+var ErrSignatureInvalid = gc.ErrSignatureInvalid
+
+// MarshalJSON converts the Encrypted value into the JSON form of its raw value
+func (s Encrypted[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s.Raw, false)
+}
+
+// UnmarshalJSON replaces the Encrypted value's raw value with one read from JSON
+func (s *Encrypted[T]) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, &s.Raw, nil)
+}
+
+// MarshalText converts the Encrypted value into the text form of its raw value
+func (s Encrypted[T]) MarshalText() ([]byte, error) {
+	return marshalText(s.Raw, false)
+}
+
+// UnmarshalText replaces the Encrypted value's raw value with one read from text
+func (s *Encrypted[T]) UnmarshalText(text []byte) error {
+	return unmarshalText(text, &s.Raw, nil)
+}
+
+// MarshalYAML converts the Encrypted value into its raw value for marshaling as YAML
+func (s Encrypted[T]) MarshalYAML() (interface{}, error) {
+	return marshalYAML(s.Raw, false)
+}
+
+// UnmarshalYAML replaces the Encrypted value's raw value with one read from YAML
+func (s *Encrypted[T]) UnmarshalYAML(node *yaml.Node) error {
+	return unmarshalYAML(node, &s.Raw, nil)
+}
+
+// MarshalJSON converts the NullEncrypted value into the JSON form of its raw value, or null if it's empty
+func (s NullEncrypted[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s.Raw, s.Empty)
+}
+
+// UnmarshalJSON replaces the NullEncrypted value's raw value with one read from JSON; null makes it empty
+func (s *NullEncrypted[T]) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, &s.Raw, &s.Empty)
+}
+
+// MarshalText converts the NullEncrypted value into the text form of its raw value, or empty text if it's empty
+func (s NullEncrypted[T]) MarshalText() ([]byte, error) {
+	return marshalText(s.Raw, s.Empty)
+}
+
+// UnmarshalText replaces the NullEncrypted value's raw value with one read from text; empty text makes it empty
+func (s *NullEncrypted[T]) UnmarshalText(text []byte) error {
+	return unmarshalText(text, &s.Raw, &s.Empty)
+}
+
+// MarshalYAML converts the NullEncrypted value into its raw value for marshaling as YAML, or null if it's empty
+func (s NullEncrypted[T]) MarshalYAML() (interface{}, error) {
+	return marshalYAML(s.Raw, s.Empty)
+}
+
+// UnmarshalYAML replaces the NullEncrypted value's raw value with one read from YAML; null makes it empty, though yaml.v3 leaves values decoded from null untouched instead
+func (s *NullEncrypted[T]) UnmarshalYAML(node *yaml.Node) error {
+	return unmarshalYAML(node, &s.Raw, &s.Empty)
+}
+
+// MarshalJSON converts the DeterministicEncrypted value into the JSON form of its raw value
+func (s DeterministicEncrypted[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s.Raw, false)
+}
+
+// UnmarshalJSON replaces the DeterministicEncrypted value's raw value with one read from JSON
+func (s *DeterministicEncrypted[T]) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, &s.Raw, nil)
+}
+
+// MarshalText converts the DeterministicEncrypted value into the text form of its raw value
+func (s DeterministicEncrypted[T]) MarshalText() ([]byte, error) {
+	return marshalText(s.Raw, false)
+}
+
+// UnmarshalText replaces the DeterministicEncrypted value's raw value with one read from text
+func (s *DeterministicEncrypted[T]) UnmarshalText(text []byte) error {
+	return unmarshalText(text, &s.Raw, nil)
+}
+
+// MarshalYAML converts the DeterministicEncrypted value into its raw value for marshaling as YAML
+func (s DeterministicEncrypted[T]) MarshalYAML() (interface{}, error) {
+	return marshalYAML(s.Raw, false)
+}
+
+// UnmarshalYAML replaces the DeterministicEncrypted value's raw value with one read from YAML
+func (s *DeterministicEncrypted[T]) UnmarshalYAML(node *yaml.Node) error {
+	return unmarshalYAML(node, &s.Raw, nil)
+}
+
+// MarshalJSON converts the NullDeterministicEncrypted value into the JSON form of its raw value, or null if it's empty
+func (s NullDeterministicEncrypted[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s.Raw, s.Empty)
+}
+
+// UnmarshalJSON replaces the NullDeterministicEncrypted value's raw value with one read from JSON; null makes it empty
+func (s *NullDeterministicEncrypted[T]) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, &s.Raw, &s.Empty)
+}
+
+// MarshalText converts the NullDeterministicEncrypted value into the text form of its raw value, or empty text if it's empty
+func (s NullDeterministicEncrypted[T]) MarshalText() ([]byte, error) {
+	return marshalText(s.Raw, s.Empty)
+}
+
+// UnmarshalText replaces the NullDeterministicEncrypted value's raw value with one read from text; empty text makes it empty
+func (s *NullDeterministicEncrypted[T]) UnmarshalText(text []byte) error {
+	return unmarshalText(text, &s.Raw, &s.Empty)
+}
+
+// MarshalYAML converts the NullDeterministicEncrypted value into its raw value for marshaling as YAML, or null if it's empty
+func (s NullDeterministicEncrypted[T]) MarshalYAML() (interface{}, error) {
+	return marshalYAML(s.Raw, s.Empty)
+}
+
+// UnmarshalYAML replaces the NullDeterministicEncrypted value's raw value with one read from YAML; null makes it empty, though yaml.v3 leaves values decoded from null untouched instead
+func (s *NullDeterministicEncrypted[T]) UnmarshalYAML(node *yaml.Node) error {
+	return unmarshalYAML(node, &s.Raw, &s.Empty)
+}
+
+// MarshalJSON converts the Signed value into the JSON form of its raw value, or null if its signature is invalid, following its Config's MarshalPolicy
+func (s Signed[T]) MarshalJSON() ([]byte, error) {
+	null, err := s.invalidSignature(s.Valid)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalJSON(s.Raw, null)
+}
+
+// UnmarshalJSON replaces the Signed value's raw value with one read from JSON
+func (s *Signed[T]) UnmarshalJSON(data []byte) error {
+	s.unscanned()
+	s.Valid = false
+
+	return unmarshalJSON(data, &s.Raw, nil)
+}
+
+// MarshalText converts the Signed value into the text form of its raw value, or empty text if its signature is invalid, following its Config's MarshalPolicy
+func (s Signed[T]) MarshalText() ([]byte, error) {
+	null, err := s.invalidSignature(s.Valid)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalText(s.Raw, null)
+}
+
+// UnmarshalText replaces the Signed value's raw value with one read from text
+func (s *Signed[T]) UnmarshalText(text []byte) error {
+	s.unscanned()
+	s.Valid = false
+
+	return unmarshalText(text, &s.Raw, nil)
+}
+
+// MarshalYAML converts the Signed value into its raw value for marshaling as YAML, or null if its signature is invalid, following its Config's MarshalPolicy
+func (s Signed[T]) MarshalYAML() (interface{}, error) {
+	null, err := s.invalidSignature(s.Valid)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalYAML(s.Raw, null)
+}
+
+// UnmarshalYAML replaces the Signed value's raw value with one read from YAML
+func (s *Signed[T]) UnmarshalYAML(node *yaml.Node) error {
+	s.unscanned()
+	s.Valid = false
+
+	return unmarshalYAML(node, &s.Raw, nil)
+}
+
+// MarshalJSON converts the NullSigned value into the JSON form of its raw value, or null if it's empty or its signature is invalid, following its Config's MarshalPolicy
+func (s NullSigned[T]) MarshalJSON() ([]byte, error) {
+	null, err := s.invalidSignature(s.Valid)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalJSON(s.Raw, null || s.Empty)
+}
+
+// UnmarshalJSON replaces the NullSigned value's raw value with one read from JSON; null makes it empty
+func (s *NullSigned[T]) UnmarshalJSON(data []byte) error {
+	s.unscanned()
+	s.Valid = false
+
+	return unmarshalJSON(data, &s.Raw, &s.Empty)
+}
+
+// MarshalText converts the NullSigned value into the text form of its raw value, or empty text if it's empty or its signature is invalid, following its Config's MarshalPolicy
+func (s NullSigned[T]) MarshalText() ([]byte, error) {
+	null, err := s.invalidSignature(s.Valid)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalText(s.Raw, null || s.Empty)
+}
+
+// UnmarshalText replaces the NullSigned value's raw value with one read from text; empty text makes it empty
+func (s *NullSigned[T]) UnmarshalText(text []byte) error {
+	s.unscanned()
+	s.Valid = false
+
+	return unmarshalText(text, &s.Raw, &s.Empty)
+}
+
+// MarshalYAML converts the NullSigned value into its raw value for marshaling as YAML, or null if it's empty or its signature is invalid, following its Config's MarshalPolicy
+func (s NullSigned[T]) MarshalYAML() (interface{}, error) {
+	null, err := s.invalidSignature(s.Valid)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalYAML(s.Raw, null || s.Empty)
+}
+
+// UnmarshalYAML replaces the NullSigned value's raw value with one read from YAML; null makes it empty, though yaml.v3 leaves values decoded from null untouched instead
+func (s *NullSigned[T]) UnmarshalYAML(node *yaml.Node) error {
+	s.unscanned()
+	s.Valid = false
+
+	return unmarshalYAML(node, &s.Raw, &s.Empty)
+}
+
+// MarshalJSON converts the SignedEncrypted value into the JSON form of its raw value, or null if its signature is invalid, following its Config's MarshalPolicy
+func (s SignedEncrypted[T]) MarshalJSON() ([]byte, error) {
+	null, err := s.invalidSignature(s.Valid)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalJSON(s.Raw, null)
+}
+
+// UnmarshalJSON replaces the SignedEncrypted value's raw value with one read from JSON
+func (s *SignedEncrypted[T]) UnmarshalJSON(data []byte) error {
+	s.unscanned()
+	s.Valid = false
+
+	return unmarshalJSON(data, &s.Raw, nil)
+}
+
+// MarshalText converts the SignedEncrypted value into the text form of its raw value, or empty text if its signature is invalid, following its Config's MarshalPolicy
+func (s SignedEncrypted[T]) MarshalText() ([]byte, error) {
+	null, err := s.invalidSignature(s.Valid)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalText(s.Raw, null)
+}
+
+// UnmarshalText replaces the SignedEncrypted value's raw value with one read from text
+func (s *SignedEncrypted[T]) UnmarshalText(text []byte) error {
+	s.unscanned()
+	s.Valid = false
+
+	return unmarshalText(text, &s.Raw, nil)
+}
+
+// MarshalYAML converts the SignedEncrypted value into its raw value for marshaling as YAML, or null if its signature is invalid, following its Config's MarshalPolicy
+func (s SignedEncrypted[T]) MarshalYAML() (interface{}, error) {
+	null, err := s.invalidSignature(s.Valid)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalYAML(s.Raw, null)
+}
+
+// UnmarshalYAML replaces the SignedEncrypted value's raw value with one read from YAML
+func (s *SignedEncrypted[T]) UnmarshalYAML(node *yaml.Node) error {
+	s.unscanned()
+	s.Valid = false
+
+	return unmarshalYAML(node, &s.Raw, nil)
+}
+
+// MarshalJSON converts the NullSignedEncrypted value into the JSON form of its raw value, or null if it's empty or its signature is invalid, following its Config's MarshalPolicy
+func (s NullSignedEncrypted[T]) MarshalJSON() ([]byte, error) {
+	null, err := s.invalidSignature(s.Valid)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalJSON(s.Raw, null || s.Empty)
+}
+
+// UnmarshalJSON replaces the NullSignedEncrypted value's raw value with one read from JSON; null makes it empty
+func (s *NullSignedEncrypted[T]) UnmarshalJSON(data []byte) error {
+	s.unscanned()
+	s.Valid = false
+
+	return unmarshalJSON(data, &s.Raw, &s.Empty)
+}
+
+// MarshalText converts the NullSignedEncrypted value into the text form of its raw value, or empty text if it's empty or its signature is invalid, following its Config's MarshalPolicy
+func (s NullSignedEncrypted[T]) MarshalText() ([]byte, error) {
+	null, err := s.invalidSignature(s.Valid)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalText(s.Raw, null || s.Empty)
+}
+
+// UnmarshalText replaces the NullSignedEncrypted value's raw value with one read from text; empty text makes it empty
+func (s *NullSignedEncrypted[T]) UnmarshalText(text []byte) error {
+	s.unscanned()
+	s.Valid = false
+
+	return unmarshalText(text, &s.Raw, &s.Empty)
+}
+
+// MarshalYAML converts the NullSignedEncrypted value into its raw value for marshaling as YAML, or null if it's empty or its signature is invalid, following its Config's MarshalPolicy
+func (s NullSignedEncrypted[T]) MarshalYAML() (interface{}, error) {
+	null, err := s.invalidSignature(s.Valid)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalYAML(s.Raw, null || s.Empty)
+}
+
+// UnmarshalYAML replaces the NullSignedEncrypted value's raw value with one read from YAML; null makes it empty, though yaml.v3 leaves values decoded from null untouched instead
+func (s *NullSignedEncrypted[T]) UnmarshalYAML(node *yaml.Node) error {
+	s.unscanned()
+	s.Valid = false
+
+	return unmarshalYAML(node, &s.Raw, &s.Empty)
+}
+
+// MarshalJSON converts the EncryptedDocument value into the JSON form of its document, or null if it's nil
+func (s EncryptedDocument) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s.Raw, s.Raw == nil)
+}
+
+// UnmarshalJSON replaces the document with one read from JSON, which holds generic maps and slices rather than a registered document type
+func (s *EncryptedDocument) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, &s.Raw, nil)
+}
+
+// MarshalText converts the EncryptedDocument value into the JSON form of its document, or empty text if it's nil
+func (s EncryptedDocument) MarshalText() ([]byte, error) {
+	if s.Raw == nil {
+		return []byte{}, nil
+	}
+
+	return marshalJSON(s.Raw, false)
+}
+
+// UnmarshalText replaces the document with one read from JSON text, which holds generic maps and slices rather than a registered document type
+func (s *EncryptedDocument) UnmarshalText(text []byte) error {
+	if len(text) < 1 {
+		s.Raw = nil
+		return nil
+	}
+
+	return unmarshalJSON(text, &s.Raw, nil)
+}
+
+// MarshalYAML converts the EncryptedDocument value into its document for marshaling as YAML, or nil
+func (s EncryptedDocument) MarshalYAML() (interface{}, error) {
+	return marshalYAML(s.Raw, s.Raw == nil)
+}
+
+// UnmarshalYAML replaces the document with one read from YAML, which holds generic maps and slices rather than a registered document type
+func (s *EncryptedDocument) UnmarshalYAML(node *yaml.Node) error {
+	return unmarshalYAML(node, &s.Raw, nil)
+}
+
+// MarshalJSON reads the whole EncryptedStream, converting it into a base64 JSON string, or null
+func (s EncryptedStream) MarshalJSON() ([]byte, error) {
+	data, ok, err := streamBytes(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalJSON(data, !ok)
+}
+
+// UnmarshalJSON replaces the EncryptedStream with the bytes of a base64 JSON string; null makes it NULL
+func (s *EncryptedStream) UnmarshalJSON(data []byte) error {
+	var raw []byte
+	if err := unmarshalJSON(data, &raw, nil); err != nil {
+		return err
+	}
+	if raw == nil {
+		return s.Assign(nil)
+	}
+
+	return s.Assign(raw)
+}
+
+// MarshalText reads the whole EncryptedStream as text
+func (s EncryptedStream) MarshalText() ([]byte, error) {
+	data, _, err := streamBytes(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalText(data, false)
+}
+
+// UnmarshalText replaces the EncryptedStream with the bytes of the text
+func (s *EncryptedStream) UnmarshalText(text []byte) error {
+	return s.Assign(append([]byte{}, text...))
+}
+
+// MarshalYAML reads the whole EncryptedStream, converting it into YAML, or nil
+func (s EncryptedStream) MarshalYAML() (interface{}, error) {
+	data, ok, err := streamBytes(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalYAML(data, !ok)
+}
+
+// UnmarshalYAML replaces the EncryptedStream with the bytes read from YAML; null makes it NULL
+func (s *EncryptedStream) UnmarshalYAML(node *yaml.Node) error {
+	var raw []byte
+	if err := unmarshalYAML(node, &raw, nil); err != nil {
+		return err
+	}
+	if raw == nil {
+		return s.Assign(nil)
+	}
+
+	return s.Assign(raw)
+}
+
+// PRIVATE
+
+// invalidSignature decides, following the MarshalPolicy of the value's Config, whether a signed value whose signature failed verification should be marshaled as null
+func (f Field) invalidSignature(valid bool) (bool, error) {
+	if valid || f.source() == nil {
+		return false, nil
+	}
+
+	config, _ := f.config()
+	switch config.MarshalPolicy {
+	case gc.MarshalValue:
+		return false, nil
+	case gc.MarshalError:
+		return false, ErrSignatureInvalid
+	}
+
+	return true, nil
+}
+
+// unscanned forgets the value most recently scanned, once the raw value has been replaced by unmarshaling another
+func (f *Field) unscanned() {
+	if f.state != nil {
+		state := f.copyState()
+		state.source, state.stored, state.failed = nil, nil, false
+		f.state = &state
+	}
+}
+
+// marshalJSON converts a raw value into JSON, or null.
+// JSON has no complex numbers, so those are marshaled as strings, such as "(1+2i)".
+func marshalJSON(raw interface{}, null bool) ([]byte, error) {
+	if null {
+		return []byte("null"), nil
+	}
+	if text, ok := complexText(raw); ok {
+		return json.Marshal(text)
+	}
+
+	return json.Marshal(raw)
+}
+
+// unmarshalJSON reads a raw value from JSON; null zeroes it, and marks it empty if empty is given
+func unmarshalJSON(data []byte, raw interface{}, empty *bool) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return setNull(raw, empty)
+	}
+	if empty != nil {
+		*empty = false
+	}
+
+	if isComplex(raw) {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+
+		return unmarshalText([]byte(text), raw, nil)
+	}
+
+	return json.Unmarshal(data, raw)
+}
+
+// marshalText converts a raw value into text; strings, byte slices, and rune slices are used as they are,
+// scalar values and encoding.TextMarshalers are formatted as usual, and anything else is marshaled as JSON
+func marshalText(raw interface{}, null bool) ([]byte, error) {
+	if null {
+		return []byte{}, nil
+	}
+
+	switch v := raw.(type) {
+	case encoding.TextMarshaler:
+		return v.MarshalText()
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return append([]byte{}, v...), nil
+	case []rune:
+		return []byte(string(v)), nil
+	}
+	if text, ok := complexText(raw); ok {
+		return []byte(text), nil
+	}
+
+	v := reflect.ValueOf(raw)
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.AppendBool(nil, v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(nil, v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.AppendUint(nil, v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.AppendFloat(nil, v.Float(), 'g', -1, v.Type().Bits()), nil
+	}
+
+	return json.Marshal(raw)
+}
+
+// unmarshalText reads a raw value from text, the inverse of marshalText; empty text is null, if empty is given
+func unmarshalText(text []byte, raw interface{}, empty *bool) error {
+	if empty != nil {
+		if *empty = len(text) < 1; *empty {
+			return setNull(raw, nil)
+		}
+	}
+
+	switch v := raw.(type) {
+	case encoding.TextUnmarshaler:
+		return v.UnmarshalText(text)
+	case *string:
+		*v = string(text)
+		return nil
+	case *[]byte:
+		*v = append([]byte{}, text...)
+		return nil
+	case *[]rune:
+		*v = []rune(string(text))
+		return nil
+	case *interface{}:
+		*v = string(text)
+		return nil
+	}
+
+	dest := reflect.ValueOf(raw).Elem()
+	var err error
+	switch dest.Kind() {
+	case reflect.Bool:
+		var parsed bool
+		parsed, err = strconv.ParseBool(string(text))
+		dest.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var parsed int64
+		parsed, err = strconv.ParseInt(string(text), 10, dest.Type().Bits())
+		dest.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var parsed uint64
+		parsed, err = strconv.ParseUint(string(text), 10, dest.Type().Bits())
+		dest.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		var parsed float64
+		parsed, err = strconv.ParseFloat(string(text), dest.Type().Bits())
+		dest.SetFloat(parsed)
+	case reflect.Complex64, reflect.Complex128:
+		var parsed complex128
+		parsed, err = strconv.ParseComplex(string(text), dest.Type().Bits())
+		dest.SetComplex(parsed)
+	default:
+		err = json.Unmarshal(text, raw)
+	}
+
+	return err
+}
+
+// marshalYAML converts a raw value into the value to marshal as YAML, or nil; complex numbers are marshaled as strings
+func marshalYAML(raw interface{}, null bool) (interface{}, error) {
+	if null {
+		return nil, nil
+	}
+	if text, ok := complexText(raw); ok {
+		return text, nil
+	}
+
+	return raw, nil
+}
+
+// unmarshalYAML reads a raw value from a YAML node; null zeroes it, and marks it empty if empty is given
+func unmarshalYAML(node *yaml.Node, raw interface{}, empty *bool) error {
+	if node.ShortTag() == "!!null" {
+		return setNull(raw, empty)
+	}
+	if empty != nil {
+		*empty = false
+	}
+
+	if isComplex(raw) {
+		var text string
+		if err := node.Decode(&text); err != nil {
+			return err
+		}
+
+		return unmarshalText([]byte(text), raw, nil)
+	}
+
+	return node.Decode(raw)
+}
+
+// setNull zeroes the raw value raw points to, and marks it empty if empty is given
+func setNull(raw interface{}, empty *bool) error {
+	dest := reflect.ValueOf(raw).Elem()
+	dest.Set(reflect.Zero(dest.Type()))
+	if empty != nil {
+		*empty = true
+	}
+
+	return nil
+}
+
+// complexText formats complex numbers, which JSON and YAML can't hold, as text
+func complexText(raw interface{}) (string, bool) {
+	switch v := raw.(type) {
+	case complex64:
+		return strconv.FormatComplex(complex128(v), 'g', -1, 64), true
+	case complex128:
+		return strconv.FormatComplex(v, 'g', -1, 128), true
+	}
+
+	return "", false
+}
+
+// isComplex reports whether raw points to a complex number
+func isComplex(raw interface{}) bool {
+	switch reflect.ValueOf(raw).Elem().Kind() {
+	case reflect.Complex64, reflect.Complex128:
+		return true
+	}
+
+	return false
+}
+
+// streamBytes reads the whole of a stream, for marshaling it
+func streamBytes(s EncryptedStream) ([]byte, bool, error) {
+	if s.reader == nil && s.source() == nil {
+		return nil, false, nil
+	}
+
+	plain, err := s.plaintext()
+	if err != nil {
+		return nil, false, err
+	}
+	var out bytes.Buffer
+	if _, err := out.ReadFrom(plain); err != nil {
+		return nil, false, fmt.Errorf("reading stream to marshal it: %w", err)
+	}
+
+	return out.Bytes(), true, nil
+}
@@ -0,0 +1,148 @@
+package cryptypes_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	gc "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+	"gopkg.in/yaml.v3"
+)
+
+type marshalTestStruct struct {
+	Email    cryptypes.EncryptedString           `json:"email" yaml:"email"`
+	Age      cryptypes.NullEncryptedInt          `json:"age" yaml:"age"`
+	Balance  cryptypes.EncryptedComplex128       `json:"balance" yaml:"balance"`
+	Nickname cryptypes.NullSignedEncryptedString `json:"nickname" yaml:"nickname"`
+	Joined   cryptypes.SignedTime                `json:"joined" yaml:"joined"`
+}
+
+func TestMarshalJSON(t *testing.T) {
+	joined := time.Date(2020, 2, 29, 12, 0, 0, 0, time.UTC)
+	in := marshalTestStruct{
+		Email:    cryptypes.EncryptedString{Raw: "alice@example.com"},
+		Age:      cryptypes.NullEncryptedInt{Empty: true},
+		Balance:  cryptypes.EncryptedComplex128{Raw: 1 - 2i},
+		Nickname: cryptypes.NullSignedEncryptedString{Raw: "Al"},
+		Joined:   cryptypes.SignedTime{Raw: joined},
+	}
+
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"email":"alice@example.com","age":null,"balance":"(1-2i)","nickname":"Al","joined":"2020-02-29T12:00:00Z"}`
+	if string(data) != expected {
+		t.Errorf("Expected %v; got %v instead", expected, string(data))
+	}
+
+	var out marshalTestStruct
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Email.Raw != in.Email.Raw || !out.Age.Empty || out.Balance.Raw != in.Balance.Raw || out.Nickname.Raw != "Al" || out.Nickname.Empty || !out.Joined.Raw.Equal(joined) {
+		t.Errorf("Expected %+v; got %+v instead", in, out)
+	}
+}
+
+func TestMarshalYAML(t *testing.T) {
+	in := marshalTestStruct{
+		Email:   cryptypes.EncryptedString{Raw: "alice@example.com"},
+		Age:     cryptypes.NullEncryptedInt{Raw: 30},
+		Balance: cryptypes.EncryptedComplex128{Raw: 2i},
+	}
+	in.Nickname.Empty = true
+
+	data, err := yaml.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(data), "nickname: null\n") {
+		t.Errorf("Expected an empty nickname to be null; got:\n%s", data)
+	}
+
+	var out marshalTestStruct
+	if err := yaml.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Email.Raw != in.Email.Raw || out.Age.Raw != 30 || out.Age.Empty || out.Balance.Raw != in.Balance.Raw {
+		t.Errorf("Expected %+v; got %+v instead from:\n%s", in, out, data)
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	count := cryptypes.EncryptedUint16{Raw: 65535}
+	text, err := count.MarshalText()
+	if err != nil || string(text) != "65535" {
+		t.Errorf("Expected %v; got %v (%v) instead", "65535", string(text), err)
+	}
+	if err := count.UnmarshalText([]byte("65536")); err == nil {
+		t.Error("Expected an error unmarshaling an out of range uint16; got none")
+	}
+
+	var name cryptypes.NullEncryptedString
+	if err := name.UnmarshalText([]byte("")); err != nil || !name.Empty {
+		t.Errorf("Expected an empty value; got %v (empty = %v, %v) instead", name.Raw, name.Empty, err)
+	}
+	if err := name.UnmarshalText([]byte("Alice")); err != nil || name.Raw != "Alice" || name.Empty {
+		t.Errorf("Expected %v; got %v (empty = %v, %v) instead", "Alice", name.Raw, name.Empty, err)
+	}
+}
+
+func TestMarshalInvalidSignature(t *testing.T) {
+	signed, err := cryptypes.SignedString{Raw: "trusted"}.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actual cryptypes.SignedString
+	if err := actual.Scan(tamperWith(signed, rawValue("forged"))); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, err := json.Marshal(actual); err != nil || string(data) != "null" {
+		t.Errorf("Expected %v; got %v (%v) instead", "null", string(data), err)
+	}
+
+	// The MarshalPolicy applies whichever SignaturePolicy the value was read under
+	config := gc.GlobalConfig()
+	config.MarshalPolicy = gc.MarshalValue
+	actual.BindConfig(&config)
+	if data, err := json.Marshal(actual); err != nil || string(data) != `"forged"` {
+		t.Errorf("Expected %v; got %v (%v) instead", `"forged"`, string(data), err)
+	}
+
+	config.MarshalPolicy = gc.MarshalError
+	if _, err := actual.MarshalText(); !errors.Is(err, cryptypes.ErrSignatureInvalid) {
+		t.Errorf("Expected %v; got %v instead", cryptypes.ErrSignatureInvalid, err)
+	}
+	if _, err := yaml.Marshal(actual); err == nil {
+		t.Error("Expected an error marshaling an invalid signature as YAML; got none")
+	}
+
+	if err := json.Unmarshal([]byte(`"replaced"`), &actual); err != nil {
+		t.Fatal(err)
+	}
+	if scanned := actual.ScannedValue(); scanned != nil {
+		t.Errorf("Expected the scanned value to be forgotten once replaced; got %v instead", scanned)
+	}
+	if data, err := json.Marshal(actual); err != nil || string(data) != `"replaced"` {
+		t.Errorf("Expected %v; got %v (%v) instead", `"replaced"`, string(data), err)
+	}
+
+	// Values accepted by the OnInvalidSignature hook as they're read are still marshaled as null, unless the MarshalPolicy says otherwise
+	callback := gc.GlobalConfig()
+	callback.SignaturePolicy = gc.SignatureCallback
+	callback.OnInvalidSignature = func(interface{}) error { return nil }
+	var accepted cryptypes.SignedString
+	accepted.BindConfig(&callback)
+	if err := accepted.Scan(tamperWith(signed, rawValue("forged"))); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := json.Marshal(accepted); err != nil || string(data) != "null" {
+		t.Errorf("Expected %v; got %v (%v) instead", "null", string(data), err)
+	}
+}
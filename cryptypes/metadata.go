@@ -0,0 +1,131 @@
+package cryptypes
+
+import (
+	"errors"
+	"time"
+
+	gc "github.com/danhunsaker/gorm-crypto"
+)
+
+// ErrNotScanned is returned when asking for the Metadata of a value which wasn't scanned from the DB, or was scanned from NULL
+var ErrNotScanned = errors.New("value wasn't scanned from the DB, so it has no metadata")
+
+// Metadata describes how a value scanned from the DB was written: which Setup wrote it, when, and with which algorithms.
+// Values written before Envelopes were introduced have a Version of 0, and an unknown Kind.
+// Algorithms holds the names of the algorithms used to write the value, keyed by component, as in YAML configs - encoding, serializing, encryption, and so on;
+// it's empty for values whose Setup has been removed from the Config, which still report what their Envelopes record.
+type Metadata struct {
+	Kind       EnvelopeKind
+	Version    byte
+	Flags      EnvelopeFlags
+	SetupID    string
+	SetupTime  time.Time
+	At         time.Time
+	Algorithms map[string]string
+
+	current bool
+}
+
+// IsCurrent reports whether the value was written by the Setup currently used to write new values, so it doesn't need rotating
+func (m Metadata) IsCurrent() bool {
+	return m.current
+}
+
+// Metadata describes how the value most recently scanned from the DB was written, using the Config the value is bound to.
+// The Setup which wrote it doesn't have to be readable any more, so rotation coverage can be reported even for retired Setups.
+func (f Field) Metadata() (Metadata, error) {
+	source := f.source()
+	if len(source) < 1 {
+		return Metadata{}, ErrNotScanned
+	}
+
+	config, ok := f.config()
+	if !ok {
+		return Metadata{}, errNoConfig
+	}
+
+	in, setup, err := metadataSource(config, source)
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	out := Metadata{
+		Kind:       in.Kind,
+		Version:    in.Version,
+		Flags:      in.Flags,
+		SetupID:    in.SetupID,
+		At:         in.At,
+		Algorithms: setupAlgorithms(setup, in),
+	}
+	for t, s := range config.Setups {
+		if s.Identifier() == in.SetupID {
+			out.SetupTime = t
+		}
+	}
+	if active, err := config.ActiveSetup(); err == nil {
+		out.current = in.Version > 0 && active.Identifier() == in.SetupID
+	}
+
+	return out, nil
+}
+
+// PRIVATE
+
+// metadataSource reads the Envelope of a scanned value, or builds one for legacy values, and finds the Setup which wrote it.
+// Envelopes whose Setup has been removed are returned with an empty Setup, so their values can still be counted as needing rotation.
+func metadataSource(config gc.Config, source []byte) (Envelope, gc.Setup, error) {
+	if !IsEnvelope(source) {
+		in, setup, err := openLegacy(config, source, 0)
+		// Legacy values which were read fine, but whose Setup can no longer be used, still have metadata to report
+		if setup.Encrypter == nil {
+			return in, setup, err
+		}
+
+		return in, setup, nil
+	}
+
+	var in Envelope
+	if err := in.UnmarshalBinary(source); err != nil {
+		return in, gc.Setup{}, err
+	}
+	setup, _ := config.SetupByID(in.SetupID)
+
+	return in, setup, nil
+}
+
+// setupAlgorithms names the algorithms of a Setup which were used to write the value held in an Envelope
+func setupAlgorithms(setup gc.Setup, in Envelope) map[string]string {
+	if setup.Encoder == nil || setup.Serializer == nil {
+		return map[string]string{}
+	}
+
+	algorithms := map[string]string{
+		"encoding":    setup.Encoder.Name(),
+		"serializing": setup.Serializer.Name(),
+	}
+
+	switch in.Kind {
+	case KindEncrypted, KindEncryptedStream:
+		algorithms["encryption"] = setup.Encrypter.Name()
+	case KindSigned:
+		algorithms["signing"] = setup.Signer.Name()
+	case KindSignedEncrypted:
+		algorithms["encryption"] = setup.Encrypter.Name()
+		algorithms["signing"] = setup.Signer.Name()
+	case KindDeterministic:
+		if setup.DeterministicEncrypter != nil {
+			algorithms["deterministic_encryption"] = setup.DeterministicEncrypter.Name()
+		}
+	default:
+		// Legacy values don't record what was done to them, so name every algorithm which may have been used
+		algorithms["encryption"] = setup.Encrypter.Name()
+		if len(in.Signature) > 0 {
+			algorithms["signing"] = setup.Signer.Name()
+		}
+	}
+	if in.Flags&FlagCompressed != 0 && setup.Compressor != nil {
+		algorithms["compression"] = setup.Compressor.Name()
+	}
+
+	return algorithms
+}
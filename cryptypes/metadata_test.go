@@ -0,0 +1,135 @@
+package cryptypes_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	gc "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/compression"
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+	"github.com/danhunsaker/gorm-crypto/encoding"
+	"github.com/danhunsaker/gorm-crypto/encryption"
+	"github.com/danhunsaker/gorm-crypto/serializing"
+	"github.com/danhunsaker/gorm-crypto/signing"
+)
+
+func TestMetadata(t *testing.T) {
+	enc, _ := encryption.NewAES256GCM("EncryptionKeyThatShouldBe32Bytes")
+	siv, _ := encryption.NewAESSIV("DeterministicKeyThatShouldBe64BytesLongSoThatBothHalvesAre32Byte")
+	oldTime, newTime := time.Now().Add(-time.Hour).UTC(), time.Now().Add(-time.Minute).UTC()
+	oldSetup := gc.Setup{
+		ID:                     "old",
+		Encoder:                encoding.Base64{},
+		Serializer:             serializing.JSON{},
+		Encrypter:              enc,
+		Signer:                 signing.NewED25519FromSeed("SigningKeyThatShouldBe32BytesToo"),
+		DeterministicEncrypter: siv,
+		Compressor:             compression.Gzip{},
+	}
+	newSetup := oldSetup
+	newSetup.ID, newSetup.Encoder, newSetup.Compressor = "new", encoding.Hex{}, nil
+
+	before := gc.Config{Setups: map[time.Time]gc.Setup{oldTime: oldSetup}}
+	after := gc.Config{Setups: map[time.Time]gc.Setup{oldTime: oldSetup, newTime: newSetup}}
+
+	written := time.Now()
+	stale := cryptypes.SignedEncryptedString{Raw: strings.Repeat("Test", 100)}
+	stale.BindConfig(&before)
+	sealed, err := stale.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual cryptypes.SignedEncryptedString
+	actual.BindConfig(&after)
+	if _, err := actual.Metadata(); !errors.Is(err, cryptypes.ErrNotScanned) {
+		t.Errorf("Expected %v; got %v instead", cryptypes.ErrNotScanned, err)
+	}
+	if err := actual.Scan(sealed); err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := actual.Metadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.SetupID != "old" || !meta.SetupTime.Equal(oldTime) || meta.Kind != cryptypes.KindSignedEncrypted || meta.Flags&cryptypes.FlagCompressed == 0 {
+		t.Errorf("Expected the old Setup's metadata; got %+v instead", meta)
+	}
+	if meta.At.Before(written.Add(-time.Second)) || meta.At.After(time.Now()) {
+		t.Errorf("Expected a write time of about %v; got %v instead", written, meta.At)
+	}
+	expected := map[string]string{
+		"encoding":    encoding.Base64{}.Name(),
+		"serializing": serializing.JSON{}.Name(),
+		"encryption":  enc.Name(),
+		"signing":     oldSetup.Signer.Name(),
+		"compression": compression.Gzip{}.Name(),
+	}
+	if !reflect.DeepEqual(meta.Algorithms, expected) {
+		t.Errorf("Expected %v; got %v instead", expected, meta.Algorithms)
+	}
+	if meta.IsCurrent() {
+		t.Error("Expected a value written by an older Setup not to be current")
+	}
+
+	// Values whose Setup has been removed still report what their Envelopes record, so they can be counted as needing rotation
+	retired := gc.Config{Setups: map[time.Time]gc.Setup{newTime: newSetup}}
+	actual.BindConfig(&retired)
+	if meta, err = actual.Metadata(); err != nil {
+		t.Fatal(err)
+	}
+	if meta.SetupID != "old" || meta.Kind != cryptypes.KindSignedEncrypted || meta.At.Before(written.Add(-time.Second)) || !meta.SetupTime.IsZero() {
+		t.Errorf("Expected the removed Setup's metadata; got %+v instead", meta)
+	}
+	if len(meta.Algorithms) > 0 || meta.IsCurrent() {
+		t.Errorf("Expected no algorithms, and not to be current; got %v (current = %v) instead", meta.Algorithms, meta.IsCurrent())
+	}
+	actual.BindConfig(&after)
+
+	deterministic := cryptypes.DeterministicEncryptedString{Raw: "Test"}
+	deterministic.BindConfig(&after)
+	sealed, err = deterministic.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := deterministic.Scan(sealed); err != nil {
+		t.Fatal(err)
+	}
+	if meta, err = deterministic.Metadata(); err != nil {
+		t.Fatal(err)
+	}
+	if !meta.IsCurrent() || meta.SetupID != "new" || !meta.At.IsZero() {
+		t.Errorf("Expected current metadata without a write time; got %+v instead", meta)
+	}
+	if algorithm := meta.Algorithms["deterministic_encryption"]; algorithm != siv.Name() {
+		t.Errorf("Expected %v; got %v instead", siv.Name(), algorithm)
+	}
+}
+
+func TestMetadataLegacy(t *testing.T) {
+	setup := gc.GlobalConfig().UsedSetup(time.Now())
+
+	serial, _ := setup.Serializer.Serialize("Test")
+	crypted, _ := setup.Encrypter.Encrypt(serial)
+	encoded, _ := setup.Encoder.Encode(crypted)
+	legacy, _ := setup.Serializer.Serialize(internalStruct{Raw: encoded, At: time.Now()})
+
+	var actual cryptypes.EncryptedString
+	if err := actual.Scan(legacy); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := actual.Metadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Version != 0 || meta.SetupID != setup.Identifier() || meta.IsCurrent() {
+		t.Errorf("Expected legacy metadata from %v; got %+v instead", setup.Identifier(), meta)
+	}
+	if algorithm := meta.Algorithms["encryption"]; algorithm != setup.Encrypter.Name() {
+		t.Errorf("Expected %v; got %v instead", setup.Encrypter.Name(), algorithm)
+	}
+}
@@ -0,0 +1,256 @@
+package cryptypes
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+)
+
+// SetRedaction turns redaction of encrypted values on or off; it's off by default.
+// While it's on, the encrypted and signed types print a mask such as [encrypted:string] in place of their raw values, whether they're formatted by fmt
+// or logged by log/slog, so decrypted values stay out of logs. Signed types are redacted too, just as RedactLogger redacts them from SQL logs.
+func SetRedaction(enabled bool) {
+	var on int32
+	if enabled {
+		on = 1
+	}
+	atomic.StoreInt32(&redaction, on)
+}
+
+// String prints the Encrypted value's raw value, or a mask while redaction is on
+func (s Encrypted[T]) String() string {
+	return redactedString(mask(KindEncrypted, &s.Raw), s.Raw, false)
+}
+
+// GoString prints the Encrypted value as Go syntax, or a mask while redaction is on
+func (s Encrypted[T]) GoString() string {
+	if redacting() {
+		return mask(KindEncrypted, &s.Raw)
+	}
+
+	return fmt.Sprintf("%T{Raw:%#v}", s, s.Raw)
+}
+
+// Format prints the Encrypted value's raw value with any fmt verb, or a mask while redaction is on
+func (s Encrypted[T]) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, s, mask(KindEncrypted, &s.Raw), s.Raw, false)
+}
+
+// String prints the NullEncrypted value's raw value, or a mask while redaction is on
+func (s NullEncrypted[T]) String() string {
+	return redactedString(mask(KindEncrypted, &s.Raw), s.Raw, s.Empty)
+}
+
+// GoString prints the NullEncrypted value as Go syntax, or a mask while redaction is on
+func (s NullEncrypted[T]) GoString() string {
+	if redacting() {
+		return mask(KindEncrypted, &s.Raw)
+	}
+
+	return fmt.Sprintf("%T{Raw:%#v, Empty:%t}", s, s.Raw, s.Empty)
+}
+
+// Format prints the NullEncrypted value's raw value with any fmt verb, or a mask while redaction is on
+func (s NullEncrypted[T]) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, s, mask(KindEncrypted, &s.Raw), s.Raw, s.Empty)
+}
+
+// String prints the DeterministicEncrypted value's raw value, or a mask while redaction is on
+func (s DeterministicEncrypted[T]) String() string {
+	return redactedString(mask(KindDeterministic, &s.Raw), s.Raw, false)
+}
+
+// GoString prints the DeterministicEncrypted value as Go syntax, or a mask while redaction is on
+func (s DeterministicEncrypted[T]) GoString() string {
+	if redacting() {
+		return mask(KindDeterministic, &s.Raw)
+	}
+
+	return fmt.Sprintf("%T{Raw:%#v}", s, s.Raw)
+}
+
+// Format prints the DeterministicEncrypted value's raw value with any fmt verb, or a mask while redaction is on
+func (s DeterministicEncrypted[T]) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, s, mask(KindDeterministic, &s.Raw), s.Raw, false)
+}
+
+// String prints the NullDeterministicEncrypted value's raw value, or a mask while redaction is on
+func (s NullDeterministicEncrypted[T]) String() string {
+	return redactedString(mask(KindDeterministic, &s.Raw), s.Raw, s.Empty)
+}
+
+// GoString prints the NullDeterministicEncrypted value as Go syntax, or a mask while redaction is on
+func (s NullDeterministicEncrypted[T]) GoString() string {
+	if redacting() {
+		return mask(KindDeterministic, &s.Raw)
+	}
+
+	return fmt.Sprintf("%T{Raw:%#v, Empty:%t}", s, s.Raw, s.Empty)
+}
+
+// Format prints the NullDeterministicEncrypted value's raw value with any fmt verb, or a mask while redaction is on
+func (s NullDeterministicEncrypted[T]) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, s, mask(KindDeterministic, &s.Raw), s.Raw, s.Empty)
+}
+
+// String prints the Signed value's raw value, or a mask while redaction is on
+func (s Signed[T]) String() string {
+	return redactedString(mask(KindSigned, &s.Raw), s.Raw, false)
+}
+
+// GoString prints the Signed value as Go syntax, or a mask while redaction is on
+func (s Signed[T]) GoString() string {
+	if redacting() {
+		return mask(KindSigned, &s.Raw)
+	}
+
+	return fmt.Sprintf("%T{Raw:%#v, Valid:%t}", s, s.Raw, s.Valid)
+}
+
+// Format prints the Signed value's raw value with any fmt verb, or a mask while redaction is on
+func (s Signed[T]) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, s, mask(KindSigned, &s.Raw), s.Raw, false)
+}
+
+// String prints the NullSigned value's raw value, or a mask while redaction is on
+func (s NullSigned[T]) String() string {
+	return redactedString(mask(KindSigned, &s.Raw), s.Raw, s.Empty)
+}
+
+// GoString prints the NullSigned value as Go syntax, or a mask while redaction is on
+func (s NullSigned[T]) GoString() string {
+	if redacting() {
+		return mask(KindSigned, &s.Raw)
+	}
+
+	return fmt.Sprintf("%T{Raw:%#v, Empty:%t, Valid:%t}", s, s.Raw, s.Empty, s.Valid)
+}
+
+// Format prints the NullSigned value's raw value with any fmt verb, or a mask while redaction is on
+func (s NullSigned[T]) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, s, mask(KindSigned, &s.Raw), s.Raw, s.Empty)
+}
+
+// String prints the SignedEncrypted value's raw value, or a mask while redaction is on
+func (s SignedEncrypted[T]) String() string {
+	return redactedString(mask(KindSignedEncrypted, &s.Raw), s.Raw, false)
+}
+
+// GoString prints the SignedEncrypted value as Go syntax, or a mask while redaction is on
+func (s SignedEncrypted[T]) GoString() string {
+	if redacting() {
+		return mask(KindSignedEncrypted, &s.Raw)
+	}
+
+	return fmt.Sprintf("%T{Raw:%#v, Valid:%t}", s, s.Raw, s.Valid)
+}
+
+// Format prints the SignedEncrypted value's raw value with any fmt verb, or a mask while redaction is on
+func (s SignedEncrypted[T]) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, s, mask(KindSignedEncrypted, &s.Raw), s.Raw, false)
+}
+
+// String prints the NullSignedEncrypted value's raw value, or a mask while redaction is on
+func (s NullSignedEncrypted[T]) String() string {
+	return redactedString(mask(KindSignedEncrypted, &s.Raw), s.Raw, s.Empty)
+}
+
+// GoString prints the NullSignedEncrypted value as Go syntax, or a mask while redaction is on
+func (s NullSignedEncrypted[T]) GoString() string {
+	if redacting() {
+		return mask(KindSignedEncrypted, &s.Raw)
+	}
+
+	return fmt.Sprintf("%T{Raw:%#v, Empty:%t, Valid:%t}", s, s.Raw, s.Empty, s.Valid)
+}
+
+// Format prints the NullSignedEncrypted value's raw value with any fmt verb, or a mask while redaction is on
+func (s NullSignedEncrypted[T]) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, s, mask(KindSignedEncrypted, &s.Raw), s.Raw, s.Empty)
+}
+
+// String prints the EncryptedDocument value's document, or a mask while redaction is on
+func (s EncryptedDocument) String() string {
+	return redactedString(documentMask, s.Raw, s.Raw == nil)
+}
+
+// GoString prints the EncryptedDocument value as Go syntax, or a mask while redaction is on
+func (s EncryptedDocument) GoString() string {
+	if redacting() {
+		return documentMask
+	}
+
+	return fmt.Sprintf("%T{Raw:%#v}", s, s.Raw)
+}
+
+// Format prints the EncryptedDocument value's document with any fmt verb, or a mask while redaction is on
+func (s EncryptedDocument) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, s, documentMask, s.Raw, s.Raw == nil)
+}
+
+// PRIVATE
+
+var redaction int32
+
+const documentMask = "[encrypted:document]"
+
+func redacting() bool {
+	return atomic.LoadInt32(&redaction) != 0
+}
+
+// mask builds the text printed in place of a redacted raw value, from the kind of value and the type raw points to
+func mask(kind EnvelopeKind, raw interface{}) string {
+	t := reflect.TypeOf(raw).Elem()
+	name := t.String()
+	if t.Kind() == reflect.Interface && t.NumMethod() == 0 {
+		name = "any"
+	}
+
+	return "[" + kind.String() + ":" + name + "]"
+}
+
+func redactedString(masked string, raw interface{}, null bool) string {
+	switch {
+	case redacting():
+		return masked
+	case null:
+		return "<nil>"
+	}
+
+	return fmt.Sprint(raw)
+}
+
+// formatRedacted prints a raw value with the verb and flags given to Format, or its mask while redaction is on.
+// The %#v verb prints the value itself as Go syntax instead.
+func formatRedacted(f fmt.State, verb rune, value fmt.GoStringer, masked string, raw interface{}, null bool) {
+	switch {
+	case redacting():
+		fmt.Fprint(f, masked)
+	case verb == 'v' && f.Flag('#'):
+		fmt.Fprint(f, value.GoString())
+	case null:
+		fmt.Fprint(f, "<nil>")
+	default:
+		fmt.Fprintf(f, formatDirective(f, verb), raw)
+	}
+}
+
+// formatDirective rebuilds the formatting directive which was given to Format
+func formatDirective(f fmt.State, verb rune) string {
+	directive := []byte{'%'}
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			directive = append(directive, byte(flag))
+		}
+	}
+	if width, ok := f.Width(); ok {
+		directive = strconv.AppendInt(directive, int64(width), 10)
+	}
+	if precision, ok := f.Precision(); ok {
+		directive = append(directive, '.')
+		directive = strconv.AppendInt(directive, int64(precision), 10)
+	}
+
+	return string(append(directive, string(verb)...))
+}
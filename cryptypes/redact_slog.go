@@ -0,0 +1,63 @@
+//go:build go1.21
+
+package cryptypes
+
+import "log/slog"
+
+// LogValue logs the Encrypted value's raw value, or a mask while redaction is on
+func (s Encrypted[T]) LogValue() slog.Value {
+	return logValue(mask(KindEncrypted, &s.Raw), s.Raw, false)
+}
+
+// LogValue logs the NullEncrypted value's raw value, or a mask while redaction is on
+func (s NullEncrypted[T]) LogValue() slog.Value {
+	return logValue(mask(KindEncrypted, &s.Raw), s.Raw, s.Empty)
+}
+
+// LogValue logs the DeterministicEncrypted value's raw value, or a mask while redaction is on
+func (s DeterministicEncrypted[T]) LogValue() slog.Value {
+	return logValue(mask(KindDeterministic, &s.Raw), s.Raw, false)
+}
+
+// LogValue logs the NullDeterministicEncrypted value's raw value, or a mask while redaction is on
+func (s NullDeterministicEncrypted[T]) LogValue() slog.Value {
+	return logValue(mask(KindDeterministic, &s.Raw), s.Raw, s.Empty)
+}
+
+// LogValue logs the Signed value's raw value, or a mask while redaction is on
+func (s Signed[T]) LogValue() slog.Value {
+	return logValue(mask(KindSigned, &s.Raw), s.Raw, false)
+}
+
+// LogValue logs the NullSigned value's raw value, or a mask while redaction is on
+func (s NullSigned[T]) LogValue() slog.Value {
+	return logValue(mask(KindSigned, &s.Raw), s.Raw, s.Empty)
+}
+
+// LogValue logs the SignedEncrypted value's raw value, or a mask while redaction is on
+func (s SignedEncrypted[T]) LogValue() slog.Value {
+	return logValue(mask(KindSignedEncrypted, &s.Raw), s.Raw, false)
+}
+
+// LogValue logs the NullSignedEncrypted value's raw value, or a mask while redaction is on
+func (s NullSignedEncrypted[T]) LogValue() slog.Value {
+	return logValue(mask(KindSignedEncrypted, &s.Raw), s.Raw, s.Empty)
+}
+
+// LogValue logs the EncryptedDocument value's document, or a mask while redaction is on
+func (s EncryptedDocument) LogValue() slog.Value {
+	return logValue(documentMask, s.Raw, s.Raw == nil)
+}
+
+// PRIVATE
+
+func logValue(masked string, raw interface{}, null bool) slog.Value {
+	switch {
+	case redacting():
+		return slog.StringValue(masked)
+	case null:
+		return slog.AnyValue(nil)
+	}
+
+	return slog.AnyValue(raw)
+}
@@ -0,0 +1,32 @@
+//go:build go1.21
+
+package cryptypes_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+)
+
+func TestRedactionLogValue(t *testing.T) {
+	var out bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&out, nil))
+	ssn := cryptypes.EncryptedString{Raw: "111-11-1111"}
+
+	log.Info("plain", "ssn", ssn)
+	if !strings.Contains(out.String(), "ssn=111-11-1111") {
+		t.Errorf("Expected the raw value to be logged; got %v instead", out.String())
+	}
+
+	cryptypes.SetRedaction(true)
+	defer cryptypes.SetRedaction(false)
+
+	out.Reset()
+	log.Info("redacted", "ssn", ssn)
+	if !strings.Contains(out.String(), "ssn=[encrypted:string]") {
+		t.Errorf("Expected the value to be redacted; got %v instead", out.String())
+	}
+}
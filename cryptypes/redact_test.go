@@ -0,0 +1,68 @@
+package cryptypes_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+)
+
+type redactTestStruct struct {
+	Name  cryptypes.SignedString
+	SSN   cryptypes.EncryptedString
+	Age   cryptypes.NullDeterministicEncryptedInt
+	Notes cryptypes.NullSignedEncrypted[[]string]
+}
+
+func TestFormatting(t *testing.T) {
+	count := cryptypes.EncryptedInt{Raw: 42}
+	for format, expected := range map[string]string{
+		"%v":   "42",
+		"%5d":  "   42",
+		"%-4x": "2a  ",
+		"%#v":  "cryptypes.Encrypted[int]{Raw:42}",
+		"%s":   "%!s(int=42)",
+	} {
+		if actual := fmt.Sprintf(format, count); actual != expected {
+			t.Errorf("Expected %q; got %q instead", expected, actual)
+		}
+	}
+
+	empty := cryptypes.NullEncryptedString{Empty: true}
+	if actual := empty.String(); actual != "<nil>" {
+		t.Errorf("Expected %q; got %q instead", "<nil>", actual)
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	cryptypes.SetRedaction(true)
+	defer cryptypes.SetRedaction(false)
+
+	in := redactTestStruct{
+		Name:  cryptypes.SignedString{Raw: "Alice"},
+		SSN:   cryptypes.EncryptedString{Raw: "111-11-1111"},
+		Age:   cryptypes.NullDeterministicEncryptedInt{Raw: 30},
+		Notes: cryptypes.NullSignedEncrypted[[]string]{Raw: []string{"secret"}},
+	}
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		actual := fmt.Sprintf(format, in)
+		if strings.Contains(actual, "111-11-1111") || strings.Contains(actual, "30") || strings.Contains(actual, "secret") || strings.Contains(actual, "Alice") {
+			t.Errorf("Expected %s to redact encrypted and signed values; got %v instead", format, actual)
+		}
+	}
+
+	for expected, actual := range map[string]string{
+		"[encrypted:string]":          in.SSN.String(),
+		"[signed:string]":             fmt.Sprintf("%+v", in.Name),
+		"[signed:int]":                cryptypes.NullSignedInt{Raw: 30}.GoString(),
+		"[deterministic:int]":         in.Age.GoString(),
+		"[signed+encrypted:[]string]": fmt.Sprint(in.Notes),
+		"[encrypted:any]":             fmt.Sprintf("%q", cryptypes.EncryptedAny{Raw: "x"}),
+		"[encrypted:document]":        fmt.Sprint(cryptypes.EncryptedDocument{Raw: "x"}),
+	} {
+		if actual != expected {
+			t.Errorf("Expected %q; got %q instead", expected, actual)
+		}
+	}
+}
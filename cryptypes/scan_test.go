@@ -0,0 +1,218 @@
+package cryptypes_test
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	gc "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+)
+
+// scanDialects stands in for the drivers of each dialect, converting a stored value into each of the types they're known to return for it
+var scanDialects = map[string][]func([]byte) interface{}{
+	"bigquery":   {bytesValue},
+	"clickhouse": {stringValue},
+	"mysql":      {bytesValue, stringValue, rawBytesValue},
+	"postgres":   {bytesValue},
+	"sqlite":     {bytesValue, stringValue},
+	"sqlserver":  {bytesValue, stringValue},
+}
+
+func bytesValue(stored []byte) interface{} {
+	return append([]byte(nil), stored...)
+}
+
+func stringValue(stored []byte) interface{} {
+	return string(stored)
+}
+
+func rawBytesValue(stored []byte) interface{} {
+	return sql.RawBytes(append([]byte(nil), stored...))
+}
+
+// scanTypes pairs a value of each type with a constructor for an empty value to scan it into
+func scanTypes() map[string]struct {
+	value driver.Valuer
+	empty func() sql.Scanner
+} {
+	return map[string]struct {
+		value driver.Valuer
+		empty func() sql.Scanner
+	}{
+		"Encrypted":                  {cryptypes.EncryptedString{Raw: "Test"}, func() sql.Scanner { return &cryptypes.EncryptedString{} }},
+		"NullEncrypted":              {cryptypes.NullEncryptedString{Raw: "Test"}, func() sql.Scanner { return &cryptypes.NullEncryptedString{} }},
+		"DeterministicEncrypted":     {cryptypes.DeterministicEncryptedString{Raw: "Test"}, func() sql.Scanner { return &cryptypes.DeterministicEncryptedString{} }},
+		"NullDeterministicEncrypted": {cryptypes.NullDeterministicEncryptedString{Raw: "Test"}, func() sql.Scanner { return &cryptypes.NullDeterministicEncryptedString{} }},
+		"Signed":                     {cryptypes.SignedString{Raw: "Test"}, func() sql.Scanner { return &cryptypes.SignedString{} }},
+		"NullSigned":                 {cryptypes.NullSignedString{Raw: "Test"}, func() sql.Scanner { return &cryptypes.NullSignedString{} }},
+		"SignedEncrypted":            {cryptypes.SignedEncryptedString{Raw: "Test"}, func() sql.Scanner { return &cryptypes.SignedEncryptedString{} }},
+		"NullSignedEncrypted":        {cryptypes.NullSignedEncryptedString{Raw: "Test"}, func() sql.Scanner { return &cryptypes.NullSignedEncryptedString{} }},
+		"EncryptedDocument":          {cryptypes.EncryptedDocument{Raw: profile{FullName: "Test", Age: 42}}, func() sql.Scanner { return &cryptypes.EncryptedDocument{} }},
+	}
+}
+
+func TestScanDialects(t *testing.T) {
+	for dialect, conversions := range scanDialects {
+		for name, test := range scanTypes() {
+			t.Run(dialect+"/"+name, func(t *testing.T) {
+				stored, err := test.value.Value()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				for _, convert := range conversions {
+					actual := test.empty()
+					if err := actual.Scan(convert(stored.([]byte))); err != nil {
+						t.Fatalf("Scanning %T: %v", convert(stored.([]byte)), err)
+					}
+
+					expected := reflect.ValueOf(test.value).FieldByName("Raw").Interface()
+					if raw := reflect.ValueOf(actual).Elem().FieldByName("Raw").Interface(); !reflect.DeepEqual(raw, expected) {
+						t.Errorf("Expected %v; got %v instead", expected, raw)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestScanNull(t *testing.T) {
+	for name, test := range scanTypes() {
+		t.Run(name, func(t *testing.T) {
+			stored, err := test.value.Value()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := test.empty()
+			if err := actual.Scan(stored); err != nil {
+				t.Fatal(err)
+			}
+			if err := actual.Scan(nil); err != nil {
+				t.Fatal(err)
+			}
+
+			value := reflect.ValueOf(actual).Elem()
+			if raw := value.FieldByName("Raw"); !raw.IsZero() {
+				t.Errorf("Expected a zero value; got %v instead", raw.Interface())
+			}
+			if empty := value.FieldByName("Empty"); empty.IsValid() && !empty.Bool() {
+				t.Error("Expected NULL to be read as empty")
+			}
+			if scanned := actual.(interface{ ScannedValue() interface{} }).ScannedValue(); scanned != nil {
+				t.Errorf("Expected no scanned value; got %v instead", scanned)
+			}
+
+			if err := actual.Scan(stored); err != nil {
+				t.Fatal(err)
+			}
+			if empty := value.FieldByName("Empty"); empty.IsValid() && empty.Bool() {
+				t.Error("Expected a value scanned after NULL not to be empty")
+			}
+		})
+	}
+}
+
+func TestScanUnsupported(t *testing.T) {
+	for name, test := range scanTypes() {
+		t.Run(name, func(t *testing.T) {
+			var scanErr *cryptypes.ScanError
+			if err := test.empty().Scan(int64(42)); !errors.As(err, &scanErr) {
+				t.Fatalf("Expected a ScanError; got %v instead", err)
+			}
+			if scanErr.Value != int64(42) {
+				t.Errorf("Expected %v; got %v instead", int64(42), scanErr.Value)
+			}
+		})
+	}
+
+	var scanErr *cryptypes.ScanError
+	if err := (&cryptypes.EncryptedStream{}).Scan(42.0); !errors.As(err, &scanErr) {
+		t.Errorf("Expected a ScanError; got %v instead", err)
+	}
+}
+
+func TestScanStream(t *testing.T) {
+	stored, err := cryptypes.NewEncryptedStream(bytes.NewReader([]byte("Test"))).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for dialect, conversions := range scanDialects {
+		for _, convert := range conversions {
+			var actual cryptypes.EncryptedStream
+			if err := actual.Scan(convert(stored.([]byte))); err != nil {
+				t.Fatalf("%s: %v", dialect, err)
+			}
+			r, err := actual.Open()
+			if err != nil {
+				t.Fatalf("%s: %v", dialect, err)
+			}
+			if plain, err := io.ReadAll(r); err != nil || string(plain) != "Test" {
+				t.Errorf("%s: expected %v; got %v (%v) instead", dialect, "Test", string(plain), err)
+			}
+		}
+	}
+
+	text := cryptypes.NewEncryptedStream(bytes.NewReader([]byte("Test")))
+	text.BindStorage(gc.StorageText)
+	if stored, err = text.Value(); err != nil {
+		t.Fatal(err)
+	}
+	var scanned cryptypes.EncryptedStream
+	if err := scanned.Scan(stored); err != nil {
+		t.Fatal(err)
+	}
+	if r, err := scanned.Open(); err != nil {
+		t.Fatal(err)
+	} else if plain, err := io.ReadAll(r); err != nil || string(plain) != "Test" {
+		t.Errorf("Expected %v; got %v (%v) instead", "Test", string(plain), err)
+	}
+
+	var actual cryptypes.EncryptedStream
+	if err := actual.Scan(nil); err != nil || actual.ScannedValue() != nil {
+		t.Errorf("Expected NULL to be read as no value; got %v (%v) instead", actual.ScannedValue(), err)
+	}
+}
+
+func TestScanStorage(t *testing.T) {
+	for _, storage := range []gc.Storage{gc.StorageText, gc.StorageJSON} {
+		for name, test := range scanTypes() {
+			t.Run(storage.String()+"/"+name, func(t *testing.T) {
+				value := reflect.New(reflect.TypeOf(test.value))
+				value.Elem().Set(reflect.ValueOf(test.value))
+				value.Interface().(gc.StorageBinder).BindStorage(storage)
+
+				stored, err := value.Interface().(driver.Valuer).Value()
+				if err != nil {
+					t.Fatal(err)
+				}
+				text, ok := stored.(string)
+				if !ok {
+					t.Fatalf("Expected a string; got %T instead", stored)
+				}
+				if storage == gc.StorageJSON && !json.Valid([]byte(text)) {
+					t.Errorf("Expected a JSON string; got %q instead", text)
+				}
+
+				for _, convert := range []func([]byte) interface{}{bytesValue, stringValue, rawBytesValue} {
+					actual := test.empty()
+					if err := actual.Scan(convert([]byte(text))); err != nil {
+						t.Fatal(err)
+					}
+
+					expected := reflect.ValueOf(test.value).FieldByName("Raw").Interface()
+					if raw := reflect.ValueOf(actual).Elem().FieldByName("Raw").Interface(); !reflect.DeepEqual(raw, expected) {
+						t.Errorf("Expected %v; got %v instead", expected, raw)
+					}
+				}
+			})
+		}
+	}
+}
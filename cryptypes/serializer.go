@@ -0,0 +1,209 @@
+package cryptypes
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+
+	gc "github.com/danhunsaker/gorm-crypto"
+	"gorm.io/gorm/schema"
+)
+
+func init() {
+	schema.RegisterSerializer("encrypted", EncryptedSerializer{})
+	schema.RegisterSerializer("signed", SignedSerializer{})
+	schema.RegisterSerializer("signedencrypted", SignedEncryptedSerializer{})
+}
+
+// SignatureRecorder is implemented by models which want to know whether the signatures of their signed serializer fields are valid.
+// Models which don't implement it get an error instead, when reading a field whose signature is invalid under the SignatureFlag policy.
+type SignatureRecorder interface {
+	// RecordSignature records whether the signature of the named field was valid as it was read
+	RecordSignature(field string, valid bool)
+}
+
+// Signatures implements SignatureRecorder; embed it in a model, tagged with `gorm:"-"`, to check each field's signature after reading it
+type Signatures map[string]bool
+
+// RecordSignature records whether the signature of the named field was valid as it was read
+func (s *Signatures) RecordSignature(field string, valid bool) {
+	if *s == nil {
+		*s = make(Signatures)
+	}
+	(*s)[field] = valid
+}
+
+// Valid reports whether the named field's signature was valid when it was last read
+func (s Signatures) Valid(field string) bool {
+	return s[field]
+}
+
+// EncryptedSerializer encrypts plain fields tagged with `gorm:"serializer:encrypted"`, just as the Encrypted types do
+type EncryptedSerializer struct{}
+
+// Scan converts the value from the DB into the field's own type
+func (EncryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	return scanSerialized(ctx, field, dst, dbValue, false, func(config gc.Config, source []byte, dest interface{}) (bool, error) {
+		return true, decrypt(ctx, config, source, dest, nil)
+	})
+}
+
+// Value converts the field's value into a value that can safely be stored in the DB
+func (EncryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	return serializedValue(ctx, field, fieldValue, func(config gc.Config, value interface{}) (driver.Value, error) {
+		return encrypt(ctx, config, value, nil)
+	})
+}
+
+// SignedSerializer signs plain fields tagged with `gorm:"serializer:signed"`, just as the Signed types do
+type SignedSerializer struct{}
+
+// Scan converts the value from the DB into the field's own type, reporting its signature's validity to the model if it's a SignatureRecorder
+func (SignedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	return scanSerialized(ctx, field, dst, dbValue, true, verify)
+}
+
+// Value converts the field's value into a value that can safely be stored in the DB
+func (SignedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	return serializedValue(ctx, field, fieldValue, sign)
+}
+
+// SignedEncryptedSerializer signs and encrypts plain fields tagged with `gorm:"serializer:signedencrypted"`, just as the SignedEncrypted types do
+type SignedEncryptedSerializer struct{}
+
+// Scan converts the value from the DB into the field's own type, reporting its signature's validity to the model if it's a SignatureRecorder
+func (SignedEncryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	return scanSerialized(ctx, field, dst, dbValue, true, func(config gc.Config, source []byte, dest interface{}) (bool, error) {
+		return decryptVerify(ctx, config, source, dest, nil)
+	})
+}
+
+// Value converts the field's value into a value that can safely be stored in the DB
+func (SignedEncryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	return serializedValue(ctx, field, fieldValue, func(config gc.Config, value interface{}) (driver.Value, error) {
+		return encryptSign(ctx, config, value, nil)
+	})
+}
+
+// PRIVATE
+
+// contextConfig returns the Config carried by a Statement's context, falling back to the global one, and whether one is actually available
+func contextConfig(ctx context.Context) (gc.Config, bool) {
+	if config, ok := gc.ConfigFromContext(ctx); ok {
+		return config, true
+	}
+
+	global := gc.GlobalConfig()
+	return global, len(global.Setups) > 0
+}
+
+// scanSerialized reads a DB value into a serializer field, recording its signature's validity if it's signed.
+// NULLs, and nil pointers, are read as the zero value of the field's type.
+func scanSerialized(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}, signed bool, read func(gc.Config, []byte, interface{}) (bool, error)) error {
+	target := reflect.New(field.FieldType)
+
+	source, ok := scanSource(dbValue)
+	if !ok {
+		return &ScanError{Value: dbValue, Target: field.Schema.Name + "." + field.Name}
+	}
+
+	if source != nil {
+		config, ok := contextConfig(ctx)
+		if !ok {
+			return errNoConfig
+		}
+
+		dest, finish := serialTarget(target)
+		valid, err := read(config, source, dest)
+		if err == nil {
+			err = finish()
+		}
+		if err != nil {
+			return err
+		}
+
+		if signed {
+			if err := serializedSignature(config, field, dst, target, valid); err != nil {
+				return err
+			}
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(target.Elem())
+	return nil
+}
+
+// serializedValue converts a serializer field's value into a DB value; nil pointers and other nil values are stored as NULL
+func serializedValue(ctx context.Context, field *schema.Field, fieldValue interface{}, write func(gc.Config, interface{}) (driver.Value, error)) (interface{}, error) {
+	if fieldValue == nil {
+		return nil, nil
+	}
+	switch v := reflect.ValueOf(fieldValue); v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+	}
+
+	config, ok := contextConfig(ctx)
+	if !ok {
+		return nil, errNoConfig
+	}
+
+	storage, ok, err := gc.FieldStorage(field)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		storage = config.CurrentSetup().Storage
+	}
+
+	serial, err := serialValue(field.FieldType, fieldValue)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := write(config, serial)
+	return stored(storage, out), err
+}
+
+// serializedSignature applies the SignaturePolicy a field is tagged with, or that of its Config, to a value read into target whose signature is invalid,
+// then reports its signature validity to its model, if the model is a SignatureRecorder.
+// Under the SignatureFlag policy, invalid signatures fail the read unless the model records them.
+func serializedSignature(config gc.Config, field *schema.Field, dst, target reflect.Value, valid bool) error {
+	policy, ok, err := gc.FieldSignaturePolicy(field)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		policy = config.SignaturePolicy
+	}
+
+	if !valid {
+		switch policy {
+		case gc.SignatureError:
+			return fmt.Errorf("%s.%s: %w", field.Schema.Name, field.Name, ErrSignatureInvalid)
+		case gc.SignatureZero:
+			target.Elem().Set(reflect.Zero(field.FieldType))
+		case gc.SignatureCallback:
+			if err := signatureHook(config, target.Interface()); err != nil {
+				return err
+			}
+		}
+	}
+
+	if dst.CanAddr() {
+		dst = dst.Addr()
+	}
+	if recorder, ok := dst.Interface().(SignatureRecorder); ok {
+		recorder.RecordSignature(field.Name, valid)
+		return nil
+	}
+
+	if !valid && policy == gc.SignatureFlag {
+		return fmt.Errorf("%s.%s has an invalid signature", field.Schema.Name, field.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package cryptypes_test
+
+import (
+	"errors"
+	"testing"
+
+	gc "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+)
+
+func TestSignaturePolicy(t *testing.T) {
+	signed, err := cryptypes.SignedString{Raw: "trusted"}.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := tamperWith(signed, rawValue("forged"))
+
+	config := gc.GlobalConfig()
+	read := func(policy gc.SignaturePolicy) (cryptypes.SignedString, error) {
+		var actual cryptypes.SignedString
+		config.SignaturePolicy = policy
+		actual.BindConfig(&config)
+		return actual, actual.Scan(forged)
+	}
+
+	actual, err := read(gc.SignatureFlag)
+	if err != nil || actual.Valid || actual.Raw != "forged" || !actual.SignatureFailed() {
+		t.Errorf("Expected a flagged %v; got %+v (%v) instead", "forged", actual, err)
+	}
+
+	if _, err := read(gc.SignatureError); !errors.Is(err, cryptypes.ErrSignatureInvalid) {
+		t.Errorf("Expected %v; got %v instead", cryptypes.ErrSignatureInvalid, err)
+	}
+
+	if actual, err = read(gc.SignatureZero); err != nil || actual.Valid || actual.Raw != "" {
+		t.Errorf("Expected a cleared value; got %+v (%v) instead", actual, err)
+	}
+
+	if _, err := read(gc.SignatureCallback); err == nil {
+		t.Error("Expected an error using the SignatureCallback policy without a hook; got none")
+	}
+	var hooked interface{}
+	config.OnInvalidSignature = func(value interface{}) error {
+		hooked = value
+		value.(*cryptypes.SignedString).Raw = "replaced"
+		return nil
+	}
+	if actual, err = read(gc.SignatureCallback); err != nil || actual.Raw != "replaced" || hooked == nil {
+		t.Errorf("Expected the hook to replace the value; got %+v (%v) instead", actual, err)
+	}
+
+	var bound cryptypes.SignedEncryptedString
+	config.SignaturePolicy = gc.SignatureFlag
+	bound.BindConfig(&config)
+	bound.BindSignaturePolicy(gc.SignatureError)
+	sealed, err := cryptypes.SignedEncryptedString{Raw: "trusted"}.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bound.Scan(sealed); err != nil || !bound.Valid || bound.SignatureFailed() {
+		t.Errorf("Expected a valid signature to be read under any policy; got %+v (%v) instead", bound, err)
+	}
+
+	var null cryptypes.NullSignedString
+	config.SignaturePolicy = gc.SignatureError
+	null.BindConfig(&config)
+	if err := null.Scan(nil); err != nil || !null.Empty {
+		t.Errorf("Expected NULL to be read under any policy; got %+v (%v) instead", null, err)
+	}
+}
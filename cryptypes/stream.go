@@ -0,0 +1,238 @@
+package cryptypes
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	gc "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/encryption"
+)
+
+// EncryptedStream supports encrypting large binary data, such as file uploads, without the extra copies the other types make.
+// Rather than a Raw value, it's written from an io.Reader and read back through one, encrypted and decrypted a chunk at a time,
+// which requires an Encrypter implementing encryption.StreamAlgorithm. Streams aren't serialized, compressed, encoded, or signed.
+// Nothing is streamed to or from the DB itself: database/sql only passes complete values to and from drivers,
+// so Value buffers the whole encrypted value in memory before handing it over, and Scan holds the whole encrypted value it's given.
+// To keep large values out of memory entirely, store them elsewhere with EncryptTo, and read them back with DecryptFrom.
+type EncryptedStream struct {
+	Field
+	reader *streamSource
+}
+
+// NewEncryptedStream creates an EncryptedStream which stores everything read from r
+func NewEncryptedStream(r io.Reader) EncryptedStream {
+	var s EncryptedStream
+	s.SetReader(r)
+
+	return s
+}
+
+// SetReader replaces the value with everything read from r, which is read to the end when the value is stored.
+// Storing the value again - as a gormcrypto.Plugin does to bind new rows to their primary keys - requires r to be an io.Seeker.
+func (s *EncryptedStream) SetReader(r io.Reader) {
+	s.reader = &streamSource{Reader: r}
+}
+
+// Assign replaces the value with an io.Reader, a []byte, or a string; nil values are stored as NULL
+func (s *EncryptedStream) Assign(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		s.reader = nil
+		s.scanned(nil)
+	case io.Reader:
+		s.SetReader(v)
+	case []byte:
+		s.SetReader(bytes.NewReader(v))
+	case string:
+		s.SetReader(strings.NewReader(v))
+	default:
+		return fmt.Errorf("can't assign %T to an EncryptedStream", value)
+	}
+
+	return nil
+}
+
+// Scan keeps the encrypted value from the DB so it can be decrypted through Open; nothing is decrypted until then
+func (s *EncryptedStream) Scan(value interface{}) error {
+	source, ok := s.scanValue(value)
+	if !ok {
+		return &ScanError{Value: value, Target: fmt.Sprintf("%T", s)}
+	}
+
+	s.reader = nil
+	s.scanned(source)
+	return nil
+}
+
+// Value converts an initialized EncryptedStream value into a value that can safely be stored in the DB.
+// The whole encrypted value is buffered in memory, since drivers only accept complete values.
+// Values scanned from the DB are stored again as they are, unless they were written by an older Setup, or need binding to their row.
+func (s EncryptedStream) Value() (driver.Value, error) {
+	if s.reader == nil && s.source() == nil {
+		return nil, nil
+	}
+	config, _ := s.config()
+	if s.reader == nil && s.current() {
+		return stored(s.storage(config), s.source()), nil
+	}
+
+	var out bytes.Buffer
+	if err := s.EncryptTo(&out); err != nil {
+		return nil, err
+	}
+
+	return stored(s.storage(config), out.Bytes()), nil
+}
+
+// Open returns a Reader which decrypts the value scanned from the DB, a chunk at a time. NULL values read as empty.
+func (s EncryptedStream) Open() (io.Reader, error) {
+	if s.source() == nil {
+		return bytes.NewReader(nil), nil
+	}
+
+	return s.DecryptFrom(bytes.NewReader(s.source()))
+}
+
+// EncryptTo encrypts the value, writing it to w a chunk at a time, exactly as it would be stored in the DB in a binary Storage
+func (s EncryptedStream) EncryptTo(w io.Writer) error {
+	config, ok := s.config()
+	if !ok {
+		return errNoConfig
+	}
+
+	plain, err := s.plaintext()
+	if err != nil {
+		return err
+	}
+
+	return encryptStream(config, w, plain, s.BoundContext())
+}
+
+// DecryptFrom returns a Reader which decrypts a value read from r - exactly as it's stored in the DB in a binary Storage - a chunk at a time
+func (s EncryptedStream) DecryptFrom(r io.Reader) (io.Reader, error) {
+	config, ok := s.config()
+	if !ok {
+		return nil, errNoConfig
+	}
+
+	return decryptStream(config, r, s.BoundContext(), s.contextRequired())
+}
+
+// PRIVATE
+
+// streamSource tracks whether an EncryptedStream's Reader has been read yet, and is shared by copies of the EncryptedStream
+type streamSource struct {
+	io.Reader
+	read bool
+}
+
+// rewind readies the Reader to be read from the start, which is only possible more than once for an io.Seeker
+func (s *streamSource) rewind() (io.Reader, error) {
+	if s.read {
+		seeker, ok := s.Reader.(io.Seeker)
+		if !ok {
+			return nil, errors.New("stream has already been read, and isn't an io.Seeker, so it can't be read again")
+		}
+		if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+	s.read = true
+
+	return s.Reader, nil
+}
+
+// plaintext returns the Reader to store, or a Reader decrypting the scanned value to re-encrypt it
+func (s EncryptedStream) plaintext() (io.Reader, error) {
+	if s.reader != nil {
+		return s.reader.rewind()
+	}
+
+	return s.Open()
+}
+
+// current reports whether the scanned value was written by the active Setup, and bound to its row if it should be
+func (s EncryptedStream) current() bool {
+	config, ok := s.config()
+	if !ok {
+		return true
+	}
+	setup, err := config.ActiveSetup()
+	if err != nil {
+		return true
+	}
+
+	var in Envelope
+	if err := in.UnmarshalBinary(s.source()); err != nil {
+		return false
+	}
+
+	return in.SetupID == setup.Identifier() && (in.Flags&FlagContextBound != 0) == (s.BoundContext() != nil)
+}
+
+// encryptStream encrypts everything read from plain onto w, after the Envelope describing it
+func encryptStream(config gc.Config, w io.Writer, plain io.Reader, associated []byte) error {
+	setup, err := config.ActiveSetup()
+	if err != nil {
+		return err
+	}
+	stream, ok := setup.Encrypter.(encryption.StreamAlgorithm)
+	if !ok {
+		return fmt.Errorf("setup %q can't encrypt streams; its encrypter doesn't implement encryption.StreamAlgorithm", setup.Identifier())
+	}
+
+	out := Envelope{Kind: KindEncryptedStream, SetupID: setup.Identifier(), At: time.Now()}
+	if associated != nil {
+		out.Flags |= FlagContextBound
+	}
+	header, err := out.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	crypted, err := stream.EncryptStream(w, associated)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(crypted, plain); err != nil {
+		return err
+	}
+
+	return crypted.Close()
+}
+
+// decryptStream reads the Envelope from the start of r, and returns a Reader decrypting the stream after it, refusing it if it isn't bound to its row but required is set
+func decryptStream(config gc.Config, r io.Reader, associated []byte, required bool) (io.Reader, error) {
+	in, crypted, err := ReadEnvelope(r)
+	if err != nil {
+		return nil, err
+	}
+
+	setup, err := envelopeSetup(config, in, KindEncryptedStream)
+	if err != nil {
+		return nil, err
+	}
+	stream, ok := setup.Encrypter.(encryption.StreamAlgorithm)
+	if !ok {
+		return nil, fmt.Errorf("setup %q can't decrypt streams; its encrypter doesn't implement encryption.StreamAlgorithm", setup.Identifier())
+	}
+
+	if in.Flags&FlagContextBound == 0 {
+		if required {
+			return nil, ErrContextUnbound
+		}
+		associated = nil
+	} else if associated == nil {
+		return nil, errNoContext
+	}
+
+	return stream.DecryptStream(crypted, associated)
+}
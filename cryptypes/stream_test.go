@@ -0,0 +1,82 @@
+package cryptypes_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+	"github.com/danhunsaker/gorm-crypto/encryption"
+)
+
+func TestEncryptedStream(t *testing.T) {
+	expected := make([]byte, 3*encryption.StreamChunkSize+5)
+	rand.Read(expected)
+
+	stream := cryptypes.NewEncryptedStream(bytes.NewReader(expected))
+	stored, err := stream.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := stream.Value()
+	if err != nil {
+		t.Fatalf("Expected a seekable stream to be stored again; got %v", err)
+	}
+	if bytes.Equal(stored.([]byte), again.([]byte)) {
+		t.Error("Expected every stored stream to be encrypted differently")
+	}
+
+	var envelope cryptypes.Envelope
+	if err := envelope.UnmarshalBinary(stored.([]byte)); err != nil {
+		t.Fatal(err)
+	}
+	if envelope.Kind != cryptypes.KindEncryptedStream || len(envelope.Raw) > 0 {
+		t.Errorf("Expected an empty %v envelope; got %v with %d bytes instead", cryptypes.KindEncryptedStream, envelope.Kind, len(envelope.Raw))
+	}
+
+	var actual cryptypes.EncryptedStream
+	if err := actual.Scan(stored); err != nil {
+		t.Fatal(err)
+	}
+	r, err := actual.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain, err := io.ReadAll(r); err != nil || !bytes.Equal(plain, expected) {
+		t.Errorf("Expected %d bytes; got %d different bytes (and %v) instead", len(expected), len(plain), err)
+	}
+
+	if restored, err := actual.Value(); err != nil || !bytes.Equal(restored.([]byte), stored.([]byte)) {
+		t.Errorf("Expected an unchanged stream to be stored as-is; got %v", err)
+	}
+
+	var streamed bytes.Buffer
+	if err := stream.EncryptTo(&streamed); err != nil {
+		t.Fatal(err)
+	}
+	r, err = actual.DecryptFrom(iotest.HalfReader(&streamed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain, err := io.ReadAll(r); err != nil || !bytes.Equal(plain, expected) {
+		t.Errorf("Expected %d bytes; got %d different bytes (and %v) instead", len(expected), len(plain), err)
+	}
+
+	once := cryptypes.NewEncryptedStream(iotest.OneByteReader(bytes.NewReader(expected)))
+	if _, err := once.Value(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := once.Value(); err == nil {
+		t.Error("Expected an error storing a stream which can't be read again; got none")
+	}
+
+	var null cryptypes.EncryptedStream
+	if err := null.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := null.Value(); value != nil || err != nil {
+		t.Errorf("Expected a NULL value; got %v (and %v) instead", value, err)
+	}
+}
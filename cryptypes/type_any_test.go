@@ -104,6 +104,96 @@ func TestNullEncryptedAnyUnset(t *testing.T) {
 	}
 }
 
+func TestDeterministicEncryptedAny(t *testing.T) {
+	var out testStruct
+	expected := cryptypes.DeterministicEncryptedAny{
+		Raw: &in,
+	}
+	actual := cryptypes.DeterministicEncryptedAny{
+		Raw: &out,
+	}
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !actual.Raw.(*testStruct).Equals(*expected.Raw.(*testStruct)) {
+		t.Errorf("Expected raw = %v; got %v", *expected.Raw.(*testStruct), *actual.Raw.(*testStruct))
+	}
+}
+
+func TestDeterministicEncryptedAnyUnset(t *testing.T) {
+	var out testStruct
+	expected := cryptypes.DeterministicEncryptedAny{
+		Raw: &unset,
+	}
+	actual := cryptypes.DeterministicEncryptedAny{
+		Raw: &out,
+	}
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !actual.Raw.(*testStruct).Equals(*expected.Raw.(*testStruct)) {
+		t.Errorf("Expected raw = %v; got %v", *expected.Raw.(*testStruct), *actual.Raw.(*testStruct))
+	}
+}
+
+func TestNullDeterministicEncryptedAny(t *testing.T) {
+	var out testStruct
+	expected := cryptypes.NullDeterministicEncryptedAny{
+		Raw: &in,
+	}
+	actual := cryptypes.NullDeterministicEncryptedAny{
+		Raw: &out,
+	}
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !actual.Raw.(*testStruct).Equals(*expected.Raw.(*testStruct)) {
+		t.Errorf("Expected raw = %v; got %v", *expected.Raw.(*testStruct), *actual.Raw.(*testStruct))
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
+func TestNullDeterministicEncryptedAnyUnset(t *testing.T) {
+	var out testStruct
+	expected := cryptypes.NullDeterministicEncryptedAny{
+		Raw: &unset,
+	}
+	actual := cryptypes.NullDeterministicEncryptedAny{
+		Raw: &out,
+	}
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !actual.Raw.(*testStruct).Equals(*expected.Raw.(*testStruct)) {
+		t.Errorf("Expected raw = %v; got %v", *expected.Raw.(*testStruct), *actual.Raw.(*testStruct))
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
 func TestNullEncryptedAnyEmpty(t *testing.T) {
 	var out testStruct
 	expected := cryptypes.NullEncryptedAny{
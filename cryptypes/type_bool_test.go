@@ -80,6 +80,80 @@ func TestNullEncryptedBoolUnset(t *testing.T) {
 	}
 }
 
+func TestDeterministicEncryptedBool(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedBool{
+		Raw: true,
+	}
+	var actual cryptypes.DeterministicEncryptedBool
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestDeterministicEncryptedBoolUnset(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedBool{}
+	var actual cryptypes.DeterministicEncryptedBool
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestNullDeterministicEncryptedBool(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedBool{
+		Raw: true,
+	}
+	var actual cryptypes.NullDeterministicEncryptedBool
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
+func TestNullDeterministicEncryptedBoolUnset(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedBool{}
+	var actual cryptypes.NullDeterministicEncryptedBool
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
 func TestNullEncryptedBoolEmpty(t *testing.T) {
 	expected := cryptypes.NullEncryptedBool{
 		Raw:   true,
@@ -81,6 +81,80 @@ func TestNullEncryptedByteSliceUnset(t *testing.T) {
 	}
 }
 
+func TestDeterministicEncryptedByteSlice(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedByteSlice{
+		Raw: []byte("Test"),
+	}
+	var actual cryptypes.DeterministicEncryptedByteSlice
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !bytes.Equal(actual.Raw, expected.Raw) {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestDeterministicEncryptedByteSliceUnset(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedByteSlice{}
+	var actual cryptypes.DeterministicEncryptedByteSlice
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !bytes.Equal(actual.Raw, expected.Raw) {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestNullDeterministicEncryptedByteSlice(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedByteSlice{
+		Raw: []byte("Test"),
+	}
+	var actual cryptypes.NullDeterministicEncryptedByteSlice
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !bytes.Equal(actual.Raw, expected.Raw) {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
+func TestNullDeterministicEncryptedByteSliceUnset(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedByteSlice{}
+	var actual cryptypes.NullDeterministicEncryptedByteSlice
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !bytes.Equal(actual.Raw, expected.Raw) {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
 func TestNullEncryptedByteSliceEmpty(t *testing.T) {
 	expected := cryptypes.NullEncryptedByteSlice{
 		Raw:   nil,
@@ -80,6 +80,80 @@ func TestNullEncryptedByteUnset(t *testing.T) {
 	}
 }
 
+func TestDeterministicEncryptedByte(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedByte{
+		Raw: 42,
+	}
+	var actual cryptypes.DeterministicEncryptedByte
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestDeterministicEncryptedByteUnset(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedByte{}
+	var actual cryptypes.DeterministicEncryptedByte
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestNullDeterministicEncryptedByte(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedByte{
+		Raw: 42,
+	}
+	var actual cryptypes.NullDeterministicEncryptedByte
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
+func TestNullDeterministicEncryptedByteUnset(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedByte{}
+	var actual cryptypes.NullDeterministicEncryptedByte
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
 func TestNullEncryptedByteEmpty(t *testing.T) {
 	expected := cryptypes.NullEncryptedByte{
 		Raw:   42,
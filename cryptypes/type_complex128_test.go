@@ -80,6 +80,80 @@ func TestNullEncryptedComplex128Unset(t *testing.T) {
 	}
 }
 
+func TestDeterministicEncryptedComplex128(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedComplex128{
+		Raw: 42i,
+	}
+	var actual cryptypes.DeterministicEncryptedComplex128
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestDeterministicEncryptedComplex128Unset(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedComplex128{}
+	var actual cryptypes.DeterministicEncryptedComplex128
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestNullDeterministicEncryptedComplex128(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedComplex128{
+		Raw: 42i,
+	}
+	var actual cryptypes.NullDeterministicEncryptedComplex128
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
+func TestNullDeterministicEncryptedComplex128Unset(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedComplex128{}
+	var actual cryptypes.NullDeterministicEncryptedComplex128
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
 func TestNullEncryptedComplex128Empty(t *testing.T) {
 	expected := cryptypes.NullEncryptedComplex128{
 		Raw:   42i,
@@ -80,6 +80,80 @@ func TestNullEncryptedComplex64Unset(t *testing.T) {
 	}
 }
 
+func TestDeterministicEncryptedComplex64(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedComplex64{
+		Raw: 42i,
+	}
+	var actual cryptypes.DeterministicEncryptedComplex64
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestDeterministicEncryptedComplex64Unset(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedComplex64{}
+	var actual cryptypes.DeterministicEncryptedComplex64
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestNullDeterministicEncryptedComplex64(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedComplex64{
+		Raw: 42i,
+	}
+	var actual cryptypes.NullDeterministicEncryptedComplex64
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
+func TestNullDeterministicEncryptedComplex64Unset(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedComplex64{}
+	var actual cryptypes.NullDeterministicEncryptedComplex64
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
 func TestNullEncryptedComplex64Empty(t *testing.T) {
 	expected := cryptypes.NullEncryptedComplex64{
 		Raw:   42i,
@@ -80,6 +80,80 @@ func TestNullEncryptedFloat32Unset(t *testing.T) {
 	}
 }
 
+func TestDeterministicEncryptedFloat32(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedFloat32{
+		Raw: 42,
+	}
+	var actual cryptypes.DeterministicEncryptedFloat32
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestDeterministicEncryptedFloat32Unset(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedFloat32{}
+	var actual cryptypes.DeterministicEncryptedFloat32
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestNullDeterministicEncryptedFloat32(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedFloat32{
+		Raw: 42,
+	}
+	var actual cryptypes.NullDeterministicEncryptedFloat32
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
+func TestNullDeterministicEncryptedFloat32Unset(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedFloat32{}
+	var actual cryptypes.NullDeterministicEncryptedFloat32
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
 func TestNullEncryptedFloat32Empty(t *testing.T) {
 	expected := cryptypes.NullEncryptedFloat32{
 		Raw:   42,
@@ -80,6 +80,80 @@ func TestNullEncryptedFloat64Unset(t *testing.T) {
 	}
 }
 
+func TestDeterministicEncryptedFloat64(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedFloat64{
+		Raw: 42,
+	}
+	var actual cryptypes.DeterministicEncryptedFloat64
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestDeterministicEncryptedFloat64Unset(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedFloat64{}
+	var actual cryptypes.DeterministicEncryptedFloat64
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestNullDeterministicEncryptedFloat64(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedFloat64{
+		Raw: 42,
+	}
+	var actual cryptypes.NullDeterministicEncryptedFloat64
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
+func TestNullDeterministicEncryptedFloat64Unset(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedFloat64{}
+	var actual cryptypes.NullDeterministicEncryptedFloat64
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
 func TestNullEncryptedFloat64Empty(t *testing.T) {
 	expected := cryptypes.NullEncryptedFloat64{
 		Raw:   42,
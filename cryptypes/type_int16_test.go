@@ -80,6 +80,80 @@ func TestNullEncryptedInt16Unset(t *testing.T) {
 	}
 }
 
+func TestDeterministicEncryptedInt16(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedInt16{
+		Raw: 42,
+	}
+	var actual cryptypes.DeterministicEncryptedInt16
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestDeterministicEncryptedInt16Unset(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedInt16{}
+	var actual cryptypes.DeterministicEncryptedInt16
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestNullDeterministicEncryptedInt16(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedInt16{
+		Raw: 42,
+	}
+	var actual cryptypes.NullDeterministicEncryptedInt16
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
+func TestNullDeterministicEncryptedInt16Unset(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedInt16{}
+	var actual cryptypes.NullDeterministicEncryptedInt16
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
 func TestNullEncryptedInt16Empty(t *testing.T) {
 	expected := cryptypes.NullEncryptedInt16{
 		Raw:   42,
@@ -80,6 +80,80 @@ func TestNullEncryptedInt32Unset(t *testing.T) {
 	}
 }
 
+func TestDeterministicEncryptedInt32(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedInt32{
+		Raw: 42,
+	}
+	var actual cryptypes.DeterministicEncryptedInt32
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestDeterministicEncryptedInt32Unset(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedInt32{}
+	var actual cryptypes.DeterministicEncryptedInt32
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestNullDeterministicEncryptedInt32(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedInt32{
+		Raw: 42,
+	}
+	var actual cryptypes.NullDeterministicEncryptedInt32
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
+func TestNullDeterministicEncryptedInt32Unset(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedInt32{}
+	var actual cryptypes.NullDeterministicEncryptedInt32
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
 func TestNullEncryptedInt32Empty(t *testing.T) {
 	expected := cryptypes.NullEncryptedInt32{
 		Raw:   42,
@@ -80,6 +80,80 @@ func TestNullEncryptedInt64Unset(t *testing.T) {
 	}
 }
 
+func TestDeterministicEncryptedInt64(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedInt64{
+		Raw: 42,
+	}
+	var actual cryptypes.DeterministicEncryptedInt64
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestDeterministicEncryptedInt64Unset(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedInt64{}
+	var actual cryptypes.DeterministicEncryptedInt64
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestNullDeterministicEncryptedInt64(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedInt64{
+		Raw: 42,
+	}
+	var actual cryptypes.NullDeterministicEncryptedInt64
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
+func TestNullDeterministicEncryptedInt64Unset(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedInt64{}
+	var actual cryptypes.NullDeterministicEncryptedInt64
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
 func TestNullEncryptedInt64Empty(t *testing.T) {
 	expected := cryptypes.NullEncryptedInt64{
 		Raw:   42,
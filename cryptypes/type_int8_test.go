@@ -80,6 +80,80 @@ func TestNullEncryptedInt8Unset(t *testing.T) {
 	}
 }
 
+func TestDeterministicEncryptedInt8(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedInt8{
+		Raw: 42,
+	}
+	var actual cryptypes.DeterministicEncryptedInt8
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestDeterministicEncryptedInt8Unset(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedInt8{}
+	var actual cryptypes.DeterministicEncryptedInt8
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestNullDeterministicEncryptedInt8(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedInt8{
+		Raw: 42,
+	}
+	var actual cryptypes.NullDeterministicEncryptedInt8
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
+func TestNullDeterministicEncryptedInt8Unset(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedInt8{}
+	var actual cryptypes.NullDeterministicEncryptedInt8
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
 func TestNullEncryptedInt8Empty(t *testing.T) {
 	expected := cryptypes.NullEncryptedInt8{
 		Raw:   42,
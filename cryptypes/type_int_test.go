@@ -80,6 +80,80 @@ func TestNullEncryptedIntUnset(t *testing.T) {
 	}
 }
 
+func TestDeterministicEncryptedInt(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedInt{
+		Raw: 42,
+	}
+	var actual cryptypes.DeterministicEncryptedInt
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestDeterministicEncryptedIntUnset(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedInt{}
+	var actual cryptypes.DeterministicEncryptedInt
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestNullDeterministicEncryptedInt(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedInt{
+		Raw: 42,
+	}
+	var actual cryptypes.NullDeterministicEncryptedInt
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
+func TestNullDeterministicEncryptedIntUnset(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedInt{}
+	var actual cryptypes.NullDeterministicEncryptedInt
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
 func TestNullEncryptedIntEmpty(t *testing.T) {
 	expected := cryptypes.NullEncryptedInt{
 		Raw:   42,
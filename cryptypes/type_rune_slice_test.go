@@ -81,6 +81,80 @@ func TestNullEncryptedRuneSliceUnset(t *testing.T) {
 	}
 }
 
+func TestDeterministicEncryptedRuneSlice(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedRuneSlice{
+		Raw: []rune("Test"),
+	}
+	var actual cryptypes.DeterministicEncryptedRuneSlice
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !reflect.DeepEqual(actual.Raw, expected.Raw) {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestDeterministicEncryptedRuneSliceUnset(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedRuneSlice{}
+	var actual cryptypes.DeterministicEncryptedRuneSlice
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !reflect.DeepEqual(actual.Raw, expected.Raw) {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestNullDeterministicEncryptedRuneSlice(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedRuneSlice{
+		Raw: []rune("Test"),
+	}
+	var actual cryptypes.NullDeterministicEncryptedRuneSlice
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !reflect.DeepEqual(actual.Raw, expected.Raw) {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
+func TestNullDeterministicEncryptedRuneSliceUnset(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedRuneSlice{}
+	var actual cryptypes.NullDeterministicEncryptedRuneSlice
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !reflect.DeepEqual(actual.Raw, expected.Raw) {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
 func TestNullEncryptedRuneSliceEmpty(t *testing.T) {
 	expected := cryptypes.NullEncryptedRuneSlice{
 		Raw:   nil,
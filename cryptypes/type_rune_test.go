@@ -80,6 +80,80 @@ func TestNullEncryptedRuneUnset(t *testing.T) {
 	}
 }
 
+func TestDeterministicEncryptedRune(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedRune{
+		Raw: 42,
+	}
+	var actual cryptypes.DeterministicEncryptedRune
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestDeterministicEncryptedRuneUnset(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedRune{}
+	var actual cryptypes.DeterministicEncryptedRune
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestNullDeterministicEncryptedRune(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedRune{
+		Raw: 42,
+	}
+	var actual cryptypes.NullDeterministicEncryptedRune
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
+func TestNullDeterministicEncryptedRuneUnset(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedRune{}
+	var actual cryptypes.NullDeterministicEncryptedRune
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
 func TestNullEncryptedRuneEmpty(t *testing.T) {
 	expected := cryptypes.NullEncryptedRune{
 		Raw:   42,
@@ -80,6 +80,80 @@ func TestNullEncryptedStringUnset(t *testing.T) {
 	}
 }
 
+func TestDeterministicEncryptedString(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedString{
+		Raw: "Test",
+	}
+	var actual cryptypes.DeterministicEncryptedString
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestDeterministicEncryptedStringUnset(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedString{}
+	var actual cryptypes.DeterministicEncryptedString
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestNullDeterministicEncryptedString(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedString{
+		Raw: "Test",
+	}
+	var actual cryptypes.NullDeterministicEncryptedString
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
+func TestNullDeterministicEncryptedStringUnset(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedString{}
+	var actual cryptypes.NullDeterministicEncryptedString
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
 func TestNullEncryptedStringEmpty(t *testing.T) {
 	expected := cryptypes.NullEncryptedString{
 		Raw:   "",
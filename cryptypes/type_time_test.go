@@ -81,6 +81,80 @@ func TestNullEncryptedTimeUnset(t *testing.T) {
 	}
 }
 
+func TestDeterministicEncryptedTime(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedTime{
+		Raw: time.Now(),
+	}
+	var actual cryptypes.DeterministicEncryptedTime
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !actual.Raw.Equal(expected.Raw) {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestDeterministicEncryptedTimeUnset(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedTime{}
+	var actual cryptypes.DeterministicEncryptedTime
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !actual.Raw.Equal(expected.Raw) {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestNullDeterministicEncryptedTime(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedTime{
+		Raw: time.Now(),
+	}
+	var actual cryptypes.NullDeterministicEncryptedTime
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !actual.Raw.Equal(expected.Raw) {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
+func TestNullDeterministicEncryptedTimeUnset(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedTime{}
+	var actual cryptypes.NullDeterministicEncryptedTime
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !actual.Raw.Equal(expected.Raw) {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
 func TestNullEncryptedTimeEmpty(t *testing.T) {
 	expected := cryptypes.NullEncryptedTime{
 		Raw:   time.Now(),
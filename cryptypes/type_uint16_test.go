@@ -80,6 +80,80 @@ func TestNullEncryptedUint16Unset(t *testing.T) {
 	}
 }
 
+func TestDeterministicEncryptedUint16(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedUint16{
+		Raw: 42,
+	}
+	var actual cryptypes.DeterministicEncryptedUint16
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestDeterministicEncryptedUint16Unset(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedUint16{}
+	var actual cryptypes.DeterministicEncryptedUint16
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestNullDeterministicEncryptedUint16(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedUint16{
+		Raw: 42,
+	}
+	var actual cryptypes.NullDeterministicEncryptedUint16
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
+func TestNullDeterministicEncryptedUint16Unset(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedUint16{}
+	var actual cryptypes.NullDeterministicEncryptedUint16
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
 func TestNullEncryptedUint16Empty(t *testing.T) {
 	expected := cryptypes.NullEncryptedUint16{
 		Raw:   42,
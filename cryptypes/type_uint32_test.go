@@ -80,6 +80,80 @@ func TestNullEncryptedUint32Unset(t *testing.T) {
 	}
 }
 
+func TestDeterministicEncryptedUint32(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedUint32{
+		Raw: 42,
+	}
+	var actual cryptypes.DeterministicEncryptedUint32
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestDeterministicEncryptedUint32Unset(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedUint32{}
+	var actual cryptypes.DeterministicEncryptedUint32
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestNullDeterministicEncryptedUint32(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedUint32{
+		Raw: 42,
+	}
+	var actual cryptypes.NullDeterministicEncryptedUint32
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
+func TestNullDeterministicEncryptedUint32Unset(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedUint32{}
+	var actual cryptypes.NullDeterministicEncryptedUint32
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
 func TestNullEncryptedUint32Empty(t *testing.T) {
 	expected := cryptypes.NullEncryptedUint32{
 		Raw:   42,
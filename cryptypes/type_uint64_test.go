@@ -80,6 +80,80 @@ func TestNullEncryptedUint64Unset(t *testing.T) {
 	}
 }
 
+func TestDeterministicEncryptedUint64(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedUint64{
+		Raw: 42,
+	}
+	var actual cryptypes.DeterministicEncryptedUint64
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestDeterministicEncryptedUint64Unset(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedUint64{}
+	var actual cryptypes.DeterministicEncryptedUint64
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestNullDeterministicEncryptedUint64(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedUint64{
+		Raw: 42,
+	}
+	var actual cryptypes.NullDeterministicEncryptedUint64
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
+func TestNullDeterministicEncryptedUint64Unset(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedUint64{}
+	var actual cryptypes.NullDeterministicEncryptedUint64
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
 func TestNullEncryptedUint64Empty(t *testing.T) {
 	expected := cryptypes.NullEncryptedUint64{
 		Raw:   42,
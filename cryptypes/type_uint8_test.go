@@ -80,6 +80,80 @@ func TestNullEncryptedUint8Unset(t *testing.T) {
 	}
 }
 
+func TestDeterministicEncryptedUint8(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedUint8{
+		Raw: 42,
+	}
+	var actual cryptypes.DeterministicEncryptedUint8
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestDeterministicEncryptedUint8Unset(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedUint8{}
+	var actual cryptypes.DeterministicEncryptedUint8
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestNullDeterministicEncryptedUint8(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedUint8{
+		Raw: 42,
+	}
+	var actual cryptypes.NullDeterministicEncryptedUint8
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
+func TestNullDeterministicEncryptedUint8Unset(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedUint8{}
+	var actual cryptypes.NullDeterministicEncryptedUint8
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
 func TestNullEncryptedUint8Empty(t *testing.T) {
 	expected := cryptypes.NullEncryptedUint8{
 		Raw:   42,
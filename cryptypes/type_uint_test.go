@@ -80,6 +80,80 @@ func TestNullEncryptedUintUnset(t *testing.T) {
 	}
 }
 
+func TestDeterministicEncryptedUint(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedUint{
+		Raw: 42,
+	}
+	var actual cryptypes.DeterministicEncryptedUint
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestDeterministicEncryptedUintUnset(t *testing.T) {
+	expected := cryptypes.DeterministicEncryptedUint{}
+	var actual cryptypes.DeterministicEncryptedUint
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+}
+
+func TestNullDeterministicEncryptedUint(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedUint{
+		Raw: 42,
+	}
+	var actual cryptypes.NullDeterministicEncryptedUint
+
+	crypted, err := expected.Value()
+	if err != nil {
+		t.Error(err)
+	}
+	err = actual.Scan(crypted)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
+func TestNullDeterministicEncryptedUintUnset(t *testing.T) {
+	expected := cryptypes.NullDeterministicEncryptedUint{}
+	var actual cryptypes.NullDeterministicEncryptedUint
+
+	err := actual.Scan([]byte(""))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if actual.Raw != expected.Raw {
+		t.Errorf("Expected raw = %v; got %v", expected.Raw, actual.Raw)
+	}
+	if actual.Empty != expected.Empty {
+		t.Errorf("Expected empty = %v; got %v", expected.Empty, actual.Empty)
+	}
+}
+
 func TestNullEncryptedUintEmpty(t *testing.T) {
 	expected := cryptypes.NullEncryptedUint{
 		Raw:   42,
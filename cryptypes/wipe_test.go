@@ -0,0 +1,114 @@
+package cryptypes_test
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+	"time"
+
+	gc "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+	"github.com/danhunsaker/gorm-crypto/encoding"
+	"github.com/danhunsaker/gorm-crypto/encryption"
+	"github.com/danhunsaker/gorm-crypto/serializing"
+	"github.com/danhunsaker/gorm-crypto/signing"
+)
+
+// recordingJSON is a JSON serializer which remembers the plaintext buffers passing through it
+type recordingJSON struct {
+	serializing.JSON
+	buffers *[][]byte
+}
+
+func (r recordingJSON) Serialize(value interface{}) ([]byte, error) {
+	serial, err := r.JSON.Serialize(value)
+	*r.buffers = append(*r.buffers, serial)
+
+	return serial, err
+}
+
+func (r recordingJSON) Unserialize(source []byte, dest interface{}) error {
+	*r.buffers = append(*r.buffers, source)
+
+	return r.JSON.Unserialize(source, dest)
+}
+
+func TestPlaintextWiped(t *testing.T) {
+	var buffers [][]byte
+	enc, _ := encryption.NewAES256GCM("EncryptionKeyThatShouldBe32Bytes")
+	siv, _ := encryption.NewAESSIV("DeterministicKeyThatShouldBe64BytesLongSoThatBothHalvesAre32Byte")
+	config := gc.Config{Setups: map[time.Time]gc.Setup{time.Now(): {
+		Encoder:                encoding.Base64{},
+		Serializer:             recordingJSON{buffers: &buffers},
+		Encrypter:              enc,
+		Signer:                 signing.NewED25519FromSeed("SigningKeyThatShouldBe32BytesToo"),
+		DeterministicEncrypter: siv,
+	}}}
+
+	for name, roundTrip := range map[string]func() error{
+		"encrypted": func() error {
+			expected, actual := cryptypes.EncryptedString{Raw: "Secret"}, cryptypes.EncryptedString{}
+			if err := scanBound(&config, &expected, &actual, &actual.Field); err != nil {
+				return err
+			}
+			return restored(expected.Raw, actual.Raw)
+		},
+		"deterministic": func() error {
+			expected, actual := cryptypes.DeterministicEncryptedString{Raw: "Secret"}, cryptypes.DeterministicEncryptedString{}
+			if err := scanBound(&config, &expected, &actual, &actual.Field); err != nil {
+				return err
+			}
+			return restored(expected.Raw, actual.Raw)
+		},
+		"signed+encrypted": func() error {
+			expected, actual := cryptypes.SignedEncryptedString{Raw: "Secret"}, cryptypes.SignedEncryptedString{}
+			if err := scanBound(&config, &expected, &actual, &actual.Field); err != nil {
+				return err
+			}
+			return restored(expected.Raw, actual.Raw)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			buffers = nil
+			if err := roundTrip(); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(buffers) != 2 {
+				t.Fatalf("Expected %v buffers; got %v instead", 2, len(buffers))
+			}
+			for _, buffer := range buffers {
+				if !bytes.Equal(buffer, make([]byte, len(buffer))) {
+					t.Errorf("Expected plaintext to be wiped; got %q instead", buffer)
+				}
+			}
+		})
+	}
+}
+
+// restored checks that wiping the plaintext didn't also wipe the value it was read into
+func restored(expected, actual string) error {
+	if actual != expected {
+		return fmt.Errorf("Expected %v; got %v instead", expected, actual)
+	}
+
+	return nil
+}
+
+// scanBound stores a value, and scans it back into another, with both bound to the same Config
+func scanBound(config *gc.Config, value interface {
+	driver.Valuer
+	BindConfig(*gc.Config)
+}, dest sql.Scanner, field *cryptypes.Field) error {
+	value.BindConfig(config)
+	field.BindConfig(config)
+
+	sealed, err := value.Value()
+	if err != nil {
+		return err
+	}
+
+	return dest.Scan(sealed)
+}
@@ -1,6 +1,11 @@
 // Package encoding defines the various encoding Algorithms supported by the gormcrypto package
 package encoding
 
+import (
+	"errors"
+	"fmt"
+)
+
 // Algorithm is a bad name for the core interface all gormcrypto encodings implement. The name was chosen for consistency more than anything.
 // A type implementing encoding.Algorithm will convert a value between its raw binary and encoded text forms, in a manner consistent with its type.
 // The types implemented here wrap the Go standard library's various encoding packages.
@@ -15,8 +20,18 @@ type Algorithm interface {
 	Decode([]byte) ([]byte, error)
 }
 
+// Creator configures an Algorithm based on a configuration map, reporting any problems with that configuration
+type Creator func(map[string]interface{}) (Algorithm, error)
+
 // RegisterAlgo adds an Algorithm to the internal algos map so it can be used in YAML configs
 func RegisterAlgo(name string, creator func(map[string]interface{}) Algorithm) {
+	Register(name, func(m map[string]interface{}) (Algorithm, error) {
+		return creator(m), nil
+	})
+}
+
+// Register adds an Algorithm to the internal algos map so it can be used in YAML configs, using a Creator which can report errors
+func Register(name string, creator Creator) {
 	algos[name] = creator
 }
 
@@ -29,13 +44,29 @@ func SupportedAlgos() []string {
 	return keys
 }
 
-// FromYaml configures an Algorithm automatically based on a name and a configuration map
+// FromYaml configures an Algorithm automatically based on a name and a configuration map.
+// It returns nil if the Algorithm can't be configured; use New to find out why.
 func FromYaml(name string, config map[string]interface{}) Algorithm {
-	return algos[name](config)
+	algo, _ := New(name, config)
+
+	return algo
 }
 
-var algos map[string]func(map[string]interface{}) Algorithm
+// New configures an Algorithm automatically based on a name and a configuration map, reporting any problems it finds
+func New(name string, config map[string]interface{}) (Algorithm, error) {
+	creator, ok := algos[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownAlgorithm, name)
+	}
+
+	return creator(config)
+}
+
+// ErrUnknownAlgorithm is returned by New when no Algorithm has been registered under the requested name
+var ErrUnknownAlgorithm = errors.New("unknown encoding algorithm")
+
+var algos map[string]Creator
 
 func init() {
-	algos = make(map[string]func(map[string]interface{}) Algorithm, 0)
+	algos = make(map[string]Creator, 0)
 }
@@ -1,6 +1,11 @@
 // Package encryption defines the various encryption Algorithms supported by the gormcrypto package
 package encryption
 
+import (
+	"errors"
+	"fmt"
+)
+
 // Algorithm defines an interface that encryption types must implement to be usable with gormcrypto.
 // A type implementing encryption.Algorithm will convert a value to and from its serialized and encrypted representations.
 // The types implemented here wrap the Go standard (and extended) library's various (non-deprecated) crypto packages.
@@ -15,8 +20,29 @@ type Algorithm interface {
 	Decrypt([]byte) ([]byte, error)
 }
 
+// AEADAlgorithm is implemented by Algorithms which can authenticate associated data along with the plaintext.
+// The associated data isn't stored in the ciphertext; the same data has to be given again to decrypt it,
+// which binds the ciphertext to a context - such as the row and column it's stored in - without encrypting that context.
+type AEADAlgorithm interface {
+	Algorithm
+	// EncryptWithAD transforms plaintext values into a securely encrypted binary representation, authenticating the associated data along with them.
+	EncryptWithAD(plain, associated []byte) ([]byte, error)
+	// DecryptWithAD transforms a securely encrypted binary value into its plaintext version, failing unless the associated data matches.
+	DecryptWithAD(crypted, associated []byte) ([]byte, error)
+}
+
+// Creator configures an Algorithm based on a configuration map, reporting any problems with that configuration
+type Creator func(map[string]interface{}) (Algorithm, error)
+
 // RegisterAlgo adds an Algorithm to the internal algos map so it can be used in YAML configs
 func RegisterAlgo(name string, creator func(map[string]interface{}) Algorithm) {
+	Register(name, func(m map[string]interface{}) (Algorithm, error) {
+		return creator(m), nil
+	})
+}
+
+// Register adds an Algorithm to the internal algos map so it can be used in YAML configs, using a Creator which can report errors
+func Register(name string, creator Creator) {
 	algos[name] = creator
 }
 
@@ -29,13 +55,29 @@ func SupportedAlgos() []string {
 	return keys
 }
 
-// FromYaml configures an Algorithm automatically based on a name and a configuration map
+// FromYaml configures an Algorithm automatically based on a name and a configuration map.
+// It returns nil if the Algorithm can't be configured; use New to find out why.
 func FromYaml(name string, config map[string]interface{}) Algorithm {
-	return algos[name](config)
+	algo, _ := New(name, config)
+
+	return algo
+}
+
+// New configures an Algorithm automatically based on a name and a configuration map, reporting any problems it finds
+func New(name string, config map[string]interface{}) (Algorithm, error) {
+	creator, ok := algos[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownAlgorithm, name)
+	}
+
+	return creator(config)
 }
 
-var algos map[string]func(map[string]interface{}) Algorithm
+// ErrUnknownAlgorithm is returned by New when no Algorithm has been registered under the requested name
+var ErrUnknownAlgorithm = errors.New("unknown encryption algorithm")
+
+var algos map[string]Creator
 
 func init() {
-	algos = make(map[string]func(map[string]interface{}) Algorithm, 0)
+	algos = make(map[string]Creator, 0)
 }
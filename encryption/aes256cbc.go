@@ -8,21 +8,27 @@ import (
 	"encoding/hex"
 	"errors"
 	"io"
+
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
 )
 
 func init() {
-	RegisterAlgo("aes256cbc", func(m map[string]interface{}) Algorithm {
-		key, _ := hex.DecodeString(m["key"].(string))
-		algo, _ := NewAES256CBC(string(key))
-		return algo
+	Register("aes256cbc", func(m map[string]interface{}) (Algorithm, error) {
+		key, err := keyconfig.HexSized(m, "key", 32)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewAES256CBCFromBytes(key)
 	})
 }
 
 // AES256CBC supports AES256CBC encryption of arbitrary data
 type AES256CBC struct {
 	Algorithm
-	key   string
-	block cipher.Block
+	key         []byte
+	block       cipher.Block
+	fingerprint []byte
 }
 
 // Name identifies the Algorithm as a string for exporting configurations
@@ -32,36 +38,58 @@ func (AES256CBC) Name() string {
 
 // Config converts an Algorthim's internal configuration into a map for export
 func (e AES256CBC) Config() map[string]interface{} {
+	if e.block == nil {
+		return nil
+	}
+
 	return map[string]interface{}{
-		"key": hex.EncodeToString([]byte(e.key)),
+		"key": hex.EncodeToString(e.key),
 	}
 }
 
+// KeyFingerprint returns a one-way hash of the key, computed as the Algorithm was created
+func (e AES256CBC) KeyFingerprint() []byte {
+	return e.fingerprint
+}
+
 // NewAES256CBC creates a new AES256CBC value
 func NewAES256CBC(key string) (*AES256CBC, error) {
+	return NewAES256CBCFromBytes([]byte(key))
+}
+
+// NewAES256CBCFromBytes creates a new AES256CBC value.
+// The key is kept rather than copied, so that Destroy can wipe it.
+func NewAES256CBCFromBytes(key []byte) (*AES256CBC, error) {
 	if len(key) != 32 {
 		return nil, errors.New("key length MUST be 32 bytes for AES256")
 	}
 
-	block, err := aes.NewCipher([]byte(key))
+	block, err := aes.NewCipher(key)
 
 	if err != nil {
 		return nil, err
 	}
 
 	return &AES256CBC{
-		key:   key,
-		block: block,
+		key:         key,
+		block:       block,
+		fingerprint: keyconfig.Fingerprint(key),
 	}, nil
 }
 
 // Encrypt encrypts data with key
 func (e *AES256CBC) Encrypt(plain []byte) ([]byte, error) {
+	if e.block == nil {
+		return nil, keyconfig.ErrDestroyed
+	}
+
 	padded := 0
 
 	if f := len(plain) % aes.BlockSize; f != 0 {
 		padded = aes.BlockSize - f
-		plain = append(plain, bytes.Repeat([]byte{byte(padded)}, padded)...)
+		// Padding is added to a copy, which is wiped afterwards, so the caller's buffer is never written to
+		plain = append(append(make([]byte, 0, len(plain)+padded), plain...), bytes.Repeat([]byte{byte(padded)}, padded)...)
+		defer keyconfig.Wipe(plain)
 	}
 
 	crypted := make([]byte, aes.BlockSize+len(plain)+1)
@@ -79,6 +107,9 @@ func (e *AES256CBC) Encrypt(plain []byte) ([]byte, error) {
 
 // Decrypt decrypts data with key
 func (e *AES256CBC) Decrypt(crypted []byte) ([]byte, error) {
+	if e.block == nil {
+		return nil, keyconfig.ErrDestroyed
+	}
 	if len(crypted) < aes.BlockSize {
 		return nil, errors.New("encrypted data too short")
 	}
@@ -102,3 +133,9 @@ func (e *AES256CBC) Decrypt(crypted []byte) ([]byte, error) {
 
 	return decrypted, nil
 }
+
+// Destroy wipes the key, after which the AES256CBC value refuses to encrypt or decrypt anything
+func (e *AES256CBC) Destroy() {
+	keyconfig.Wipe(e.key)
+	e.key, e.block = nil, nil
+}
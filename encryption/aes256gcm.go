@@ -7,21 +7,27 @@ import (
 	"encoding/hex"
 	"errors"
 	"io"
+
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
 )
 
 func init() {
-	RegisterAlgo("aes256gcm", func(m map[string]interface{}) Algorithm {
-		key, _ := hex.DecodeString(m["key"].(string))
-		algo, _ := NewAES256GCM(string(key))
-		return algo
+	Register("aes256gcm", func(m map[string]interface{}) (Algorithm, error) {
+		key, err := keyconfig.HexSized(m, "key", 32)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewAES256GCMFromBytes(key)
 	})
 }
 
 // AES256GCM supports AES256GCM encryption of arbitrary data
 type AES256GCM struct {
 	Algorithm
-	key  string
-	aead cipher.AEAD
+	key         []byte
+	aead        cipher.AEAD
+	fingerprint []byte
 }
 
 // Name identifies the Algorithm as a string for exporting configurations
@@ -31,11 +37,20 @@ func (AES256GCM) Name() string {
 
 // Config converts an Algorthim's internal configuration into a map for export
 func (e AES256GCM) Config() map[string]interface{} {
+	if e.aead == nil {
+		return nil
+	}
+
 	return map[string]interface{}{
-		"key": hex.EncodeToString([]byte(e.key)),
+		"key": hex.EncodeToString(e.key),
 	}
 }
 
+// KeyFingerprint returns a one-way hash of the key, computed as the Algorithm was created
+func (e AES256GCM) KeyFingerprint() []byte {
+	return e.fingerprint
+}
+
 // NewAES creates a new AES value
 func NewAES(key string) (*AES256GCM, error) {
 	return NewAES256(key)
@@ -48,43 +63,98 @@ func NewAES256(key string) (*AES256GCM, error) {
 
 // NewAES256GCM creates instance of AES256GCM with passed key
 func NewAES256GCM(key string) (*AES256GCM, error) {
+	return NewAES256GCMFromBytes([]byte(key))
+}
+
+// NewAES256GCMFromBytes creates instance of AES256GCM with passed key.
+// The key is kept rather than copied, so that Destroy can wipe it.
+func NewAES256GCMFromBytes(key []byte) (*AES256GCM, error) {
 	if len(key) != 32 {
 		return nil, errors.New("key length MUST be 32 bytes for AES256")
 	}
 
-	aesCipher, err := aes.NewCipher([]byte(key))
-	if err != nil {
-		return nil, err
-	}
-
-	aesGCM, err := cipher.NewGCM(aesCipher)
+	aesGCM, err := newGCM(key)
 	if err != nil {
 		return nil, err
 	}
 
 	return &AES256GCM{
-		key:  key,
-		aead: aesGCM,
+		key:         key,
+		aead:        aesGCM,
+		fingerprint: keyconfig.Fingerprint(key),
 	}, nil
 }
 
 // Encrypt encrypts data with key
 func (e *AES256GCM) Encrypt(plain []byte) ([]byte, error) {
+	return e.EncryptWithAD(plain, nil)
+}
+
+// Decrypt decrypts data with key
+func (e *AES256GCM) Decrypt(crypted []byte) ([]byte, error) {
+	return e.DecryptWithAD(crypted, nil)
+}
+
+// EncryptWithAD encrypts data with key, authenticating the associated data along with it
+func (e *AES256GCM) EncryptWithAD(plain, associated []byte) ([]byte, error) {
+	if e.aead == nil {
+		return nil, keyconfig.ErrDestroyed
+	}
+
 	nonce := make([]byte, e.aead.NonceSize())
 	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
 		return nil, err
 	}
 
-	return e.aead.Seal(nonce, nonce, plain, nil), nil
+	return e.aead.Seal(nonce, nonce, plain, associated), nil
 }
 
-// Decrypt decrypts data with key
-func (e *AES256GCM) Decrypt(crypted []byte) ([]byte, error) {
+// DecryptWithAD decrypts data with key, failing unless the associated data matches that given to EncryptWithAD
+func (e *AES256GCM) DecryptWithAD(crypted, associated []byte) ([]byte, error) {
+	if e.aead == nil {
+		return nil, keyconfig.ErrDestroyed
+	}
+
 	nonceSize := e.aead.NonceSize()
 	if len(crypted) < nonceSize {
 		return nil, errors.New("encrypted data is not valid")
 	}
 
 	nonce, crypted := crypted[:nonceSize], crypted[nonceSize:]
-	return e.aead.Open(nil, nonce, crypted, nil)
+	return e.aead.Open(nil, nonce, crypted, associated)
+}
+
+// EncryptStream encrypts everything written to the returned WriteCloser onto w, a chunk at a time
+func (e *AES256GCM) EncryptStream(w io.Writer, associated []byte) (io.WriteCloser, error) {
+	if e.aead == nil {
+		return nil, keyconfig.ErrDestroyed
+	}
+
+	return newStreamWriter(w, e.key, newGCM, associated)
+}
+
+// DecryptStream decrypts a stream produced by EncryptStream, a chunk at a time
+func (e *AES256GCM) DecryptStream(r io.Reader, associated []byte) (io.Reader, error) {
+	if e.aead == nil {
+		return nil, keyconfig.ErrDestroyed
+	}
+
+	return newStreamReader(r, e.key, newGCM, associated)
+}
+
+// Destroy wipes the key, after which the AES256GCM value refuses to encrypt or decrypt anything
+func (e *AES256GCM) Destroy() {
+	keyconfig.Wipe(e.key)
+	e.key, e.aead = nil, nil
+}
+
+// PRIVATE
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	aesCipher, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(aesCipher)
 }
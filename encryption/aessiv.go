@@ -0,0 +1,252 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
+)
+
+func init() {
+	Register("aessiv", func(m map[string]interface{}) (Algorithm, error) {
+		key, err := keyconfig.Hex(m, "key")
+		if err != nil {
+			return nil, err
+		}
+		if len(key) != 32 && len(key) != 48 && len(key) != 64 {
+			return nil, keyconfig.Errorf("key", "%w: expected 32, 48, or 64 bytes; got %d instead", keyconfig.ErrKeyLength, len(key))
+		}
+
+		return NewAESSIVFromBytes(key)
+	})
+}
+
+// DeterministicAlgorithm is implemented by Algorithms which always encrypt the same plaintext to the same ciphertext.
+// That allows encrypted values to be compared for equality - in WHERE clauses and unique indexes, for example - without decrypting them.
+// The price is that anyone who can see the ciphertexts can also tell which values are equal, and how often each one occurs.
+type DeterministicAlgorithm interface {
+	Algorithm
+	// Deterministic marks the Algorithm as deterministic; it does nothing
+	Deterministic()
+}
+
+// AESSIV supports deterministic AES-SIV (RFC 5297) encryption of arbitrary data.
+// The key is split in half; the first half authenticates, and the second half encrypts.
+// A 64-byte key gives AES-256 for both halves, while 32- and 48-byte keys give AES-128 and AES-192.
+type AESSIV struct {
+	DeterministicAlgorithm
+	key         []byte
+	mac         cipher.Block
+	ctr         cipher.Block
+	fingerprint []byte
+}
+
+// Name identifies the Algorithm as a string for exporting configurations
+func (AESSIV) Name() string {
+	return "aessiv"
+}
+
+// Config converts an Algorthim's internal configuration into a map for export
+func (e AESSIV) Config() map[string]interface{} {
+	if e.mac == nil {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"key": hex.EncodeToString(e.key),
+	}
+}
+
+// KeyFingerprint returns a one-way hash of the key, computed as the Algorithm was created
+func (e AESSIV) KeyFingerprint() []byte {
+	return e.fingerprint
+}
+
+// NewAESSIV creates a new AESSIV value
+func NewAESSIV(key string) (*AESSIV, error) {
+	return NewAESSIVFromBytes([]byte(key))
+}
+
+// NewAESSIVFromBytes creates a new AESSIV value.
+// The key is kept rather than copied, so that Destroy can wipe it.
+func NewAESSIVFromBytes(key []byte) (*AESSIV, error) {
+	if len(key) != 32 && len(key) != 48 && len(key) != 64 {
+		return nil, errors.New("key length MUST be 32, 48, or 64 bytes for AES-SIV")
+	}
+
+	mac, err := aes.NewCipher(key[:len(key)/2])
+	if err != nil {
+		return nil, err
+	}
+
+	ctr, err := aes.NewCipher(key[len(key)/2:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &AESSIV{
+		key:         key,
+		mac:         mac,
+		ctr:         ctr,
+		fingerprint: keyconfig.Fingerprint(key),
+	}, nil
+}
+
+// Deterministic marks the Algorithm as deterministic; it does nothing
+func (*AESSIV) Deterministic() {}
+
+// Encrypt encrypts data with key; the same data always produces the same result
+func (e *AESSIV) Encrypt(plain []byte) ([]byte, error) {
+	if e.mac == nil {
+		return nil, keyconfig.ErrDestroyed
+	}
+
+	return e.Seal(plain), nil
+}
+
+// Decrypt decrypts data with key
+func (e *AESSIV) Decrypt(crypted []byte) ([]byte, error) {
+	return e.Open(crypted)
+}
+
+// EncryptWithAD encrypts data with key, authenticating the associated data along with it
+func (e *AESSIV) EncryptWithAD(plain, associated []byte) ([]byte, error) {
+	if e.mac == nil {
+		return nil, keyconfig.ErrDestroyed
+	}
+
+	return e.Seal(plain, associated), nil
+}
+
+// DecryptWithAD decrypts data with key, failing unless the associated data matches that given to EncryptWithAD
+func (e *AESSIV) DecryptWithAD(crypted, associated []byte) ([]byte, error) {
+	return e.Open(crypted, associated)
+}
+
+// Seal encrypts plaintext, authenticating it along with any associated data given.
+// Unlike Encrypt, it can't report errors, so it panics once the AESSIV value has been destroyed.
+func (e *AESSIV) Seal(plaintext []byte, associated ...[]byte) []byte {
+	iv := e.s2v(associated, plaintext)
+
+	out := make([]byte, aes.BlockSize+len(plaintext))
+	copy(out, iv)
+	e.xorKeyStream(out[aes.BlockSize:], plaintext, iv)
+
+	return out
+}
+
+// Open decrypts and authenticates ciphertext produced by Seal with the same associated data
+func (e *AESSIV) Open(ciphertext []byte, associated ...[]byte) ([]byte, error) {
+	if e.mac == nil {
+		return nil, keyconfig.ErrDestroyed
+	}
+	if len(ciphertext) < aes.BlockSize {
+		return nil, errors.New("encrypted data is not valid")
+	}
+
+	iv := ciphertext[:aes.BlockSize]
+	plaintext := make([]byte, len(ciphertext)-aes.BlockSize)
+	e.xorKeyStream(plaintext, ciphertext[aes.BlockSize:], iv)
+
+	if subtle.ConstantTimeCompare(e.s2v(associated, plaintext), iv) != 1 {
+		keyconfig.Wipe(plaintext)
+		return nil, errors.New("message authentication failed")
+	}
+
+	return plaintext, nil
+}
+
+// Destroy wipes the key, after which the AESSIV value refuses to encrypt or decrypt anything
+func (e *AESSIV) Destroy() {
+	keyconfig.Wipe(e.key)
+	e.key, e.mac, e.ctr = nil, nil, nil
+}
+
+// PRIVATE
+
+// xorKeyStream applies AES-CTR, using the synthetic IV with the 31st and 63rd bits of its last 64 bits cleared as the counter
+func (e *AESSIV) xorKeyStream(dst, src, iv []byte) {
+	counter := make([]byte, aes.BlockSize)
+	copy(counter, iv)
+	counter[8] &= 0x7f
+	counter[12] &= 0x7f
+
+	cipher.NewCTR(e.ctr, counter).XORKeyStream(dst, src)
+}
+
+// s2v implements the S2V construction from RFC 5297, which turns the associated data and plaintext into a single synthetic IV
+func (e *AESSIV) s2v(associated [][]byte, last []byte) []byte {
+	d := e.cmac(make([]byte, aes.BlockSize))
+
+	for _, s := range associated {
+		dbl(d)
+		xorInto(d, e.cmac(s))
+	}
+
+	var t []byte
+	if len(last) >= aes.BlockSize {
+		t = make([]byte, len(last))
+		copy(t, last)
+		xorInto(t[len(t)-aes.BlockSize:], d)
+	} else {
+		dbl(d)
+		t = make([]byte, aes.BlockSize)
+		copy(t, last)
+		t[len(last)] = 0x80
+		xorInto(t, d)
+	}
+	defer keyconfig.Wipe(t)
+
+	return e.cmac(t)
+}
+
+// cmac implements AES-CMAC (RFC 4493) using the authentication half of the key
+func (e *AESSIV) cmac(message []byte) []byte {
+	subkey := make([]byte, aes.BlockSize)
+	e.mac.Encrypt(subkey, subkey)
+	dbl(subkey)
+
+	blocks := (len(message) + aes.BlockSize - 1) / aes.BlockSize
+	if blocks == 0 {
+		blocks = 1
+	}
+	last := make([]byte, aes.BlockSize)
+	defer keyconfig.Wipe(last)
+	tail := message[(blocks-1)*aes.BlockSize:]
+	copy(last, tail)
+	if len(tail) == aes.BlockSize {
+		xorInto(last, subkey)
+	} else {
+		last[len(tail)] = 0x80
+		dbl(subkey)
+		xorInto(last, subkey)
+	}
+
+	mac := make([]byte, aes.BlockSize)
+	for i := 0; i < blocks-1; i++ {
+		xorInto(mac, message[i*aes.BlockSize:(i+1)*aes.BlockSize])
+		e.mac.Encrypt(mac, mac)
+	}
+	xorInto(mac, last)
+	e.mac.Encrypt(mac, mac)
+
+	return mac
+}
+
+// dbl multiplies a block by x in GF(2^128), in place
+func dbl(block []byte) {
+	carry := block[0] >> 7
+	for i := 0; i < len(block)-1; i++ {
+		block[i] = block[i]<<1 | block[i+1]>>7
+	}
+	block[len(block)-1] = block[len(block)-1]<<1 ^ 0x87*carry
+}
+
+func xorInto(dst, src []byte) {
+	for i := range src {
+		dst[i] ^= src[i]
+	}
+}
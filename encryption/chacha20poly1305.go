@@ -7,22 +7,27 @@ import (
 	"errors"
 	"io"
 
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
 	"golang.org/x/crypto/chacha20poly1305"
 )
 
 func init() {
-	RegisterAlgo("chacha20", func(m map[string]interface{}) Algorithm {
-		key, _ := hex.DecodeString(m["key"].(string))
-		algo, _ := NewChaCha20Poly1305(string(key))
-		return algo
+	Register("chacha20", func(m map[string]interface{}) (Algorithm, error) {
+		key, err := keyconfig.HexSized(m, "key", 32)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewChaCha20Poly1305FromBytes(key)
 	})
 }
 
 // ChaCha20Poly1305 supports ChaCha20Poly1305 encryption of arbitrary data
 type ChaCha20Poly1305 struct {
 	Algorithm
-	key  string
-	aead cipher.AEAD
+	key         []byte
+	aead        cipher.AEAD
+	fingerprint []byte
 }
 
 // Name identifies the Algorithm as a string for exporting configurations
@@ -32,45 +37,103 @@ func (ChaCha20Poly1305) Name() string {
 
 // Config converts an Algorthim's internal configuration into a map for export
 func (e ChaCha20Poly1305) Config() map[string]interface{} {
+	if e.aead == nil {
+		return nil
+	}
+
 	return map[string]interface{}{
-		"key": hex.EncodeToString([]byte(e.key)),
+		"key": hex.EncodeToString(e.key),
 	}
 }
 
+// KeyFingerprint returns a one-way hash of the key, computed as the Algorithm was created
+func (e ChaCha20Poly1305) KeyFingerprint() []byte {
+	return e.fingerprint
+}
+
 // NewChaCha20Poly1305 creates instance of ChaCha20Poly1305 with passed key
 func NewChaCha20Poly1305(key string) (*ChaCha20Poly1305, error) {
+	return NewChaCha20Poly1305FromBytes([]byte(key))
+}
+
+// NewChaCha20Poly1305FromBytes creates instance of ChaCha20Poly1305 with passed key.
+// The key is kept rather than copied, so that Destroy can wipe it.
+func NewChaCha20Poly1305FromBytes(key []byte) (*ChaCha20Poly1305, error) {
 	if len(key) != chacha20poly1305.KeySize {
 		return nil, errors.New("key length MUST be 32 bytes for ChaCha20Poly1305")
 	}
 
-	ccpGCM, err := chacha20poly1305.New([]byte(key))
+	ccpGCM, err := chacha20poly1305.New(key)
 	if err != nil {
 		return nil, err
 	}
 
 	return &ChaCha20Poly1305{
-		key:  key,
-		aead: ccpGCM,
+		key:         key,
+		aead:        ccpGCM,
+		fingerprint: keyconfig.Fingerprint(key),
 	}, nil
 }
 
 // Encrypt encrypts data with key
 func (e *ChaCha20Poly1305) Encrypt(plain []byte) ([]byte, error) {
+	return e.EncryptWithAD(plain, nil)
+}
+
+// Decrypt decrypts data with key
+func (e *ChaCha20Poly1305) Decrypt(crypted []byte) ([]byte, error) {
+	return e.DecryptWithAD(crypted, nil)
+}
+
+// EncryptWithAD encrypts data with key, authenticating the associated data along with it
+func (e *ChaCha20Poly1305) EncryptWithAD(plain, associated []byte) ([]byte, error) {
+	if e.aead == nil {
+		return nil, keyconfig.ErrDestroyed
+	}
+
 	nonce := make([]byte, e.aead.NonceSize())
 	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
 		return nil, err
 	}
 
-	return e.aead.Seal(nonce, nonce, plain, nil), nil
+	return e.aead.Seal(nonce, nonce, plain, associated), nil
 }
 
-// Decrypt decrypts data with key
-func (e *ChaCha20Poly1305) Decrypt(crypted []byte) ([]byte, error) {
+// DecryptWithAD decrypts data with key, failing unless the associated data matches that given to EncryptWithAD
+func (e *ChaCha20Poly1305) DecryptWithAD(crypted, associated []byte) ([]byte, error) {
+	if e.aead == nil {
+		return nil, keyconfig.ErrDestroyed
+	}
+
 	nonceSize := e.aead.NonceSize()
 	if len(crypted) < nonceSize {
 		return nil, errors.New("encrypted data is not valid")
 	}
 
 	nonce, crypted := crypted[:nonceSize], crypted[nonceSize:]
-	return e.aead.Open(nil, nonce, crypted, nil)
+	return e.aead.Open(nil, nonce, crypted, associated)
+}
+
+// EncryptStream encrypts everything written to the returned WriteCloser onto w, a chunk at a time
+func (e *ChaCha20Poly1305) EncryptStream(w io.Writer, associated []byte) (io.WriteCloser, error) {
+	if e.aead == nil {
+		return nil, keyconfig.ErrDestroyed
+	}
+
+	return newStreamWriter(w, e.key, chacha20poly1305.New, associated)
+}
+
+// DecryptStream decrypts a stream produced by EncryptStream, a chunk at a time
+func (e *ChaCha20Poly1305) DecryptStream(r io.Reader, associated []byte) (io.Reader, error) {
+	if e.aead == nil {
+		return nil, keyconfig.ErrDestroyed
+	}
+
+	return newStreamReader(r, e.key, chacha20poly1305.New, associated)
+}
+
+// Destroy wipes the key, after which the ChaCha20Poly1305 value refuses to encrypt or decrypt anything
+func (e *ChaCha20Poly1305) Destroy() {
+	keyconfig.Wipe(e.key)
+	e.key, e.aead = nil, nil
 }
@@ -0,0 +1,343 @@
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
+	"github.com/danhunsaker/gorm-crypto/kms"
+)
+
+func init() {
+	Register("dek", func(m map[string]interface{}) (Algorithm, error) {
+		name, ok := m["provider"].(string)
+		if !ok || name == "" {
+			return nil, &keyconfig.FieldError{Field: "provider", Err: keyconfig.ErrMissingKey}
+		}
+
+		ttl := DefaultDataKeyCacheTTL
+		if given, ok := m["cache_ttl"]; ok {
+			text, ok := given.(string)
+			if !ok {
+				return nil, keyconfig.Errorf("cache_ttl", "expected a duration string; got %T instead", given)
+			}
+			parsed, err := time.ParseDuration(text)
+			if err != nil || parsed < 0 {
+				return nil, keyconfig.Errorf("cache_ttl", "expected a duration of 0 or more; got %q instead", text)
+			}
+			ttl = parsed
+		}
+
+		config := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			if k != "provider" && k != "cache_ttl" {
+				config[k] = v
+			}
+		}
+		provider, err := kms.New(name, config)
+		if err != nil {
+			return nil, &keyconfig.FieldError{Field: "provider", Err: err}
+		}
+
+		dek := NewDEK(provider)
+		dek.SetCacheTTL(ttl)
+
+		return dek, nil
+	})
+}
+
+// DataKeyAlgorithm is implemented by Algorithms which encrypt every value under its own data key.
+// The wrapped data key is kept apart from the ciphertext, so it can be stored - and later re-wrapped - on its own.
+type DataKeyAlgorithm interface {
+	Algorithm
+	// EncryptWithDataKey encrypts a value under a fresh data key, returning the ciphertext and the wrapped data key.
+	// Any associated data is authenticated along with the value, as AEADAlgorithm does.
+	// The context is passed on to the KMS wrapping the data key, so the call can be cancelled, or given a deadline.
+	EncryptWithDataKey(ctx context.Context, plain, associated []byte) (crypted []byte, wrappedKey []byte, err error)
+	// DecryptWithDataKey decrypts a value using the wrapped data key it was encrypted under, and the associated data it was encrypted with.
+	// The context is passed on to the KMS unwrapping the data key.
+	DecryptWithDataKey(ctx context.Context, crypted, wrappedKey, associated []byte) ([]byte, error)
+}
+
+// DefaultDataKeyCacheTTL is how long a DEK keeps the data keys it unwraps, unless its cache_ttl config says otherwise
+const DefaultDataKeyCacheTTL = time.Minute
+
+// DEK supports envelope encryption: every value is encrypted by AES256GCM under its own random data encryption key,
+// which is then wrapped by a kms.KeyProvider.
+// Unwrapped data keys are cached for a short time, so values read again soon after don't need another KMS round-trip each.
+type DEK struct {
+	DataKeyAlgorithm
+	provider kms.KeyProvider
+	cache    *dataKeyCache
+}
+
+// Name identifies the Algorithm as a string for exporting configurations
+func (DEK) Name() string {
+	return "dek"
+}
+
+// Config converts an Algorthim's internal configuration into a map for export
+func (e DEK) Config() map[string]interface{} {
+	config := map[string]interface{}{
+		"provider": e.provider.Name(),
+	}
+	for k, v := range e.provider.Config() {
+		config[k] = v
+	}
+	if e.cache != nil && e.cache.ttl != DefaultDataKeyCacheTTL {
+		config["cache_ttl"] = e.cache.ttl.String()
+	}
+
+	return config
+}
+
+// NewDEK creates a new DEK value, wrapping its data keys with the given KeyProvider, and caching them for DefaultDataKeyCacheTTL once unwrapped
+func NewDEK(provider kms.KeyProvider) *DEK {
+	return &DEK{provider: provider, cache: &dataKeyCache{ttl: DefaultDataKeyCacheTTL}}
+}
+
+// SetCacheTTL sets how long unwrapped data keys are cached for; 0 disables the cache, and wipes any keys already in it
+func (e *DEK) SetCacheTTL(ttl time.Duration) {
+	if e.cache == nil {
+		e.cache = &dataKeyCache{}
+	}
+	e.cache.mutex.Lock()
+	defer e.cache.mutex.Unlock()
+
+	e.cache.ttl = ttl
+	if ttl == 0 {
+		e.cache.clear()
+	}
+}
+
+// Provider returns the KeyProvider used to wrap data keys
+func (e *DEK) Provider() kms.KeyProvider {
+	return e.provider
+}
+
+// Encrypt encrypts data under a fresh data key, and prefixes the result with the wrapped data key
+func (e *DEK) Encrypt(plain []byte) ([]byte, error) {
+	crypted, wrappedKey, err := e.EncryptWithDataKey(context.Background(), plain, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, binary.MaxVarintLen64)
+	out := bytes.NewBuffer(buf[:binary.PutUvarint(buf, uint64(len(wrappedKey)))])
+	out.Write(wrappedKey)
+	out.Write(crypted)
+
+	return out.Bytes(), nil
+}
+
+// Decrypt decrypts data produced by Encrypt
+func (e *DEK) Decrypt(crypted []byte) ([]byte, error) {
+	size, n := binary.Uvarint(crypted)
+	if n <= 0 || uint64(len(crypted)-n) < size {
+		return nil, errors.New("encrypted data is not valid")
+	}
+
+	return e.DecryptWithDataKey(context.Background(), crypted[n+int(size):], crypted[n:n+int(size)], nil)
+}
+
+// EncryptWithDataKey encrypts a value under a fresh data key, returning the ciphertext and the wrapped data key
+func (e *DEK) EncryptWithDataKey(ctx context.Context, plain, associated []byte) ([]byte, []byte, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, nil, err
+	}
+
+	aes, err := NewAES256GCMFromBytes(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer aes.Destroy()
+
+	crypted, err := aes.EncryptWithAD(plain, associated)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wrappedKey, err := e.provider.WrapKey(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return crypted, wrappedKey, nil
+}
+
+// DecryptWithDataKey decrypts a value using the wrapped data key it was encrypted under, and the associated data it was encrypted with
+func (e *DEK) DecryptWithDataKey(ctx context.Context, crypted, wrappedKey, associated []byte) ([]byte, error) {
+	key, err := e.unwrap(ctx, wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	aes, err := NewAES256GCMFromBytes(key)
+	if err != nil {
+		keyconfig.Wipe(key)
+		return nil, err
+	}
+	defer aes.Destroy()
+
+	return aes.DecryptWithAD(crypted, associated)
+}
+
+// EncryptStream encrypts everything written to the returned WriteCloser onto w under a fresh data key, a chunk at a time.
+// The stream is prefixed with the wrapped data key, as Encrypt does.
+func (e *DEK) EncryptStream(w io.Writer, associated []byte) (io.WriteCloser, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	defer keyconfig.Wipe(key)
+
+	wrappedKey, err := e.provider.WrapKey(context.Background(), key)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, binary.MaxVarintLen64)
+	header := bytes.NewBuffer(buf[:binary.PutUvarint(buf, uint64(len(wrappedKey)))])
+	header.Write(wrappedKey)
+	if _, err := w.Write(header.Bytes()); err != nil {
+		return nil, err
+	}
+
+	return newStreamWriter(w, key, newGCM, associated)
+}
+
+// DecryptStream decrypts a stream produced by EncryptStream, a chunk at a time
+func (e *DEK) DecryptStream(r io.Reader, associated []byte) (io.Reader, error) {
+	in, ok := r.(io.ByteReader)
+	if !ok {
+		buffered := bufio.NewReader(r)
+		in, r = buffered, buffered
+	}
+
+	size, err := binary.ReadUvarint(in)
+	if err != nil || size > maxWrappedKeySize {
+		return nil, ErrStreamCorrupt
+	}
+	wrappedKey := make([]byte, size)
+	if _, err := io.ReadFull(r, wrappedKey); err != nil {
+		return nil, ErrStreamCorrupt
+	}
+
+	key, err := e.unwrap(context.Background(), wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	defer keyconfig.Wipe(key)
+
+	return newStreamReader(r, key, newGCM, associated)
+}
+
+// Destroy wipes every cached data key, and destroys the KeyProvider too, if it holds key material which can be destroyed.
+// Other data keys are wiped as soon as each value has been encrypted or decrypted, so there are none left to wipe here.
+func (e *DEK) Destroy() {
+	e.SetCacheTTL(0)
+	if destroyer, ok := e.provider.(keyconfig.Destroyer); ok {
+		destroyer.Destroy()
+	}
+}
+
+// PRIVATE
+
+// maxCachedDataKeys limits how many unwrapped data keys a DEK caches at once
+const maxCachedDataKeys = 1024
+
+// dataKeyCache holds unwrapped data keys, keyed by their wrapped form, until they expire
+type dataKeyCache struct {
+	mutex sync.Mutex
+	ttl   time.Duration
+	keys  map[string]cachedDataKey
+}
+
+type cachedDataKey struct {
+	key     []byte
+	expires time.Time
+}
+
+// get returns a copy of a cached data key, if it's cached and hasn't expired
+func (c *dataKeyCache) get(wrapped []byte) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cached, ok := c.keys[string(wrapped)]
+	if !ok || time.Now().After(cached.expires) {
+		return nil, false
+	}
+
+	return append([]byte(nil), cached.key...), true
+}
+
+// put caches a copy of a data key, first wiping any which have expired, or the oldest if the cache is full
+func (c *dataKeyCache) put(wrapped, key []byte) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.ttl <= 0 {
+		return
+	}
+	if c.keys == nil {
+		c.keys = make(map[string]cachedDataKey)
+	}
+
+	now := time.Now()
+	var oldest string
+	for id, cached := range c.keys {
+		if now.After(cached.expires) {
+			keyconfig.Wipe(cached.key)
+			delete(c.keys, id)
+		} else if oldest == "" || cached.expires.Before(c.keys[oldest].expires) {
+			oldest = id
+		}
+	}
+	if len(c.keys) >= maxCachedDataKeys {
+		keyconfig.Wipe(c.keys[oldest].key)
+		delete(c.keys, oldest)
+	}
+
+	c.keys[string(wrapped)] = cachedDataKey{key: append([]byte(nil), key...), expires: now.Add(c.ttl)}
+}
+
+// clear wipes every cached data key; the caller holds the mutex
+func (c *dataKeyCache) clear() {
+	for id, cached := range c.keys {
+		keyconfig.Wipe(cached.key)
+		delete(c.keys, id)
+	}
+}
+
+// unwrap unwraps a data key with the KeyProvider, unless it's already cached.
+// The caller wipes the key returned; the cache keeps its own copy.
+func (e *DEK) unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	if key, ok := e.cache.get(wrapped); ok {
+		return key, nil
+	}
+
+	key, err := e.provider.UnwrapKey(ctx, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	e.cache.put(wrapped, key)
+
+	return key, nil
+}
+
+// maxWrappedKeySize limits how much is read for a stream's wrapped data key, so a corrupt stream can't claim an absurdly large one
+const maxWrappedKeySize = 64 * 1024
@@ -4,12 +4,21 @@ import (
 	"bytes"
 	"crypto/rand"
 	"crypto/rsa"
+	"encoding/hex"
+	"errors"
+	"io"
 	"math/big"
+	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/danhunsaker/gorm-crypto/encryption"
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
+	"github.com/danhunsaker/gorm-crypto/kms"
 	"golang.org/x/crypto/nacl/box"
 )
 
@@ -36,6 +45,38 @@ func TestEncryption(t *testing.T) {
 	}
 }
 
+func TestAssociatedData(t *testing.T) {
+	for _, crypto := range getAlgos() {
+		aead, ok := crypto.(encryption.AEADAlgorithm)
+		if !ok {
+			continue
+		}
+
+		t.Run(reflect.TypeOf(crypto).String(), func(t *testing.T) {
+			expected := []byte("Test")
+			crypted, err := aead.EncryptWithAD(expected, []byte("users\x00ssn\x001"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := aead.DecryptWithAD(crypted, []byte("users\x00ssn\x001"))
+			if err != nil {
+				t.Error(err)
+			}
+			if !bytes.Equal(actual, expected) {
+				t.Errorf("Expected %v; got %v instead", expected, actual)
+			}
+
+			if _, err := aead.DecryptWithAD(crypted, []byte("users\x00ssn\x002")); err == nil {
+				t.Error("Expected an error decrypting with the wrong associated data; got none")
+			}
+			if _, err := aead.Decrypt(crypted); err == nil {
+				t.Error("Expected an error decrypting without the associated data; got none")
+			}
+		})
+	}
+}
+
 func TestExports(t *testing.T) {
 	for _, crypto := range getAlgos() {
 		t.Run(reflect.TypeOf(crypto).String(), func(t *testing.T) {
@@ -75,18 +116,125 @@ func TestAlgoSupportFuncs(t *testing.T) {
 	}
 }
 
+func TestStreams(t *testing.T) {
+	algos := append(getAlgos(), encryption.NewDEK(kms.NewFake()))
+	for _, crypto := range algos {
+		stream, ok := crypto.(encryption.StreamAlgorithm)
+		if !ok {
+			continue
+		}
+
+		t.Run(reflect.TypeOf(crypto).String(), func(t *testing.T) {
+			for _, size := range []int{0, 1, encryption.StreamChunkSize, 2*encryption.StreamChunkSize + 7} {
+				expected := make([]byte, size)
+				rand.Read(expected)
+
+				var crypted bytes.Buffer
+				w, err := stream.EncryptStream(&crypted, []byte("files\x00body\x001"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := io.Copy(w, iotest.HalfReader(bytes.NewReader(expected))); err != nil {
+					t.Fatal(err)
+				}
+				if err := w.Close(); err != nil {
+					t.Fatal(err)
+				}
+
+				r, err := stream.DecryptStream(bytes.NewReader(crypted.Bytes()), []byte("files\x00body\x001"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				actual, err := io.ReadAll(iotest.OneByteReader(r))
+				if err != nil {
+					t.Error(err)
+				}
+				if !bytes.Equal(actual, expected) {
+					t.Errorf("Expected %d bytes; got %d different bytes instead", len(expected), len(actual))
+				}
+
+				if r, err := stream.DecryptStream(bytes.NewReader(crypted.Bytes()), []byte("files\x00body\x002")); err == nil {
+					if _, err := io.ReadAll(r); err == nil {
+						t.Error("Expected an error decrypting with the wrong associated data; got none")
+					}
+				}
+
+				for name, tampered := range map[string][]byte{
+					"truncated": crypted.Bytes()[:crypted.Len()-size%encryption.StreamChunkSize-16],
+					"altered":   append(append([]byte(nil), crypted.Bytes()[:crypted.Len()-1]...), crypted.Bytes()[crypted.Len()-1]^1),
+				} {
+					if r, err := stream.DecryptStream(bytes.NewReader(tampered), []byte("files\x00body\x001")); err == nil {
+						if _, err := io.ReadAll(r); err == nil {
+							t.Errorf("Expected an error decrypting a %s stream of %d bytes; got none", name, size)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDestroy(t *testing.T) {
+	for _, crypto := range append(getAlgos(), encryption.NewDEK(kms.NewFake())) {
+		t.Run(reflect.TypeOf(crypto).String(), func(t *testing.T) {
+			crypted, err := crypto.Encrypt([]byte("Test"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			destroyer, ok := crypto.(keyconfig.Destroyer)
+			if !ok {
+				t.Fatalf("Expected %T to implement keyconfig.Destroyer", crypto)
+			}
+			destroyer.Destroy()
+			destroyer.Destroy()
+
+			if _, ok := crypto.(*encryption.DEK); ok {
+				return
+			}
+			if _, err := crypto.Encrypt([]byte("Test")); !errors.Is(err, keyconfig.ErrDestroyed) {
+				t.Errorf("Expected %v; got %v instead", keyconfig.ErrDestroyed, err)
+			}
+			if _, err := crypto.Decrypt(crypted); !errors.Is(err, keyconfig.ErrDestroyed) {
+				t.Errorf("Expected %v; got %v instead", keyconfig.ErrDestroyed, err)
+			}
+			if config := crypto.Config(); config != nil {
+				t.Errorf("Expected %v; got %v instead", nil, config)
+			}
+		})
+	}
+}
+
+func TestDestroyWipesKey(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	crypto, err := encryption.NewAES256GCMFromBytes(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crypto.Destroy()
+
+	if !bytes.Equal(key, make([]byte, 32)) {
+		t.Errorf("Expected %v; got %v instead", make([]byte, 32), key)
+	}
+}
+
 func getAlgos() []encryption.Algorithm {
 	naclPriv, naclPub, _ := box.GenerateKey(rand.Reader)
 	rsaPriv, _ := rsa.GenerateKey(rand.Reader, 2048)
 
 	singleKey := make([]byte, 32)
 	rand.Read(singleKey)
+	doubleKey := make([]byte, 64)
+	rand.Read(doubleKey)
 
 	return []encryption.Algorithm{
 		suppressError(encryption.NewAES(string(singleKey))),
 		suppressError(encryption.NewAES256(string(singleKey))),
 		suppressError(encryption.NewAES256CBC(string(singleKey))),
 		suppressError(encryption.NewAES256GCM(string(singleKey))),
+		suppressError(encryption.NewAESSIV(string(doubleKey))),
 		suppressError(encryption.NewChaCha20Poly1305(string(singleKey))),
 		encryption.NewNaClBox(naclPriv, naclPub),
 		encryption.NewRSA(rsaPriv),
@@ -97,3 +245,139 @@ func getAlgos() []encryption.Algorithm {
 func suppressError(in encryption.Algorithm, _ error) encryption.Algorithm {
 	return in
 }
+
+func TestConfigErrors(t *testing.T) {
+	tests := map[string]struct {
+		name     string
+		config   map[string]interface{}
+		expected error
+	}{
+		"unknown":   {"bogus", nil, encryption.ErrUnknownAlgorithm},
+		"missing":   {"aes256gcm", map[string]interface{}{}, keyconfig.ErrMissingKey},
+		"encoding":  {"aes256gcm", map[string]interface{}{"key": "not hex"}, keyconfig.ErrKeyEncoding},
+		"length":    {"xchacha20", map[string]interface{}{"key": "abcdef"}, keyconfig.ErrKeyLength},
+		"naclbox":   {"naclbox", map[string]interface{}{"private_key": strings.Repeat("00", 32)}, keyconfig.ErrMissingKey},
+		"malformed": {"rsa", map[string]interface{}{"key": "abcdef"}, keyconfig.ErrKeyEncoding},
+	}
+
+	for label, test := range tests {
+		t.Run(label, func(t *testing.T) {
+			algo, err := encryption.New(test.name, test.config)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("Expected %v; got %v instead", test.expected, err)
+			}
+			if algo != nil {
+				t.Errorf("Expected nil; got %v instead", algo)
+			}
+		})
+	}
+}
+
+func TestDEK(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	if err := os.WriteFile(path, []byte("current: test\nkeys:\n  test: "+strings.Repeat("ab", 32)+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	crypto, err := encryption.New("dek", map[string]interface{}{"provider": "local", "path": path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := map[string]interface{}{"provider": "local", "path": path}; !reflect.DeepEqual(crypto.Config(), expected) {
+		t.Errorf("Expected %v; got %v instead", expected, crypto.Config())
+	}
+
+	expected := []byte("Test")
+	crypted, err := crypto.Encrypt(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := crypto.Decrypt(crypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("Expected %v; got %v instead", expected, actual)
+	}
+
+	again, _ := crypto.Encrypt(expected)
+	if bytes.Equal(again[:len(again)-len(expected)], crypted[:len(crypted)-len(expected)]) {
+		t.Error("Expected a fresh data key for every value")
+	}
+
+	if _, err := encryption.New("dek", map[string]interface{}{"path": path}); !errors.Is(err, keyconfig.ErrMissingKey) {
+		t.Errorf("Expected %v; got %v instead", keyconfig.ErrMissingKey, err)
+	}
+
+	cached, err := encryption.New("dek", map[string]interface{}{"provider": "local", "path": path, "cache_ttl": "30s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := map[string]interface{}{"provider": "local", "path": path, "cache_ttl": "30s"}; !reflect.DeepEqual(cached.Config(), expected) {
+		t.Errorf("Expected %v; got %v instead", expected, cached.Config())
+	}
+	var field *keyconfig.FieldError
+	if _, err := encryption.New("dek", map[string]interface{}{"provider": "local", "path": path, "cache_ttl": "soon"}); !errors.As(err, &field) || field.Field != "cache_ttl" {
+		t.Errorf("Expected an error for cache_ttl; got %v instead", err)
+	}
+}
+
+func TestDEKCache(t *testing.T) {
+	provider := kms.NewFake()
+	crypto := encryption.NewDEK(provider)
+
+	crypted, err := crypto.Encrypt([]byte("Test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := crypto.Decrypt(crypted); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, unwraps := provider.Calls(); unwraps != 1 {
+		t.Errorf("Expected 1 unwrap with the cache; got %d instead", unwraps)
+	}
+
+	crypto.SetCacheTTL(0)
+	for i := 0; i < 2; i++ {
+		if _, err := crypto.Decrypt(crypted); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, unwraps := provider.Calls(); unwraps != 3 {
+		t.Errorf("Expected an unwrap for every value without the cache; got %d instead of 3", unwraps)
+	}
+}
+
+func TestAESSIV(t *testing.T) {
+	// RFC 5297, Appendix A.1
+	key, _ := hex.DecodeString("fffefdfcfbfaf9f8f7f6f5f4f3f2f1f0f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff")
+	associated, _ := hex.DecodeString("101112131415161718191a1b1c1d1e1f2021222324252627")
+	plain, _ := hex.DecodeString("112233445566778899aabbccddee")
+	expected, _ := hex.DecodeString("85632d07c6e8f37f950acd320a2ecc9340c02b9690c4dc04daef7f6afe5c")
+
+	siv, err := encryption.NewAESSIV(string(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual := siv.Seal(plain, associated); !bytes.Equal(actual, expected) {
+		t.Errorf("Expected %x; got %x instead", expected, actual)
+	}
+	if actual, err := siv.Open(expected, associated); err != nil || !bytes.Equal(actual, plain) {
+		t.Errorf("Expected %x; got %x (%v) instead", plain, actual, err)
+	}
+	if _, err := siv.Open(expected); err == nil {
+		t.Error("Expected an error opening without the associated data; got none")
+	}
+
+	first, _ := siv.Encrypt(plain)
+	second, _ := siv.Encrypt(plain)
+	if !bytes.Equal(first, second) {
+		t.Errorf("Expected deterministic output; got %x and %x", first, second)
+	}
+	if _, ok := encryption.Algorithm(siv).(encryption.DeterministicAlgorithm); !ok {
+		t.Error("Expected AESSIV to be a DeterministicAlgorithm")
+	}
+}
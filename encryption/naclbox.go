@@ -6,29 +6,42 @@ import (
 	"errors"
 	"io"
 
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
 	"golang.org/x/crypto/nacl/box"
 )
 
 func init() {
-	RegisterAlgo("naclbox", func(m map[string]interface{}) Algorithm {
-		privKeySlice, _ := hex.DecodeString(m["private_key"].(string))
+	Register("naclbox", func(m map[string]interface{}) (Algorithm, error) {
+		privKeySlice, err := keyconfig.HexSized(m, "private_key", 32)
+		if err != nil {
+			return nil, err
+		}
 		privKey := [32]byte{}
 		copy(privKey[:], privKeySlice)
+		keyconfig.Wipe(privKeySlice)
 
-		pubKeySlice, _ := hex.DecodeString(m["public_key"].(string))
+		pubKeySlice, err := keyconfig.HexSized(m, "public_key", 32)
+		if err != nil {
+			return nil, err
+		}
 		pubKey := [32]byte{}
 		copy(pubKey[:], pubKeySlice)
 
-		return NewNaClBox(&privKey, &pubKey)
+		algo := NewNaClBox(&privKey, &pubKey)
+		keyconfig.Wipe(privKey[:])
+
+		return algo, nil
 	})
 }
 
 // NaClBox supports NaClBox enrcryption of arbitrary data
 type NaClBox struct {
 	Algorithm
-	privateKey [32]byte
-	publicKey  [32]byte
-	sharedKey  [32]byte
+	privateKey  [32]byte
+	publicKey   [32]byte
+	sharedKey   [32]byte
+	destroyed   bool
+	fingerprint []byte
 }
 
 // Name identifies the Algorithm as a string for exporting configurations
@@ -38,26 +51,40 @@ func (NaClBox) Name() string {
 
 // Config converts an Algorthim's internal configuration into a map for export
 func (e NaClBox) Config() map[string]interface{} {
+	if e.destroyed {
+		return nil
+	}
+
 	return map[string]interface{}{
 		"private_key": hex.EncodeToString(e.privateKey[:]),
 		"public_key":  hex.EncodeToString(e.publicKey[:]),
 	}
 }
 
-// NewNaClBox creates a new NaClBox value
-func NewNaClBox(privateKey, publicKey *[32]byte) *NaClBox {
-	sharedEncryptKey := new([32]byte)
-	box.Precompute(sharedEncryptKey, publicKey, privateKey)
+// KeyFingerprint returns a one-way hash of the keys, computed as the Algorithm was created
+func (e NaClBox) KeyFingerprint() []byte {
+	return e.fingerprint
+}
 
-	return &NaClBox{
-		privateKey: *privateKey,
-		publicKey:  *publicKey,
-		sharedKey:  *sharedEncryptKey,
+// NewNaClBox creates a new NaClBox value.
+// The keys are copied, so the caller should wipe its own copy of the private key once it's no longer needed.
+func NewNaClBox(privateKey, publicKey *[32]byte) *NaClBox {
+	e := &NaClBox{
+		privateKey:  *privateKey,
+		publicKey:   *publicKey,
+		fingerprint: keyconfig.Fingerprint(privateKey[:], publicKey[:]),
 	}
+	box.Precompute(&e.sharedKey, publicKey, privateKey)
+
+	return e
 }
 
 // Encrypt ::: NaClBox
 func (e *NaClBox) Encrypt(plain []byte) ([]byte, error) {
+	if e.destroyed {
+		return nil, keyconfig.ErrDestroyed
+	}
+
 	var nonce [24]byte
 	if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
 		return nil, err
@@ -70,6 +97,10 @@ func (e *NaClBox) Encrypt(plain []byte) ([]byte, error) {
 
 // Decrypt ::: NaClBox
 func (e *NaClBox) Decrypt(crypted []byte) ([]byte, error) {
+	if e.destroyed {
+		return nil, keyconfig.ErrDestroyed
+	}
+
 	var nonce [24]byte
 	copy(nonce[:], crypted[:24])
 
@@ -80,3 +111,9 @@ func (e *NaClBox) Decrypt(crypted []byte) ([]byte, error) {
 
 	return decrypted, nil
 }
+
+// Destroy wipes the private and shared keys, after which the NaClBox value refuses to encrypt or decrypt anything
+func (e *NaClBox) Destroy() {
+	keyconfig.Wipe(e.privateKey[:], e.sharedKey[:])
+	e.destroyed = true
+}
@@ -6,21 +6,31 @@ import (
 	"crypto/sha512"
 	"crypto/x509"
 	"encoding/hex"
+
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
 )
 
 func init() {
-	RegisterAlgo("rsa", func(m map[string]interface{}) Algorithm {
-		data, _ := hex.DecodeString(m["key"].(string))
-		privKey, _ := x509.ParsePKCS1PrivateKey(data)
-		algo := NewRSA(privKey)
-		return algo
+	Register("rsa", func(m map[string]interface{}) (Algorithm, error) {
+		data, err := keyconfig.Hex(m, "key")
+		if err != nil {
+			return nil, err
+		}
+		privKey, err := x509.ParsePKCS1PrivateKey(data)
+		keyconfig.Wipe(data)
+		if err != nil {
+			return nil, keyconfig.Errorf("key", "%w: %v", keyconfig.ErrKeyEncoding, err)
+		}
+
+		return NewRSA(privKey), nil
 	})
 }
 
 // RSA supports RSA
 type RSA struct {
 	Algorithm
-	privateKey *rsa.PrivateKey
+	privateKey  *rsa.PrivateKey
+	fingerprint []byte
 }
 
 // Name identifies the Algorithm as a string for exporting configurations
@@ -30,20 +40,36 @@ func (RSA) Name() string {
 
 // Config converts an Algorthim's internal configuration into a map for export
 func (e RSA) Config() map[string]interface{} {
+	if e.privateKey == nil {
+		return nil
+	}
+
 	return map[string]interface{}{
 		"key": hex.EncodeToString(x509.MarshalPKCS1PrivateKey(e.privateKey)),
 	}
 }
 
+// KeyFingerprint returns a one-way hash of the public key, computed as the Algorithm was created
+func (e RSA) KeyFingerprint() []byte {
+	return e.fingerprint
+}
+
 // NewRSA creates a new RSA value
 func NewRSA(privateKey *rsa.PrivateKey) *RSA {
-	return &RSA{
-		privateKey: privateKey,
+	e := &RSA{privateKey: privateKey}
+	if privateKey != nil {
+		e.fingerprint = keyconfig.Fingerprint(x509.MarshalPKCS1PublicKey(&privateKey.PublicKey))
 	}
+
+	return e
 }
 
 // Encrypt encrypts data with public key
 func (e *RSA) Encrypt(plain []byte) ([]byte, error) {
+	if e.privateKey == nil {
+		return nil, keyconfig.ErrDestroyed
+	}
+
 	hash := sha512.New()
 	crypted, err := rsa.EncryptOAEP(hash, rand.Reader, &e.privateKey.PublicKey, plain, nil)
 	if err != nil {
@@ -54,6 +80,10 @@ func (e *RSA) Encrypt(plain []byte) ([]byte, error) {
 
 // Decrypt decrypts data with private key
 func (e *RSA) Decrypt(crypted []byte) ([]byte, error) {
+	if e.privateKey == nil {
+		return nil, keyconfig.ErrDestroyed
+	}
+
 	hash := sha512.New()
 	plain, err := rsa.DecryptOAEP(hash, rand.Reader, e.privateKey, crypted, nil)
 	if err != nil {
@@ -61,3 +91,25 @@ func (e *RSA) Decrypt(crypted []byte) ([]byte, error) {
 	}
 	return plain, nil
 }
+
+// Destroy wipes the private key's exponent, primes, and precomputed values, after which the RSA value refuses to encrypt or decrypt anything.
+// Copies the crypto/rsa package keeps internally can't be reached, so they're left for the GC.
+func (e *RSA) Destroy() {
+	if e.privateKey == nil {
+		return
+	}
+
+	keyconfig.WipeInt(e.privateKey.D)
+	for _, prime := range e.privateKey.Primes {
+		keyconfig.WipeInt(prime)
+	}
+	keyconfig.WipeInt(e.privateKey.Precomputed.Dp)
+	keyconfig.WipeInt(e.privateKey.Precomputed.Dq)
+	keyconfig.WipeInt(e.privateKey.Precomputed.Qinv)
+	for _, crt := range e.privateKey.Precomputed.CRTValues {
+		keyconfig.WipeInt(crt.Exp)
+		keyconfig.WipeInt(crt.Coeff)
+		keyconfig.WipeInt(crt.R)
+	}
+	e.privateKey = nil
+}
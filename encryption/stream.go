@@ -0,0 +1,223 @@
+package encryption
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
+	"golang.org/x/crypto/hkdf"
+)
+
+// StreamChunkSize is the amount of plaintext, in bytes, sealed into each chunk of an encrypted stream
+const StreamChunkSize = 64 * 1024
+
+// StreamAlgorithm is implemented by Algorithms which can encrypt values too large to hold in memory, one chunk at a time.
+// Streams use the STREAM construction: every stream gets its own key, derived from a random salt stored at its start,
+// and every chunk is sealed with a nonce built from its position and a flag marking the final chunk,
+// so chunks can't be reordered, dropped, or truncated without the stream failing to decrypt.
+type StreamAlgorithm interface {
+	Algorithm
+	// EncryptStream returns a WriteCloser which encrypts everything written to it onto w, authenticating the associated data along with every chunk.
+	// The stream isn't complete until the WriteCloser is closed; closing it doesn't close w.
+	EncryptStream(w io.Writer, associated []byte) (io.WriteCloser, error)
+	// DecryptStream returns a Reader which decrypts the stream read from r, failing unless the associated data matches that given to EncryptStream.
+	// Each chunk is authenticated before any of it is returned, but a stream can still fail partway through, so don't trust what's read until io.EOF.
+	DecryptStream(r io.Reader, associated []byte) (io.Reader, error)
+}
+
+// ErrStreamCorrupt is returned when reading an encrypted stream which has been altered, truncated, or encrypted under another key
+var ErrStreamCorrupt = errors.New("encrypted stream is corrupt or truncated")
+
+// PRIVATE
+
+const streamSaltSize = 32
+
+var streamInfo = []byte("gormcrypto stream v1")
+
+var errStreamClosed = errors.New("encrypted stream is already closed")
+
+// streamCipher creates the AEAD used to seal a stream's chunks from the stream's own key
+type streamCipher func(key []byte) (cipher.AEAD, error)
+
+// streamAEAD derives a stream's own key from the Algorithm's key and the stream's salt
+func streamAEAD(key, salt []byte, newAEAD streamCipher) (cipher.AEAD, error) {
+	streamKey := make([]byte, 32)
+	defer keyconfig.Wipe(streamKey)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, key, salt, streamInfo), streamKey); err != nil {
+		return nil, err
+	}
+
+	return newAEAD(streamKey)
+}
+
+// streamNonce builds the nonce for a chunk from its position in the stream, and whether it's the last one
+func streamNonce(aead cipher.AEAD, counter uint64, last bool) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce[len(nonce)-9:], counter)
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+
+	return nonce
+}
+
+func newStreamWriter(w io.Writer, key []byte, newAEAD streamCipher, associated []byte) (io.WriteCloser, error) {
+	salt := make([]byte, streamSaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+
+	aead, err := streamAEAD(key, salt, newAEAD)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(salt); err != nil {
+		return nil, err
+	}
+
+	return &streamWriter{w: w, aead: aead, associated: associated, plain: make([]byte, 0, StreamChunkSize)}, nil
+}
+
+type streamWriter struct {
+	w          io.Writer
+	aead       cipher.AEAD
+	associated []byte
+	plain      []byte
+	sealed     []byte
+	counter    uint64
+	err        error
+}
+
+// Write buffers plaintext until a full chunk is available, and seals it once more plaintext arrives, since only Close knows which chunk is last
+func (s *streamWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if s.err != nil {
+			return written, s.err
+		}
+		if len(s.plain) == StreamChunkSize {
+			s.err = s.seal(false)
+			continue
+		}
+
+		n := copy(s.plain[len(s.plain):StreamChunkSize], p)
+		s.plain = s.plain[:len(s.plain)+n]
+		p = p[n:]
+		written += n
+	}
+
+	return written, nil
+}
+
+// Close seals the final chunk, which may be empty, completing the stream
+func (s *streamWriter) Close() error {
+	if s.err != nil {
+		return s.err
+	}
+	if s.err = s.seal(true); s.err != nil {
+		return s.err
+	}
+	s.err = errStreamClosed
+
+	return nil
+}
+
+func (s *streamWriter) seal(last bool) error {
+	if s.counter == math.MaxUint64 {
+		return errors.New("encrypted stream is too long")
+	}
+
+	s.sealed = s.aead.Seal(s.sealed[:0], streamNonce(s.aead, s.counter, last), s.plain, s.associated)
+	keyconfig.Wipe(s.plain)
+	s.plain = s.plain[:0]
+	s.counter++
+
+	_, err := s.w.Write(s.sealed)
+	return err
+}
+
+func newStreamReader(r io.Reader, key []byte, newAEAD streamCipher, associated []byte) (io.Reader, error) {
+	salt := make([]byte, streamSaltSize)
+	if _, err := io.ReadFull(r, salt); err != nil {
+		return nil, ErrStreamCorrupt
+	}
+
+	aead, err := streamAEAD(key, salt, newAEAD)
+	if err != nil {
+		return nil, err
+	}
+
+	return &streamReader{r: r, aead: aead, associated: associated, sealed: make([]byte, StreamChunkSize+aead.Overhead()+1)}, nil
+}
+
+type streamReader struct {
+	r          io.Reader
+	aead       cipher.AEAD
+	associated []byte
+	sealed     []byte
+	buffered   int
+	plain      []byte
+	opened     []byte
+	counter    uint64
+	done       bool
+	err        error
+}
+
+// Read returns plaintext from the current chunk, opening the next one once it's used up
+func (s *streamReader) Read(p []byte) (int, error) {
+	for len(s.plain) < 1 {
+		if s.err != nil {
+			return 0, s.err
+		}
+		if s.done {
+			keyconfig.Wipe(s.opened)
+			return 0, io.EOF
+		}
+		s.err = s.open()
+	}
+
+	n := copy(p, s.plain)
+	s.plain = s.plain[n:]
+
+	return n, nil
+}
+
+// open reads and opens the next chunk.
+// One byte past the chunk is read ahead, to tell whether it's the last one; that byte starts the next chunk.
+func (s *streamReader) open() error {
+	n, err := io.ReadFull(s.r, s.sealed[s.buffered:])
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	available := s.buffered + n
+	chunkSize := len(s.sealed) - 1
+
+	last := available <= chunkSize
+	if last && available < s.aead.Overhead() {
+		return ErrStreamCorrupt
+	}
+	if !last {
+		available = chunkSize
+	}
+
+	s.opened, err = s.aead.Open(s.opened[:0], streamNonce(s.aead, s.counter, last), s.sealed[:available], s.associated)
+	if err != nil {
+		return ErrStreamCorrupt
+	}
+	s.plain = s.opened
+	s.counter++
+	s.done = last
+
+	s.buffered = 0
+	if !last {
+		s.sealed[0] = s.sealed[chunkSize]
+		s.buffered = 1
+	}
+
+	return nil
+}
@@ -2,40 +2,74 @@
 package gormcrypto
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"reflect"
 	"sort"
+	"sync"
 	"time"
 
+	"github.com/danhunsaker/gorm-crypto/blindindex"
+	"github.com/danhunsaker/gorm-crypto/compression"
 	"github.com/danhunsaker/gorm-crypto/encoding"
 	"github.com/danhunsaker/gorm-crypto/encryption"
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
+	"github.com/danhunsaker/gorm-crypto/keywrap"
 	"github.com/danhunsaker/gorm-crypto/serializing"
 	"github.com/danhunsaker/gorm-crypto/signing"
 	"gopkg.in/yaml.v3"
 )
 
 // Config provides the global configuration data for gormcrypto.
-// At the moment, that's just a list of different Setups your application supports.
+// Mostly, that's a list of different Setups your application supports.
 // We support multiple Setups because application requirements change over time,
 // and you'll want to be able to use values encrypted/signed by older keys/algorithms.
 // The Time value used in the map indicates when the Setup was - or should be - made active in your code.
+// The SignaturePolicy decides what happens when signed values fail verification as they're read, unless their fields are tagged with a policy of their own;
+// the SignatureCallback policy passes a pointer to each such value to OnInvalidSignature, which may change it, or return an error to fail the read.
+// The MarshalPolicy decides how such values are marshaled to JSON, text, and YAML afterwards, whichever SignaturePolicy they were read under.
+// OnWarning receives the Config's warnings (see Config.Warn), in place of the standard logger.
 type Config struct {
-	Setups map[time.Time]Setup
+	Setups             map[time.Time]Setup
+	SignaturePolicy    SignaturePolicy
+	MarshalPolicy      MarshalPolicy
+	OnInvalidSignature func(value interface{}) error
+	OnWarning          func(message string)
 }
 
 // Setup describes the way your data should be handled by gormcrypto.
 // That includes the encryption algorithm/keys, the signing algorithm/keys,
 // the mechanism for serializing values, and the encoding to use to coerce binary data into values that can safely be serialized/stored.
+// The ID is stored alongside every value the Setup produces, so that value can be read back with the exact same Setup later on.
+// If left empty, the Setup's Fingerprint is used instead.
+// The optional Compressor shrinks values before they're encrypted.
+// The optional DeterministicEncrypter is used by the DeterministicEncrypted types, in place of the Encrypter.
+// The optional BlindIndexer computes the BlindIndex columns which make encrypted values searchable.
+// The State, along with the optional NotBefore and NotAfter times, controls what the Setup may still be used for.
+// The Storage selects the kind of column values written with the Setup are stored in, unless their fields are tagged with one of their own.
+// References records which keys were loaded from the environment, files, or secrets, keyed by component (encryption, signing, etc.),
+// so ConfigToBytes can write the References back out instead of the keys themselves.
 type Setup struct {
+	ID         string
 	Encoder    encoding.Algorithm
 	Serializer serializing.Algorithm
 	Encrypter  encryption.Algorithm
 	Signer     signing.Algorithm
+	State      SetupState
+	NotBefore  time.Time
+	NotAfter   time.Time
+	Storage    Storage
+	References map[string][]keyconfig.Reference
+
+	DeterministicEncrypter encryption.DeterministicAlgorithm
+	BlindIndexer           blindindex.Algorithm
+	Compressor             compression.Algorithm
 }
 
 // Init sets up gormcrypto for use by telling it which Config to use.
-// NOTE: This function may be deprecated at some point if I can work out how to properly make gormcrypto into a GORM plugin.
+// The global Config set here is only a fallback; each *gorm.DB can use its own Config by registering a Plugin instead.
 func Init(c Config) error {
 	if len(c.Setups) < 1 {
 		return errors.New("database cryptography configuration incomplete")
@@ -45,68 +79,193 @@ func Init(c Config) error {
 	return nil
 }
 
-// GlobalConfig gets the global config value.
-// NOTE: This function will be deprecated at some point if I can work out how to properly make gormcrypto into a GORM plugin.
+// GlobalConfig gets the global config value, used by values which haven't been bound to a Config by a Plugin.
 func GlobalConfig() Config {
 	return config
 }
 
-// ConfigFromBytes converts a YAML document into a valid Config object
-func ConfigFromBytes(contents []byte) (c Config) {
-	var parsed yamlContents
-	if err := yaml.Unmarshal(contents, &parsed); err == nil {
-		c.Setups = make(map[time.Time]Setup, len(parsed))
-		for setupTime, setupValue := range parsed {
-			c.Setups[setupTime] = Setup{
-				Encoder:    encoding.FromYaml(setupValue.Encoding.Algorithm, setupValue.Encoding.Config),
-				Serializer: serializing.FromYaml(setupValue.Serializing.Algorithm, setupValue.Serializing.Config),
-				Encrypter:  encryption.FromYaml(setupValue.Encryption.Algorithm, setupValue.Encryption.Config),
-				Signer:     signing.FromYaml(setupValue.Signing.Algorithm, setupValue.Signing.Config),
-			}
-		}
-	}
+// ConfigFromBytes converts a YAML document into a valid Config object, which is empty if the document has any problems; use LoadConfig to find out what they are
+func ConfigFromBytes(contents []byte, options ...Option) Config {
+	c, _ := LoadConfig(contents, options...)
 
-	return
+	return c
 }
 
-// ConfigToBytes converts a Config value into a YAML-encoded byte slice for export to a file or other storage
-func (c Config) ConfigToBytes() ([]byte, error) {
+// ConfigToBytes converts a Config value into a YAML-encoded byte slice for export to a file or other storage.
+// Keys which were loaded through References are exported as those References, rather than as the keys themselves.
+// Other keys are exported in cleartext, unless a key wrapper is given using WithKeyWrapper.
+func (c Config) ConfigToBytes(options ...Option) ([]byte, error) {
+	opts := newOptions(options)
 	configStruct := make(yamlContents, len(c.Setups))
 
 	for t, s := range c.Setups {
-		configStruct[t] = yamlSetup{
-			Encoding: yamlSetupAlgorithm{
-				Algorithm: s.Encoder.Name(),
-				Config:    s.Encoder.Config(),
-			},
-			Serializing: yamlSetupAlgorithm{
-				Algorithm: s.Serializer.Name(),
-				Config:    s.Serializer.Config(),
-			},
-			Encryption: yamlSetupAlgorithm{
-				Algorithm: s.Encrypter.Name(),
-				Config:    s.Encrypter.Config(),
-			},
-			Signing: yamlSetupAlgorithm{
-				Algorithm: s.Signer.Name(),
-				Config:    s.Signer.Config(),
-			},
+		setup := yamlSetup{
+			ID:        s.ID,
+			State:     s.State,
+			Storage:   s.Storage,
+			NotBefore: s.NotBefore,
+			NotAfter:  s.NotAfter,
+		}
+
+		export := func(name string, algo interface {
+			Name() string
+			Config() map[string]interface{}
+		}) (yamlSetupAlgorithm, error) {
+			path := t.Format(time.RFC3339Nano) + "." + name
+			config, err := keywrap.WrapConfig(algo.Config(), opts.wrapper, path)
+			if err != nil {
+				return yamlSetupAlgorithm{}, &ConfigError{Path: path, Err: err}
+			}
+
+			return yamlSetupAlgorithm{
+				Algorithm: algo.Name(),
+				Config:    keyconfig.Unresolve(config, s.References[name]),
+			}, nil
+		}
+
+		var err error
+		if setup.Encoding, err = export("encoding", s.Encoder); err != nil {
+			return nil, err
+		}
+		if setup.Serializing, err = export("serializing", s.Serializer); err != nil {
+			return nil, err
 		}
+		if setup.Encryption, err = export("encryption", s.Encrypter); err != nil {
+			return nil, err
+		}
+		if setup.Signing, err = export("signing", s.Signer); err != nil {
+			return nil, err
+		}
+		if s.DeterministicEncrypter != nil {
+			deterministic, err := export("deterministic_encryption", s.DeterministicEncrypter)
+			if err != nil {
+				return nil, err
+			}
+			setup.DeterministicEncryption = &deterministic
+		}
+		if s.BlindIndexer != nil {
+			indexer, err := export("blind_index", s.BlindIndexer)
+			if err != nil {
+				return nil, err
+			}
+			setup.BlindIndex = &indexer
+		}
+		if s.Compressor != nil {
+			compressor, err := export("compression", s.Compressor)
+			if err != nil {
+				return nil, err
+			}
+			setup.Compression = &compressor
+		}
+
+		configStruct[t] = setup
 	}
 
 	return yaml.Marshal(configStruct)
 }
 
-// CurrentSetup returns the most recent Setup value based on the Time it was set up under
+// CurrentSetup returns the most recent Setup value based on the Time it was set up under, skipping any which can't currently be used to write new values.
+// If no Setup can be used, the zero Setup is returned; use ActiveSetup to get an error instead.
 func (c Config) CurrentSetup() Setup {
-	return c.UsedSetup(time.Now())
+	setup, _ := c.ActiveSetup()
+
+	return setup
+}
+
+// ActiveSetup returns the Setup to use for writing new values, as CurrentSetup does, or an error if no Setup can currently be used that way
+func (c Config) ActiveSetup() (Setup, error) {
+	now := time.Now()
+	setup, ok := c.selectSetup(now, func(s Setup) bool {
+		return s.CanWrite(now)
+	})
+	if !ok {
+		return setup, errors.New("no database cryptography Setup can currently be used to write values")
+	}
+
+	return setup, nil
 }
 
-// UsedSetup returns the most recent Setup value based on the passed Time, falling back to CurrentSetup
+// UsedSetup returns the most recent Setup value based on the passed Time, falling back to CurrentSetup.
+// Retired Setups are skipped.
 func (c Config) UsedSetup(at time.Time) Setup {
+	setup, _ := c.selectSetup(at, Setup.CanRead)
+
+	return setup
+}
+
+// SetupByID returns the Setup whose Identifier matches the one given, if any
+func (c Config) SetupByID(id string) (Setup, bool) {
+	for _, s := range c.Setups {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	for _, s := range c.Setups {
+		if s.ID == "" && s.Fingerprint() == id {
+			return s, true
+		}
+	}
+
+	return Setup{}, false
+}
+
+// Identifier returns the ID stored with values produced by the Setup; either its explicit ID, or its Fingerprint if it doesn't have one
+func (s Setup) Identifier() string {
+	if s.ID != "" {
+		return s.ID
+	}
+
+	return s.Fingerprint()
+}
+
+// Fingerprint derives a stable identifier for the Setup from the components needed to read the values it writes - its Encoder, Serializer,
+// Encrypter, and Signer, along with their keys - so the others, such as its Compressor, can change without orphaning those values.
+// Keys are covered by the hashes their Algorithms compute as they're created (see keyconfig.Fingerprinter), so they're never copied to build it,
+// and it stays the same after the Setup is destroyed; the key material itself can't be recovered from it.
+func (s Setup) Fingerprint() string {
+	return fingerprint(s.fingerprinted())
+}
+
+// Destroy wipes the key material held by every Setup's Algorithms from memory, once the Config is no longer needed.
+// Copies of the Config share the same Algorithms, so none of them can be used to encrypt, decrypt, sign, verify, or index anything afterwards.
+func (c Config) Destroy() {
+	for _, setup := range c.Setups {
+		setup.Destroy()
+	}
+}
+
+// Destroy wipes the key material held by the Setup's Algorithms from memory, for those which implement keyconfig.Destroyer.
+// All of the Algorithms provided by gormcrypto do; the Setup can't be used afterwards.
+func (s Setup) Destroy() {
+	if key, ok := fingerprintCacheKey(s.fingerprinted()); ok {
+		fingerprints.Delete(key)
+	}
+	for _, algo := range []interface{}{s.Encrypter, s.Signer, s.DeterministicEncrypter, s.BlindIndexer} {
+		if destroyer, ok := algo.(keyconfig.Destroyer); ok {
+			destroyer.Destroy()
+		}
+	}
+}
+
+// String converts the Setup to a string that indicates its components in a useful fashion
+func (s Setup) String() string {
+	return fmt.Sprintf("{%s %s %s %s}", reflect.TypeOf(s.Encoder).String(), reflect.TypeOf(s.Serializer).String(), reflect.TypeOf(s.Encrypter).String(), reflect.TypeOf(s.Signer).String())
+}
+
+// PRIVATE
+
+var config Config
+
+// selectSetup finds the most recent usable Setup set up at or before the passed Time, falling back to the most recent usable Setup overall
+func (c Config) selectSetup(at time.Time, usable func(Setup) bool) (Setup, bool) {
 	keys := make([]time.Time, 0, len(c.Setups))
-	for t := range c.Setups {
-		keys = append(keys, t)
+	for t, s := range c.Setups {
+		if usable(s) {
+			keys = append(keys, t)
+		}
+	}
+	if len(keys) < 1 {
+		return Setup{}, false
 	}
 	sort.Slice(keys, func(i, j int) bool {
 		return keys[i].Before(keys[j])
@@ -119,20 +278,73 @@ func (c Config) UsedSetup(at time.Time) Setup {
 		}
 	}
 	if len(filtered) > 0 {
-		return c.Setups[filtered[len(filtered)-1]]
+		return c.Setups[filtered[len(filtered)-1]], true
 	}
 
-	return c.Setups[keys[len(keys)-1]]
+	return c.Setups[keys[len(keys)-1]], true
 }
 
-// String converts the Setup to a string that indicates its components in a useful fashion
-func (s Setup) String() string {
-	return fmt.Sprintf("{%s %s %s %s}", reflect.TypeOf(s.Encoder).String(), reflect.TypeOf(s.Serializer).String(), reflect.TypeOf(s.Encrypter).String(), reflect.TypeOf(s.Signer).String())
+const fingerprintSize = 8
+
+// fingerprints caches the fingerprints computed for each set of algorithms, until their Setup is destroyed
+var fingerprints sync.Map
+
+type fingerprintAlgorithm interface {
+	Name() string
+	Config() map[string]interface{}
 }
 
-// PRIVATE
+// fingerprintKey identifies a set of algorithms in the fingerprints cache
+type fingerprintKey [4]interface{}
 
-var config Config
+// fingerprinted lists the algorithms a Setup's Fingerprint covers
+func (s Setup) fingerprinted() fingerprintKey {
+	return fingerprintKey{s.Encoder, s.Serializer, s.Encrypter, s.Signer}
+}
+
+// fingerprintCacheKey returns the key a set of algorithms is cached under; algorithms which can't be compared can't be cached
+func fingerprintCacheKey(algos fingerprintKey) (fingerprintKey, bool) {
+	for _, algo := range algos {
+		if algo != nil && !reflect.TypeOf(algo).Comparable() {
+			return fingerprintKey{}, false
+		}
+	}
+
+	return algos, true
+}
+
+// fingerprint hashes the names and configurations of a set of algorithms, skipping any which are nil.
+// Algorithms which are keyconfig.Fingerprinters are hashed by name and KeyFingerprint instead, so their keys aren't exported to do it.
+func fingerprint(algos fingerprintKey) string {
+	key, cacheable := fingerprintCacheKey(algos)
+	if cacheable {
+		if cached, ok := fingerprints.Load(key); ok {
+			return cached.(string)
+		}
+	}
+
+	hash := sha256.New()
+	for _, value := range algos {
+		algo, ok := value.(fingerprintAlgorithm)
+		if !ok {
+			continue
+		}
+		if fingerprinter, ok := algo.(keyconfig.Fingerprinter); ok {
+			hash.Write(keyconfig.Fingerprint([]byte(algo.Name()), fingerprinter.KeyFingerprint()))
+			continue
+		}
+		// yaml.Marshal sorts map keys, so this is stable for a given configuration
+		encoded, _ := yaml.Marshal(yamlSetupAlgorithm{Algorithm: algo.Name(), Config: algo.Config()})
+		hash.Write(encoded)
+	}
+	out := hex.EncodeToString(hash.Sum(nil)[:fingerprintSize])
+
+	if cacheable {
+		fingerprints.Store(key, out)
+	}
+
+	return out
+}
 
 type yamlSetupAlgorithm struct {
 	Algorithm string                 `yaml:"algorithm"`
@@ -140,10 +352,19 @@ type yamlSetupAlgorithm struct {
 }
 
 type yamlSetup struct {
+	ID          string             `yaml:"id,omitempty"`
 	Encoding    yamlSetupAlgorithm `yaml:"encoding"`
 	Serializing yamlSetupAlgorithm `yaml:"serializing"`
 	Encryption  yamlSetupAlgorithm `yaml:"encryption"`
 	Signing     yamlSetupAlgorithm `yaml:"signing"`
+	State       SetupState         `yaml:"state,omitempty"`
+	Storage     Storage            `yaml:"storage,omitempty"`
+	NotBefore   time.Time          `yaml:"not_before,omitempty"`
+	NotAfter    time.Time          `yaml:"not_after,omitempty"`
+
+	DeterministicEncryption *yamlSetupAlgorithm `yaml:"deterministic_encryption,omitempty"`
+	BlindIndex              *yamlSetupAlgorithm `yaml:"blind_index,omitempty"`
+	Compression             *yamlSetupAlgorithm `yaml:"compression,omitempty"`
 }
 
 type yamlContents map[time.Time]yamlSetup
@@ -1,16 +1,23 @@
 package gormcrypto_test
 
 import (
+	"errors"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"testing"
 	"time"
 
 	gormcrypto "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/blindindex"
+	"github.com/danhunsaker/gorm-crypto/compression"
 	"github.com/danhunsaker/gorm-crypto/encoding"
 	"github.com/danhunsaker/gorm-crypto/encryption"
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
 	"github.com/danhunsaker/gorm-crypto/serializing"
 	"github.com/danhunsaker/gorm-crypto/signing"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 )
 
 func TestInitGlobalConfig(t *testing.T) {
@@ -79,17 +86,89 @@ func TestSetupToString(t *testing.T) {
 	}
 }
 
+func TestSetupIdentifiers(t *testing.T) {
+	config := getTestConfig()
+
+	for _, setup := range config.Setups {
+		found, ok := config.SetupByID(setup.Identifier())
+		if !ok {
+			t.Errorf("Expected to find setup %v; got nothing", setup.Identifier())
+		} else if !reflect.DeepEqual(found, setup) {
+			t.Errorf("Expected %v; got %v instead", setup, found)
+		}
+		if setup.ID == "" && setup.Identifier() != setup.Fingerprint() {
+			t.Errorf("Expected %v; got %v instead", setup.Fingerprint(), setup.Identifier())
+		}
+	}
+
+	if _, ok := config.SetupByID("unknown"); ok {
+		t.Error("Expected no setup for an unknown ID")
+	}
+
+	changed := config.CurrentSetup()
+	changed.Signer = signing.NewED25519FromSeed("AnotherSigningKeyThat's32Bytes!!")
+	if changed.Fingerprint() == config.CurrentSetup().Fingerprint() {
+		t.Error("Expected fingerprint to change along with the keys")
+	}
+
+	compressed := config.CurrentSetup()
+	compressed.Compressor = compression.Gzip{Threshold: 1024}
+	if compressed.Fingerprint() != config.CurrentSetup().Fingerprint() {
+		t.Error("Expected fingerprint to ignore components other than the keys")
+	}
+}
+
+func TestSetupFingerprintDestroyed(t *testing.T) {
+	expected := getTestConfig().CurrentSetup().Fingerprint()
+
+	// Fingerprints come from hashes taken as the keys were loaded, so they still work once the keys are gone
+	config := getTestConfig()
+	config.Destroy()
+	if actual := config.CurrentSetup().Fingerprint(); actual != expected {
+		t.Errorf("Expected %v; got %v instead", expected, actual)
+	}
+}
+
+func TestConfigDestroy(t *testing.T) {
+	config := getTestConfig()
+	config.Destroy()
+
+	for _, setup := range config.Setups {
+		if _, err := setup.Encrypter.Encrypt([]byte("Test")); !errors.Is(err, keyconfig.ErrDestroyed) {
+			t.Errorf("Expected %v; got %v instead", keyconfig.ErrDestroyed, err)
+		}
+		if _, err := setup.Signer.Sign([]byte("Test")); !errors.Is(err, keyconfig.ErrDestroyed) {
+			t.Errorf("Expected %v; got %v instead", keyconfig.ErrDestroyed, err)
+		}
+		if setup.DeterministicEncrypter != nil {
+			if _, err := setup.DeterministicEncrypter.Encrypt([]byte("Test")); !errors.Is(err, keyconfig.ErrDestroyed) {
+				t.Errorf("Expected %v; got %v instead", keyconfig.ErrDestroyed, err)
+			}
+		}
+		if setup.BlindIndexer != nil {
+			if _, err := setup.BlindIndexer.Index([]byte("Test")); !errors.Is(err, keyconfig.ErrDestroyed) {
+				t.Errorf("Expected %v; got %v instead", keyconfig.ErrDestroyed, err)
+			}
+		}
+	}
+}
+
 func getTestConfig() gormcrypto.Config {
 	enc, _ := encryption.NewXChaCha20Poly1305("EncryptionKeyThatShouldBe32Bytes")
 	sig := signing.NewED25519FromSeed("SigningKeyThatShouldBe32BytesToo")
+	siv, _ := encryption.NewAESSIV("DeterministicKeyThatShouldBe64BytesLongSoThatBothHalvesAre32Byte")
+	bidx, _ := blindindex.NewHMACSHA256("BlindIndexKeyThatShouldBe32Bytes")
 
 	return gormcrypto.Config{
 		Setups: map[time.Time]gormcrypto.Setup{
 			time.Now().UTC(): {
-				Encoder:    encoding.Base64{},
-				Serializer: serializing.JSON{},
-				Encrypter:  enc,
-				Signer:     sig,
+				Encoder:                encoding.Base64{},
+				Serializer:             serializing.JSON{},
+				Encrypter:              enc,
+				Signer:                 sig,
+				DeterministicEncrypter: siv,
+				BlindIndexer:           bidx,
+				Compressor:             compression.Gzip{Threshold: 64},
 			},
 			time.Now().Add(-1 * time.Hour).UTC(): {
 				Encoder:    encoding.Hex{},
@@ -98,6 +177,7 @@ func getTestConfig() gormcrypto.Config {
 				Signer:     sig,
 			},
 			time.Now().Add(-2 * time.Hour).UTC(): {
+				ID:         "legacy",
 				Encoder:    encoding.ASCII85{},
 				Serializer: serializing.GOB{},
 				Encrypter:  enc,
@@ -106,3 +186,52 @@ func getTestConfig() gormcrypto.Config {
 		},
 	}
 }
+
+// newTestSetup builds a Setup which encrypts with AES-256-GCM and signs with ED25519, under the given 32-byte keys
+func newTestSetup(t *testing.T, encryptionKey, signingKey string) gormcrypto.Setup {
+	enc, err := encryption.NewAES256GCM(encryptionKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return gormcrypto.Setup{
+		Encoder:    encoding.Base64{},
+		Serializer: serializing.JSON{},
+		Encrypter:  enc,
+		Signer:     signing.NewED25519FromSeed(signingKey),
+	}
+}
+
+// newTestConfig builds a Config from the given Setups, each an hour newer than the last, so the final one is current
+func newTestConfig(setups ...gormcrypto.Setup) gormcrypto.Config {
+	config := gormcrypto.Config{Setups: make(map[time.Time]gormcrypto.Setup, len(setups))}
+	for i, setup := range setups {
+		config.Setups[time.Now().Add(time.Duration(i+1-len(setups))*time.Hour).UTC()] = setup
+	}
+
+	return config
+}
+
+// openTestDB opens the SQLite DB at path - or a new one, if it's empty - using a Plugin with the given Config, and migrates the given models
+func openTestDB(t *testing.T, path string, config gormcrypto.Config, models ...interface{}) *gorm.DB {
+	plugin, err := gormcrypto.NewPlugin(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if path == "" {
+		path = filepath.Join(t.TempDir(), "test.db")
+	}
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Use(plugin); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
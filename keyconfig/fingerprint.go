@@ -0,0 +1,33 @@
+package keyconfig
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// Fingerprinter is implemented by Algorithms which hold key material, so Setups can be told apart by their keys without exporting them.
+// All of the keyed Algorithms provided by gormcrypto do; Algorithms which don't are told apart by their Config instead.
+type Fingerprinter interface {
+	// KeyFingerprint returns a one-way hash of the Algorithm's key material, computed once as the Algorithm is created, and kept after it's destroyed
+	KeyFingerprint() []byte
+}
+
+// Fingerprint hashes key material for KeyFingerprint. Each key is prefixed by its length, so different sets of keys never hash the same way.
+func Fingerprint(keys ...[]byte) []byte {
+	hash := sha256.New()
+	hash.Write([]byte(fingerprintDomain))
+
+	size := make([]byte, 8)
+	for _, key := range keys {
+		binary.BigEndian.PutUint64(size, uint64(len(key)))
+		hash.Write(size)
+		hash.Write(key)
+	}
+
+	return hash.Sum(nil)
+}
+
+// PRIVATE
+
+// fingerprintDomain separates key fingerprints from any other hash of the same key material
+const fingerprintDomain = "gormcrypto key fingerprint\x00"
@@ -0,0 +1,73 @@
+// Package keyconfig helps Algorithms read key material from their configuration maps,
+// defines the errors they report when that key material is missing or malformed,
+// and helps them wipe that key material from memory once they're destroyed.
+package keyconfig
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// The problems which can be found with key material in a configuration map.
+// Errors returned by this package wrap one of these, so they can be checked with errors.Is.
+var (
+	ErrMissingKey  = errors.New("missing key")
+	ErrKeyLength   = errors.New("wrong key length")
+	ErrKeyEncoding = errors.New("bad key encoding")
+)
+
+// FieldError describes a problem with a single field of an Algorithm's configuration map
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+// Error describes the problem, prefixed by the field it was found in
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+// Unwrap exposes the underlying problem to errors.Is and errors.As
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Errorf creates a FieldError for a field, formatting its underlying problem as fmt.Errorf would
+func Errorf(field, format string, args ...interface{}) error {
+	return &FieldError{Field: field, Err: fmt.Errorf(format, args...)}
+}
+
+// Hex reads a hex-encoded value from a field of a configuration map
+func Hex(m map[string]interface{}, field string) ([]byte, error) {
+	raw, ok := m[field]
+	if !ok || raw == nil {
+		return nil, &FieldError{Field: field, Err: ErrMissingKey}
+	}
+
+	encoded, ok := raw.(string)
+	if !ok {
+		return nil, Errorf(field, "%w: expected a hex string; got %T instead", ErrKeyEncoding, raw)
+	}
+
+	decoded, err := hex.DecodeString(encoded)
+	if err != nil {
+		return nil, Errorf(field, "%w: %v", ErrKeyEncoding, err)
+	}
+
+	return decoded, nil
+}
+
+// HexSized reads a hex-encoded value from a field of a configuration map, which must decode to exactly size bytes
+func HexSized(m map[string]interface{}, field string, size int) ([]byte, error) {
+	decoded, err := Hex(m, field)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(decoded) != size {
+		return nil, Errorf(field, "%w: expected %d bytes; got %d instead", ErrKeyLength, size, len(decoded))
+	}
+
+	return decoded, nil
+}
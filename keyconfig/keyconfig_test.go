@@ -0,0 +1,45 @@
+package keyconfig_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
+)
+
+func TestHex(t *testing.T) {
+	config := map[string]interface{}{"good": "0102", "bad": "xyz", "number": 12}
+
+	actual, err := keyconfig.Hex(config, "good")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []byte{1, 2}; !bytes.Equal(actual, expected) {
+		t.Errorf("Expected %v; got %v instead", expected, actual)
+	}
+
+	tests := map[string]error{"missing": keyconfig.ErrMissingKey, "bad": keyconfig.ErrKeyEncoding, "number": keyconfig.ErrKeyEncoding}
+	for field, expected := range tests {
+		_, err := keyconfig.Hex(config, field)
+		if !errors.Is(err, expected) {
+			t.Errorf("Expected %v; got %v instead", expected, err)
+		}
+
+		var fieldErr *keyconfig.FieldError
+		if !errors.As(err, &fieldErr) || fieldErr.Field != field {
+			t.Errorf("Expected an error for field %v; got %v instead", field, err)
+		}
+	}
+}
+
+func TestHexSized(t *testing.T) {
+	config := map[string]interface{}{"key": "010203"}
+
+	if _, err := keyconfig.HexSized(config, "key", 3); err != nil {
+		t.Error(err)
+	}
+	if _, err := keyconfig.HexSized(config, "key", 4); !errors.Is(err, keyconfig.ErrKeyLength) {
+		t.Errorf("Expected %v; got %v instead", keyconfig.ErrKeyLength, err)
+	}
+}
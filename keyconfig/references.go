@@ -0,0 +1,158 @@
+package keyconfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// The kinds of Reference a configuration map can contain, each used as a suffix on the name of the field it provides.
+// For example, key_env names an environment variable holding the value of key.
+const (
+	KindEnv    = "env"
+	KindFile   = "file"
+	KindSecret = "secret"
+)
+
+// DefaultSecretDir is where DefaultResolver looks for secret files unless told otherwise
+const DefaultSecretDir = "/run/secrets"
+
+// ErrUnresolved is returned when a Reference can't be resolved to a value
+var ErrUnresolved = errors.New("unresolved key reference")
+
+// Reference records where the value of a field in a configuration map comes from, instead of the value itself
+type Reference struct {
+	// Field is the name of the field the Reference provides a value for, such as key
+	Field string
+	// Kind is how Target should be interpreted; one of KindEnv, KindFile, or KindSecret
+	Kind string
+	// Target is the environment variable, file, or secret name holding the value
+	Target string
+}
+
+// Key is the name of the configuration map field which holds the Reference, such as key_env
+func (r Reference) Key() string {
+	return r.Field + "_" + r.Kind
+}
+
+// Resolver looks up the values References point to
+type Resolver interface {
+	Resolve(ref Reference) (string, error)
+}
+
+// ResolverFunc allows a plain function to be used as a Resolver
+type ResolverFunc func(ref Reference) (string, error)
+
+// Resolve calls the function itself
+func (f ResolverFunc) Resolve(ref Reference) (string, error) {
+	return f(ref)
+}
+
+// DefaultResolver reads References from the environment, from files, and from a directory of secret files,
+// such as those Docker and Kubernetes mount into containers
+type DefaultResolver struct {
+	// SecretDir is the directory secret References are read from; DefaultSecretDir is used if it's empty
+	SecretDir string
+}
+
+// Resolve looks up the value a Reference points to, trimming any surrounding whitespace
+func (d DefaultResolver) Resolve(ref Reference) (string, error) {
+	switch ref.Kind {
+	case KindEnv:
+		value, ok := os.LookupEnv(ref.Target)
+		if !ok {
+			return "", fmt.Errorf("%w: environment variable %s is not set", ErrUnresolved, ref.Target)
+		}
+		return strings.TrimSpace(value), nil
+	case KindFile:
+		return readFile(ref.Target)
+	case KindSecret:
+		if ref.Target == "" || filepath.Base(ref.Target) != ref.Target {
+			return "", fmt.Errorf("%w: invalid secret name %q", ErrUnresolved, ref.Target)
+		}
+		dir := d.SecretDir
+		if dir == "" {
+			dir = DefaultSecretDir
+		}
+		return readFile(filepath.Join(dir, ref.Target))
+	}
+
+	return "", fmt.Errorf("%w: unknown reference kind %q", ErrUnresolved, ref.Kind)
+}
+
+// Resolve replaces every Reference in a configuration map with the value it points to, returning a new map along with the References it found.
+// The original map is left untouched, and is returned as-is if it holds no References.
+func Resolve(m map[string]interface{}, resolver Resolver) (map[string]interface{}, []Reference, error) {
+	refs := make([]Reference, 0)
+	for key, value := range m {
+		for _, kind := range []string{KindEnv, KindFile, KindSecret} {
+			if !strings.HasSuffix(key, "_"+kind) {
+				continue
+			}
+
+			target, ok := value.(string)
+			if !ok {
+				return nil, nil, Errorf(key, "%w: expected a string; got %T instead", ErrUnresolved, value)
+			}
+			refs = append(refs, Reference{Field: strings.TrimSuffix(key, "_"+kind), Kind: kind, Target: target})
+		}
+	}
+	if len(refs) < 1 {
+		return m, nil, nil
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].Key() < refs[j].Key()
+	})
+
+	resolved := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		resolved[key] = value
+	}
+	for _, ref := range refs {
+		if _, ok := m[ref.Field]; ok {
+			return nil, nil, Errorf(ref.Key(), "%w: %s is also set directly", ErrUnresolved, ref.Field)
+		}
+
+		value, err := resolver.Resolve(ref)
+		if err != nil {
+			return nil, nil, &FieldError{Field: ref.Key(), Err: err}
+		}
+		delete(resolved, ref.Key())
+		resolved[ref.Field] = value
+	}
+
+	return resolved, refs, nil
+}
+
+// Unresolve replaces the values References point to with the References themselves, so configuration maps can be exported without their secrets.
+// The original map is left untouched.
+func Unresolve(m map[string]interface{}, refs []Reference) map[string]interface{} {
+	if len(refs) < 1 {
+		return m
+	}
+
+	unresolved := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		unresolved[key] = value
+	}
+	for _, ref := range refs {
+		delete(unresolved, ref.Field)
+		unresolved[ref.Key()] = ref.Target
+	}
+
+	return unresolved
+}
+
+// PRIVATE
+
+func readFile(path string) (string, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnresolved, err)
+	}
+
+	return strings.TrimSpace(string(contents)), nil
+}
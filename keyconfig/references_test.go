@@ -0,0 +1,75 @@
+package keyconfig_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
+)
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "signing"), []byte("0102\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KEYCONFIG_TEST_KEY", "0304")
+
+	config := map[string]interface{}{
+		"key_env":            "KEYCONFIG_TEST_KEY",
+		"public_key_file":    filepath.Join(dir, "signing"),
+		"private_key_secret": "signing",
+		"other":              "untouched",
+	}
+	resolved, refs, err := keyconfig.Resolve(config, keyconfig.DefaultResolver{SecretDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{"key": "0304", "public_key": "0102", "private_key": "0102", "other": "untouched"}
+	if !reflect.DeepEqual(resolved, expected) {
+		t.Errorf("Expected %v; got %v instead", expected, resolved)
+	}
+	if len(refs) != 3 {
+		t.Errorf("Expected 3 references; got %v instead", refs)
+	}
+
+	if unresolved := keyconfig.Unresolve(resolved, refs); !reflect.DeepEqual(unresolved, config) {
+		t.Errorf("Expected %v; got %v instead", config, unresolved)
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"env":       {"key_env": "KEYCONFIG_TEST_UNSET"},
+		"file":      {"key_file": filepath.Join(t.TempDir(), "missing")},
+		"secret":    {"key_secret": "../escape"},
+		"duplicate": {"key": "00", "key_env": "PATH"},
+		"type":      {"key_file": 42},
+	}
+
+	for label, config := range tests {
+		t.Run(label, func(t *testing.T) {
+			_, _, err := keyconfig.Resolve(config, keyconfig.DefaultResolver{})
+			if !errors.Is(err, keyconfig.ErrUnresolved) {
+				t.Errorf("Expected %v; got %v instead", keyconfig.ErrUnresolved, err)
+			}
+		})
+	}
+}
+
+func TestResolverFunc(t *testing.T) {
+	resolver := keyconfig.ResolverFunc(func(ref keyconfig.Reference) (string, error) {
+		return ref.Kind + ":" + ref.Target, nil
+	})
+
+	resolved, _, err := keyconfig.Resolve(map[string]interface{}{"key_secret": "vault/aes"}, resolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved["key"] != "secret:vault/aes" {
+		t.Errorf("Expected %v; got %v instead", "secret:vault/aes", resolved["key"])
+	}
+}
@@ -0,0 +1,39 @@
+package keyconfig
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ErrDestroyed is returned by Algorithms which have been asked to work after their key material was destroyed
+var ErrDestroyed = errors.New("key material has been destroyed")
+
+// Destroyer is implemented by Algorithms which can wipe their key material from memory once they're no longer needed.
+// A destroyed Algorithm refuses to do any further work, returning ErrDestroyed instead.
+// Destroy must not be called while the Algorithm is still in use elsewhere, and calling it more than once does nothing.
+type Destroyer interface {
+	// Destroy overwrites the Algorithm's key material, and drops any ciphers derived from it
+	Destroy()
+}
+
+// Wipe overwrites buffers holding key material or plaintext with zeroes
+func Wipe(buffers ...[]byte) {
+	for _, buffer := range buffers {
+		for i := range buffer {
+			buffer[i] = 0
+		}
+	}
+}
+
+// WipeInt overwrites the digits of a secret big.Int - such as a private key's exponent - with zeroes, then sets it to zero
+func WipeInt(i *big.Int) {
+	if i == nil {
+		return
+	}
+
+	words := i.Bits()
+	for j := range words {
+		words[j] = 0
+	}
+	i.SetInt64(0)
+}
@@ -0,0 +1,165 @@
+// Package keywrap protects the key material in exported configurations, by encrypting each key under a master key or a passphrase.
+// Wrapped keys are stored as self-describing strings, so the rest of the configuration stays readable.
+package keywrap
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Prefix marks a configuration value as a wrapped key
+const Prefix = "gcwrap:"
+
+// The problems which can be found when unwrapping a key
+var (
+	ErrNoWrapper = errors.New("key is wrapped, but no key wrapper was given")
+	ErrUnwrap    = errors.New("unable to unwrap key")
+)
+
+// Wrapper encrypts keys for export, and decrypts them again on import.
+// The associated data - such as the path to the key in its configuration - is authenticated along with each key, so it can only be unwrapped with the same.
+type Wrapper interface {
+	// Wrap encrypts a key, returning a string starting with Prefix
+	Wrap(key, associated []byte) (string, error)
+	// Unwrap decrypts a key previously encrypted by Wrap with the same associated data
+	Unwrap(wrapped string, associated []byte) ([]byte, error)
+}
+
+// IsWrapped reports whether a configuration value holds a wrapped key
+func IsWrapped(value interface{}) bool {
+	s, ok := value.(string)
+
+	return ok && strings.HasPrefix(s, Prefix)
+}
+
+// WrapConfig wraps every string value in a configuration map, returning a new map.
+// Each value is bound to the path of its field - the given path, a dot, and the field's name - so it can't be moved to another field or setup.
+// The original map is returned as-is if there's no Wrapper.
+func WrapConfig(m map[string]interface{}, w Wrapper, path string) (map[string]interface{}, error) {
+	if w == nil || len(m) < 1 {
+		return m, nil
+	}
+
+	wrapped := make(map[string]interface{}, len(m))
+	for field, value := range m {
+		if s, ok := value.(string); ok {
+			var err error
+			if value, err = w.Wrap([]byte(s), fieldPath(path, field)); err != nil {
+				return nil, &keyconfig.FieldError{Field: field, Err: err}
+			}
+		}
+		wrapped[field] = value
+	}
+
+	return wrapped, nil
+}
+
+// UnwrapConfig unwraps every wrapped key in a configuration map, returning a new map.
+// The path must be the one the map was wrapped with by WrapConfig.
+// The original map is returned as-is if none of its values are wrapped.
+func UnwrapConfig(m map[string]interface{}, w Wrapper, path string) (map[string]interface{}, error) {
+	var unwrapped map[string]interface{}
+	for field, value := range m {
+		if !IsWrapped(value) {
+			continue
+		}
+		if w == nil {
+			return nil, &keyconfig.FieldError{Field: field, Err: ErrNoWrapper}
+		}
+
+		key, err := w.Unwrap(value.(string), fieldPath(path, field))
+		if err != nil {
+			return nil, &keyconfig.FieldError{Field: field, Err: err}
+		}
+
+		if unwrapped == nil {
+			unwrapped = make(map[string]interface{}, len(m))
+			for f, v := range m {
+				unwrapped[f] = v
+			}
+		}
+		unwrapped[field] = string(key)
+	}
+
+	if unwrapped == nil {
+		return m, nil
+	}
+
+	return unwrapped, nil
+}
+
+// MasterKey wraps keys under a single 32-byte key, using XChaCha20-Poly1305
+type MasterKey struct {
+	aead cipher.AEAD
+}
+
+// NewMasterKey creates a new MasterKey value
+func NewMasterKey(key []byte) (*MasterKey, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MasterKey{aead: aead}, nil
+}
+
+// Wrap encrypts a key, returning a string starting with Prefix
+func (m *MasterKey) Wrap(key, associated []byte) (string, error) {
+	sealed, err := seal(m.aead, key, associated)
+	if err != nil {
+		return "", err
+	}
+
+	return Prefix + masterScheme + ":" + sealed, nil
+}
+
+// Unwrap decrypts a key previously encrypted by Wrap with the same associated data
+func (m *MasterKey) Unwrap(wrapped string, associated []byte) ([]byte, error) {
+	parts := strings.Split(strings.TrimPrefix(wrapped, Prefix), ":")
+	if len(parts) != 2 || parts[0] != masterScheme {
+		return nil, fmt.Errorf("%w: not wrapped with a master key", ErrUnwrap)
+	}
+
+	return open(m.aead, parts[1], associated)
+}
+
+// PRIVATE
+
+const masterScheme = "master"
+
+func fieldPath(path, field string) []byte {
+	return []byte(path + "." + field)
+}
+
+func seal(aead cipher.AEAD, plaintext, associated []byte) (string, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return base64.RawStdEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, associated)), nil
+}
+
+func open(aead cipher.AEAD, encoded string, associated []byte) ([]byte, error) {
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnwrap, err)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("%w: too short", ErrUnwrap)
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], associated)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnwrap, err)
+	}
+
+	return plaintext, nil
+}
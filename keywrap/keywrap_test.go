@@ -0,0 +1,135 @@
+package keywrap_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
+	"github.com/danhunsaker/gorm-crypto/keywrap"
+)
+
+func TestWrappers(t *testing.T) {
+	for name, wrapper := range getWrappers(t, "correct horse battery staple") {
+		t.Run(name, func(t *testing.T) {
+			expected := []byte("456e6372797074696f6e4b6579")
+			associated := []byte("2006-01-02T15:04:05Z.encryption.key")
+
+			wrapped, err := wrapper.Wrap(expected, associated)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !keywrap.IsWrapped(wrapped) || strings.Contains(wrapped, string(expected)) {
+				t.Errorf("Expected a wrapped key; got %v instead", wrapped)
+			}
+
+			actual, err := wrapper.Unwrap(wrapped, associated)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(actual, expected) {
+				t.Errorf("Expected %v; got %v instead", expected, actual)
+			}
+
+			if _, err := getWrappers(t, "wrong passphrase")[name].Unwrap(wrapped, associated); !errors.Is(err, keywrap.ErrUnwrap) {
+				t.Errorf("Expected %v; got %v instead", keywrap.ErrUnwrap, err)
+			}
+			if _, err := wrapper.Unwrap(wrapped, []byte("2006-01-02T15:04:05Z.signing.key")); !errors.Is(err, keywrap.ErrUnwrap) {
+				t.Errorf("Expected %v; got %v instead", keywrap.ErrUnwrap, err)
+			}
+			if _, err := wrapper.Unwrap(wrapped[:len(wrapped)-4], associated); !errors.Is(err, keywrap.ErrUnwrap) {
+				t.Errorf("Expected %v; got %v instead", keywrap.ErrUnwrap, err)
+			}
+		})
+	}
+}
+
+func TestWrapConfig(t *testing.T) {
+	wrapper := getWrappers(t, "correct horse battery staple")["master"]
+	config := map[string]interface{}{"key": "0102", "rounds": 4}
+
+	wrapped, err := keywrap.WrapConfig(config, wrapper, "first.encryption")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !keywrap.IsWrapped(wrapped["key"]) || wrapped["rounds"] != 4 {
+		t.Errorf("Expected only strings to be wrapped; got %v instead", wrapped)
+	}
+
+	unwrapped, err := keywrap.UnwrapConfig(wrapped, wrapper, "first.encryption")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(unwrapped, config) {
+		t.Errorf("Expected %v; got %v instead", config, unwrapped)
+	}
+
+	if _, err := keywrap.UnwrapConfig(wrapped, nil, "first.encryption"); !errors.Is(err, keywrap.ErrNoWrapper) {
+		t.Errorf("Expected %v; got %v instead", keywrap.ErrNoWrapper, err)
+	}
+	if _, err := keywrap.UnwrapConfig(wrapped, wrapper, "second.encryption"); !errors.Is(err, keywrap.ErrUnwrap) {
+		t.Errorf("Expected %v; got %v instead", keywrap.ErrUnwrap, err)
+	}
+}
+
+func TestPassphraseLimits(t *testing.T) {
+	wrapper := getWrappers(t, "correct horse battery staple")["argon2id"]
+	salt := base64.RawStdEncoding.EncodeToString(make([]byte, 16))
+	sealed := base64.RawStdEncoding.EncodeToString(make([]byte, 64))
+
+	for _, params := range []string{
+		fmt.Sprintf("argon2id:%d,%d,%d", keywrap.MaxArgon2Time+1, keywrap.DefaultArgon2Memory, keywrap.DefaultArgon2Threads),
+		fmt.Sprintf("argon2id:%d,%d,%d", keywrap.DefaultArgon2Time, keywrap.MaxArgon2Memory+1, keywrap.DefaultArgon2Threads),
+		fmt.Sprintf("argon2id:%d,%d,%d", keywrap.DefaultArgon2Time, keywrap.DefaultArgon2Memory, keywrap.MaxArgon2Threads+1),
+		fmt.Sprintf("scrypt:%d,%d,%d", keywrap.MaxScryptN*2, keywrap.DefaultScryptR, keywrap.DefaultScryptP),
+		fmt.Sprintf("scrypt:%d,%d,%d", keywrap.DefaultScryptN, keywrap.MaxScryptR+1, keywrap.DefaultScryptP),
+		fmt.Sprintf("scrypt:%d,%d,%d", keywrap.DefaultScryptN, keywrap.DefaultScryptR, keywrap.MaxScryptP+1),
+		fmt.Sprintf("scrypt:%d,%d,%d", keywrap.MaxScryptN, keywrap.MaxScryptR, keywrap.DefaultScryptP),
+	} {
+		if _, err := wrapper.Unwrap(keywrap.Prefix+params+":"+salt+":"+sealed, nil); !errors.Is(err, keywrap.ErrUnwrap) {
+			t.Errorf("Expected %v for %v; got %v instead", keywrap.ErrUnwrap, params, err)
+		}
+	}
+}
+
+func TestPassphraseDestroy(t *testing.T) {
+	wrapper, err := keywrap.NewPassphrase("correct horse battery staple", keywrap.Scrypt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrapped, err := wrapper.Wrap([]byte("0102"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wrapper.Destroy()
+	wrapper.Destroy()
+
+	if _, err := wrapper.Unwrap(wrapped, nil); !errors.Is(err, keyconfig.ErrDestroyed) {
+		t.Errorf("Expected %v; got %v instead", keyconfig.ErrDestroyed, err)
+	}
+	if _, err := wrapper.Wrap([]byte("0102"), nil); !errors.Is(err, keyconfig.ErrDestroyed) {
+		t.Errorf("Expected %v; got %v instead", keyconfig.ErrDestroyed, err)
+	}
+}
+
+func getWrappers(t *testing.T, passphrase string) map[string]keywrap.Wrapper {
+	master, err := keywrap.NewMasterKey([]byte(passphrase + strings.Repeat("!", 32-len(passphrase))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	argon, err := keywrap.NewPassphrase(passphrase, keywrap.Argon2id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scrypt, err := keywrap.NewPassphrase(passphrase, keywrap.Scrypt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return map[string]keywrap.Wrapper{"master": master, "argon2id": argon, "scrypt": scrypt}
+}
@@ -0,0 +1,204 @@
+package keywrap
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+)
+
+// KDF selects the function used to stretch a passphrase into a key
+type KDF string
+
+// The supported KDFs
+const (
+	Argon2id KDF = "argon2id"
+	Scrypt   KDF = "scrypt"
+)
+
+// Default KDF parameters, following the recommendations in RFC 9106 and the scrypt package respectively.
+// Argon2id uses the RFC's second recommended option, for memory-constrained environments: t=3, m=64 MiB, p=4.
+// Keys already wrapped keep unwrapping with whichever parameters they were wrapped with, since those are stored alongside them.
+const (
+	DefaultArgon2Time    uint32 = 3
+	DefaultArgon2Memory  uint32 = 64 * 1024
+	DefaultArgon2Threads uint8  = 4
+	DefaultScryptN              = 32768
+	DefaultScryptR              = 8
+	DefaultScryptP              = 1
+)
+
+// The largest KDF parameters Unwrap accepts, so a tampered configuration can't exhaust the process's memory or stall it indefinitely.
+// Argon2id's memory is in KiB, as with the defaults, and scrypt's memory use - 128 * N * r bytes - is limited to MaxScryptMemory.
+const (
+	MaxArgon2Time    uint32 = 64
+	MaxArgon2Memory  uint32 = 1024 * 1024
+	MaxArgon2Threads uint8  = 64
+	MaxScryptN              = 1 << 22
+	MaxScryptR              = 64
+	MaxScryptP              = 64
+	MaxScryptMemory         = 1 << 30
+)
+
+// Passphrase wraps keys under a key stretched from a passphrase.
+// The KDF and its parameters are stored with each wrapped key, so keys can be unwrapped with nothing but the passphrase.
+// Stretched keys are cached until Destroy is called.
+type Passphrase struct {
+	kdf        KDF
+	passphrase []byte
+
+	mutex   sync.Mutex
+	params  string
+	derived map[string]cipher.AEAD
+	secrets [][]byte
+}
+
+// NewPassphrase creates a new Passphrase value, using the given KDF with its default parameters when wrapping
+func NewPassphrase(passphrase string, kdf KDF) (*Passphrase, error) {
+	if kdf != Argon2id && kdf != Scrypt {
+		return nil, fmt.Errorf("unknown KDF %q", kdf)
+	}
+
+	return &Passphrase{
+		kdf:        kdf,
+		passphrase: []byte(passphrase),
+		derived:    make(map[string]cipher.AEAD),
+	}, nil
+}
+
+// Wrap encrypts a key, returning a string starting with Prefix.
+// A single salt is used for everything a Passphrase value wraps, so the KDF only has to run once per export.
+func (p *Passphrase) Wrap(key, associated []byte) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.derived == nil {
+		return "", keyconfig.ErrDestroyed
+	}
+	if p.params == "" {
+		salt := make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return "", err
+		}
+
+		switch p.kdf {
+		case Argon2id:
+			p.params = fmt.Sprintf("%s:%d,%d,%d", Argon2id, DefaultArgon2Time, DefaultArgon2Memory, DefaultArgon2Threads)
+		case Scrypt:
+			p.params = fmt.Sprintf("%s:%d,%d,%d", Scrypt, DefaultScryptN, DefaultScryptR, DefaultScryptP)
+		}
+		p.params += ":" + base64.RawStdEncoding.EncodeToString(salt)
+	}
+
+	aead, err := p.aead(p.params)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := seal(aead, key, associated)
+	if err != nil {
+		return "", err
+	}
+
+	return Prefix + p.params + ":" + sealed, nil
+}
+
+// Unwrap decrypts a key previously encrypted by Wrap, using whichever KDF and parameters it was wrapped with
+func (p *Passphrase) Unwrap(wrapped string, associated []byte) ([]byte, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.derived == nil {
+		return nil, keyconfig.ErrDestroyed
+	}
+	rest := strings.TrimPrefix(wrapped, Prefix)
+	split := strings.LastIndex(rest, ":")
+	if split < 0 {
+		return nil, fmt.Errorf("%w: malformed wrapped key", ErrUnwrap)
+	}
+
+	aead, err := p.aead(rest[:split])
+	if err != nil {
+		return nil, err
+	}
+
+	return open(aead, rest[split+1:], associated)
+}
+
+// Destroy overwrites the passphrase and the keys stretched from it, after which Wrap and Unwrap return keyconfig.ErrDestroyed
+func (p *Passphrase) Destroy() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	keyconfig.Wipe(p.passphrase)
+	keyconfig.Wipe(p.secrets...)
+	p.passphrase, p.derived, p.secrets = nil, nil, nil
+}
+
+// PRIVATE
+
+const saltSize = 16
+
+// aead derives the key for a set of KDF parameters, formatted as kdf:a,b,c:salt, caching it for reuse
+func (p *Passphrase) aead(params string) (cipher.AEAD, error) {
+	if aead, ok := p.derived[params]; ok {
+		return aead, nil
+	}
+
+	parts := strings.Split(params, ":")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed wrapped key", ErrUnwrap)
+	}
+	costs := strings.Split(parts[1], ",")
+	if len(costs) != 3 {
+		return nil, fmt.Errorf("%w: malformed KDF parameters %q", ErrUnwrap, parts[1])
+	}
+	values := make([]uint64, len(costs))
+	for i, cost := range costs {
+		value, err := strconv.ParseUint(cost, 10, 32)
+		if err != nil || value == 0 {
+			return nil, fmt.Errorf("%w: malformed KDF parameters %q", ErrUnwrap, parts[1])
+		}
+		values[i] = value
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnwrap, err)
+	}
+
+	var key []byte
+	switch KDF(parts[0]) {
+	case Argon2id:
+		if values[0] > uint64(MaxArgon2Time) || values[1] > uint64(MaxArgon2Memory) || values[2] > uint64(MaxArgon2Threads) {
+			return nil, fmt.Errorf("%w: KDF parameters %q exceed the limits", ErrUnwrap, parts[1])
+		}
+		key = argon2.IDKey(p.passphrase, salt, uint32(values[0]), uint32(values[1]), uint8(values[2]), chacha20poly1305.KeySize)
+	case Scrypt:
+		if values[0] > MaxScryptN || values[1] > MaxScryptR || values[2] > MaxScryptP || 128*values[0]*values[1] > MaxScryptMemory {
+			return nil, fmt.Errorf("%w: KDF parameters %q exceed the limits", ErrUnwrap, parts[1])
+		}
+		if key, err = scrypt.Key(p.passphrase, salt, int(values[0]), int(values[1]), int(values[2]), chacha20poly1305.KeySize); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnwrap, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: not wrapped with a passphrase", ErrUnwrap)
+	}
+
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		keyconfig.Wipe(key)
+		return nil, err
+	}
+	p.derived[params] = aead
+	p.secrets = append(p.secrets, key)
+
+	return aead, nil
+}
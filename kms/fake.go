@@ -0,0 +1,68 @@
+package kms
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sync/atomic"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Fake is an in-process KeyProvider holding random key-encryption keys, for use in tests.
+// Its keys only last as long as the Fake itself, so it isn't registered for use in YAML configs.
+type Fake struct {
+	*keyring
+	generation int64
+	wraps      int64
+	unwraps    int64
+}
+
+// NewFake creates a new Fake value with a single random key-encryption key
+func NewFake() *Fake {
+	f := &Fake{keyring: newKeyring()}
+	f.Rotate()
+
+	return f
+}
+
+// Name identifies the KeyProvider as a string for exporting configurations
+func (*Fake) Name() string {
+	return "fake"
+}
+
+// Config converts a KeyProvider's internal configuration into a map for export
+func (*Fake) Config() map[string]interface{} {
+	return map[string]interface{}{}
+}
+
+// Rotate adds a new random key-encryption key and makes it current, returning its ID. Older keys can still unwrap data keys.
+func (f *Fake) Rotate() string {
+	key := make([]byte, chacha20poly1305.KeySize)
+	rand.Read(key)
+
+	id := fmt.Sprintf("fake-%d", atomic.AddInt64(&f.generation, 1))
+	f.add(id, key)
+	f.use(id)
+
+	return id
+}
+
+// Calls reports how many times WrapKey and UnwrapKey have been called
+func (f *Fake) Calls() (wraps, unwraps int) {
+	return int(atomic.LoadInt64(&f.wraps)), int(atomic.LoadInt64(&f.unwraps))
+}
+
+// WrapKey encrypts a data key with the current key-encryption key
+func (f *Fake) WrapKey(_ context.Context, key []byte) ([]byte, error) {
+	atomic.AddInt64(&f.wraps, 1)
+
+	return f.wrap(key)
+}
+
+// UnwrapKey decrypts a data key with whichever key-encryption key wrapped it
+func (f *Fake) UnwrapKey(_ context.Context, wrapped []byte) ([]byte, error) {
+	atomic.AddInt64(&f.unwraps, 1)
+
+	return f.unwrap(wrapped)
+}
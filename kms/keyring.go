@@ -0,0 +1,125 @@
+package kms
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// keyring holds a set of named key-encryption keys, one of which is current, and wraps data keys with them.
+// Wrapped keys record the name of the key-encryption key used, which is also bound to the ciphertext as associated data.
+// The keys themselves are kept rather than copied, so that Destroy can wipe them.
+type keyring struct {
+	mutex   sync.RWMutex
+	current string
+	keys    map[string]cipher.AEAD
+	secrets [][]byte
+}
+
+func newKeyring() *keyring {
+	return &keyring{keys: make(map[string]cipher.AEAD)}
+}
+
+func (k *keyring) add(id string, key []byte) error {
+	if len(id) < 1 || len(id) > 255 {
+		return fmt.Errorf("key-encryption key IDs must be 1 to 255 bytes long; got %q", id)
+	}
+
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		keyconfig.Wipe(key)
+		return fmt.Errorf("key-encryption key %q: %w", id, err)
+	}
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	if k.keys == nil {
+		keyconfig.Wipe(key)
+		return keyconfig.ErrDestroyed
+	}
+	k.keys[id] = aead
+	k.secrets = append(k.secrets, key)
+
+	return nil
+}
+
+func (k *keyring) use(id string) error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if k.keys == nil {
+		return keyconfig.ErrDestroyed
+	}
+	if _, ok := k.keys[id]; !ok {
+		return fmt.Errorf("%w %q", ErrUnknownKey, id)
+	}
+	k.current = id
+
+	return nil
+}
+
+func (k *keyring) wrap(key []byte) ([]byte, error) {
+	k.mutex.RLock()
+	id, aead, destroyed := k.current, k.keys[k.current], k.keys == nil
+	k.mutex.RUnlock()
+
+	if destroyed {
+		return nil, keyconfig.ErrDestroyed
+	}
+	if aead == nil {
+		return nil, fmt.Errorf("%w: no current key-encryption key", ErrUnknownKey)
+	}
+
+	out := make([]byte, 0, 1+len(id)+aead.NonceSize()+len(key)+aead.Overhead())
+	out = append(out, byte(len(id)))
+	out = append(out, id...)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	out = append(out, nonce...)
+
+	return aead.Seal(out, nonce, key, []byte(id)), nil
+}
+
+func (k *keyring) unwrap(wrapped []byte) ([]byte, error) {
+	if len(wrapped) < 1 || len(wrapped) < 1+int(wrapped[0]) {
+		return nil, fmt.Errorf("%w: wrapped key is truncated", ErrUnwrap)
+	}
+	id, rest := string(wrapped[1:1+wrapped[0]]), wrapped[1+wrapped[0]:]
+
+	k.mutex.RLock()
+	aead, destroyed := k.keys[id], k.keys == nil
+	k.mutex.RUnlock()
+
+	if destroyed {
+		return nil, keyconfig.ErrDestroyed
+	}
+	if aead == nil {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, id)
+	}
+	if len(rest) < aead.NonceSize() {
+		return nil, fmt.Errorf("%w: wrapped key is truncated", ErrUnwrap)
+	}
+
+	key, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], []byte(id))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnwrap, err)
+	}
+
+	return key, nil
+}
+
+// Destroy wipes every key-encryption key, after which the keyring refuses to wrap or unwrap anything
+func (k *keyring) Destroy() {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	keyconfig.Wipe(k.secrets...)
+	k.keys, k.secrets = nil, nil
+}
@@ -0,0 +1,74 @@
+// Package kms defines the KeyProvider interface used for envelope encryption, where every value is encrypted under its own data key,
+// and only that data key is encrypted - wrapped - by a key-encryption key the KeyProvider holds.
+// Rotating the key-encryption key then only requires re-wrapping data keys, rather than re-encrypting every value.
+package kms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// KeyProvider wraps and unwraps data keys using key-encryption keys it manages.
+// Implementations backed by cloud KMSes only need to satisfy this interface to be used by gormcrypto.
+type KeyProvider interface {
+	// Name identifies the KeyProvider as a string for exporting configurations
+	Name() string
+	// Config converts a KeyProvider's internal configuration into a map for export
+	Config() map[string]interface{}
+	// WrapKey encrypts a data key with the current key-encryption key
+	WrapKey(ctx context.Context, key []byte) ([]byte, error)
+	// UnwrapKey decrypts a data key with whichever key-encryption key wrapped it
+	UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// The problems KeyProviders can report
+var (
+	ErrUnknownProvider = errors.New("unknown key provider")
+	ErrUnknownKey      = errors.New("unknown key-encryption key")
+	ErrUnwrap          = errors.New("unable to unwrap data key")
+)
+
+// Creator configures a KeyProvider based on a configuration map, reporting any problems with that configuration
+type Creator func(map[string]interface{}) (KeyProvider, error)
+
+// Register adds a KeyProvider to the internal providers map so it can be used in YAML configs
+func Register(name string, creator Creator) {
+	providers[name] = creator
+}
+
+// SupportedProviders returns a list of registered KeyProviders that can be used in YAML configs
+func SupportedProviders() []string {
+	keys := make([]string, 0, len(providers))
+	for k := range providers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// New configures a KeyProvider automatically based on a name and a configuration map
+func New(name string, config map[string]interface{}) (KeyProvider, error) {
+	creator, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownProvider, name)
+	}
+
+	return creator(config)
+}
+
+// Rewrap unwraps a data key and wraps it again with the provider's current key-encryption key
+func Rewrap(ctx context.Context, provider KeyProvider, wrapped []byte) ([]byte, error) {
+	key, err := provider.UnwrapKey(ctx, wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.WrapKey(ctx, key)
+}
+
+// PRIVATE
+
+var providers = make(map[string]Creator)
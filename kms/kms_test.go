@@ -0,0 +1,132 @@
+package kms_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
+	"github.com/danhunsaker/gorm-crypto/kms"
+)
+
+func TestFake(t *testing.T) {
+	ctx := context.Background()
+	provider := kms.NewFake()
+	expected := []byte("DataKeyThatShouldBe32BytesLong!!")
+
+	wrapped, err := provider.WrapKey(ctx, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider.Rotate()
+
+	rewrapped, err := kms.Rewrap(ctx, provider, wrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(rewrapped, wrapped) {
+		t.Error("Expected the data key to be wrapped differently after rotation")
+	}
+
+	for _, w := range [][]byte{wrapped, rewrapped} {
+		actual, err := provider.UnwrapKey(ctx, w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(actual, expected) {
+			t.Errorf("Expected %v; got %v instead", expected, actual)
+		}
+	}
+
+	if _, err := kms.NewFake().UnwrapKey(ctx, wrapped); !errors.Is(err, kms.ErrUnwrap) {
+		t.Errorf("Expected %v; got %v instead", kms.ErrUnwrap, err)
+	}
+	wrapped[len(wrapped)-1] ^= 1
+	if _, err := provider.UnwrapKey(ctx, wrapped); !errors.Is(err, kms.ErrUnwrap) {
+		t.Errorf("Expected %v; got %v instead", kms.ErrUnwrap, err)
+	}
+
+	var _ keyconfig.Destroyer = provider
+	provider.Destroy()
+	if _, err := provider.UnwrapKey(ctx, rewrapped); !errors.Is(err, keyconfig.ErrDestroyed) {
+		t.Errorf("Expected %v; got %v instead", keyconfig.ErrDestroyed, err)
+	}
+}
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	writeKeys(t, path, "old", "old")
+
+	provider, err := kms.New("local", map[string]interface{}{"path": path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provider.Name() != "local" || provider.Config()["path"] != path {
+		t.Errorf("Expected local provider for %v; got %v %v instead", path, provider.Name(), provider.Config())
+	}
+
+	expected := []byte("DataKeyThatShouldBe32BytesLong!!")
+	wrapped, err := provider.WrapKey(ctx, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeKeys(t, path, "new", "old", "new")
+	rotated, err := kms.NewLocal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rewrapped, err := kms.Rewrap(ctx, rotated, wrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeKeys(t, path, "new", "new")
+	retired, err := kms.NewLocal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := retired.UnwrapKey(ctx, wrapped); !errors.Is(err, kms.ErrUnknownKey) {
+		t.Errorf("Expected %v; got %v instead", kms.ErrUnknownKey, err)
+	}
+	actual, err := retired.UnwrapKey(ctx, rewrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("Expected %v; got %v instead", expected, actual)
+	}
+
+	writeKeys(t, path, "missing", "new")
+	if _, err := kms.NewLocal(path); !errors.Is(err, kms.ErrUnknownKey) {
+		t.Errorf("Expected %v; got %v instead", kms.ErrUnknownKey, err)
+	}
+	if _, err := kms.New("bogus", nil); !errors.Is(err, kms.ErrUnknownProvider) {
+		t.Errorf("Expected %v; got %v instead", kms.ErrUnknownProvider, err)
+	}
+
+	retired.Destroy()
+	if _, err := retired.WrapKey(ctx, expected); !errors.Is(err, keyconfig.ErrDestroyed) {
+		t.Errorf("Expected %v; got %v instead", keyconfig.ErrDestroyed, err)
+	}
+	if _, err := retired.UnwrapKey(ctx, rewrapped); !errors.Is(err, keyconfig.ErrDestroyed) {
+		t.Errorf("Expected %v; got %v instead", keyconfig.ErrDestroyed, err)
+	}
+}
+
+func writeKeys(t *testing.T, path, current string, ids ...string) {
+	contents := "current: " + current + "\nkeys:\n"
+	for _, id := range ids {
+		contents += "  " + id + ": " + hex.EncodeToString([]byte(strings.Repeat(id[:1], 32))) + "\n"
+	}
+
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
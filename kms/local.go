@@ -0,0 +1,93 @@
+package kms
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	Register("local", func(m map[string]interface{}) (KeyProvider, error) {
+		path, ok := m["path"].(string)
+		if !ok || path == "" {
+			return nil, &keyconfig.FieldError{Field: "path", Err: keyconfig.ErrMissingKey}
+		}
+
+		return NewLocal(path)
+	})
+}
+
+// Local is a KeyProvider which reads its key-encryption keys from a YAML file on the local filesystem, such as:
+//
+//	current: 2022-06
+//	keys:
+//	  2022-01: <64 hex characters>
+//	  2022-06: <64 hex characters>
+//
+// To rotate, add a new key, make it current, and re-wrap the existing data keys; older keys can be removed once nothing uses them.
+// Destroy wipes the keys read from the file, after which the Local value refuses to wrap or unwrap anything.
+type Local struct {
+	path string
+	*keyring
+}
+
+// NewLocal creates a new Local value, reading its keys from the file at path
+func NewLocal(path string) (*Local, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Current string            `yaml:"current"`
+		Keys    map[string]string `yaml:"keys"`
+	}
+	if err := yaml.Unmarshal(contents, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	ring := newKeyring()
+	for id, encoded := range file.Keys {
+		key, err := hex.DecodeString(encoded)
+		if err != nil {
+			ring.Destroy()
+			return nil, fmt.Errorf("%s: key-encryption key %q: %w: %v", path, id, keyconfig.ErrKeyEncoding, err)
+		}
+		if err := ring.add(id, key); err != nil {
+			ring.Destroy()
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if err := ring.use(file.Current); err != nil {
+		ring.Destroy()
+		return nil, fmt.Errorf("%s: current: %w", path, err)
+	}
+
+	return &Local{path: path, keyring: ring}, nil
+}
+
+// Name identifies the KeyProvider as a string for exporting configurations
+func (*Local) Name() string {
+	return "local"
+}
+
+// Config converts a KeyProvider's internal configuration into a map for export
+func (l *Local) Config() map[string]interface{} {
+	return map[string]interface{}{
+		"path": l.path,
+	}
+}
+
+// WrapKey encrypts a data key with the current key-encryption key
+func (l *Local) WrapKey(_ context.Context, key []byte) ([]byte, error) {
+	return l.wrap(key)
+}
+
+// UnwrapKey decrypts a data key with whichever key-encryption key wrapped it
+func (l *Local) UnwrapKey(_ context.Context, wrapped []byte) ([]byte, error) {
+	return l.unwrap(wrapped)
+}
@@ -0,0 +1,132 @@
+package gormcrypto
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// SetupState describes where a Setup is in its lifecycle, and so what it may still be used for
+type SetupState int
+
+// The lifecycle states a Setup can be in
+const (
+	// StateActive Setups may be used for anything; the most recent one is used to write new values
+	StateActive SetupState = iota
+	// StateDecryptOnly Setups are never used to write new values, but values they wrote can still be read
+	StateDecryptOnly
+	// StateRetired Setups refuse all use; values they wrote can no longer be read
+	StateRetired
+	// StateCompromised Setups can still decrypt the values they wrote, but signatures they made are never considered Valid
+	StateCompromised
+)
+
+// WarningInterval is how long further warnings about a Setup are held back, once WarnSetup has sent one
+const WarningInterval = time.Hour
+
+// Warn formats a warning about a condition which doesn't stop gormcrypto from working, but which needs attention,
+// and passes it to the Config's OnWarning handler, or to the standard logger if it doesn't have one
+func (c Config) Warn(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	if c.OnWarning != nil {
+		c.OnWarning(message)
+		return
+	}
+
+	log.Print("gormcrypto: " + message)
+}
+
+// WarnSetup warns about a Setup, such as when reading values written by a compromised one.
+// Only one warning is sent per Setup each WarningInterval, however often it's used.
+func (c Config) WarnSetup(setup Setup, format string, args ...interface{}) {
+	if warnedSetups.allow(setup.Identifier(), time.Now()) {
+		c.Warn(format, args...)
+	}
+}
+
+// String converts the SetupState to the name used for it in YAML configs
+func (s SetupState) String() string {
+	if name, ok := stateNames[s]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("unknown(%d)", int(s))
+}
+
+// MarshalText converts the SetupState to the name used for it in YAML configs
+func (s SetupState) MarshalText() ([]byte, error) {
+	if _, ok := stateNames[s]; !ok {
+		return nil, fmt.Errorf("unknown setup state %d", int(s))
+	}
+
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText converts a SetupState name from a YAML config back into a SetupState
+func (s *SetupState) UnmarshalText(text []byte) error {
+	for state, name := range stateNames {
+		if name == string(text) {
+			*s = state
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown setup state %q", text)
+}
+
+// CanWrite reports whether the Setup may be used to write new values at the given time
+func (s Setup) CanWrite(at time.Time) bool {
+	if s.State != StateActive {
+		return false
+	}
+	if !s.NotBefore.IsZero() && at.Before(s.NotBefore) {
+		return false
+	}
+	if !s.NotAfter.IsZero() && at.After(s.NotAfter) {
+		return false
+	}
+
+	return true
+}
+
+// CanRead reports whether values written by the Setup may still be read
+func (s Setup) CanRead() bool {
+	return s.State != StateRetired
+}
+
+// PRIVATE
+
+var stateNames = map[SetupState]string{
+	StateActive:      "active",
+	StateDecryptOnly: "decrypt-only",
+	StateRetired:     "retired",
+	StateCompromised: "compromised",
+}
+
+// warnedSetups records when each Setup was last warned about
+var warnedSetups = &warningLimiter{sent: make(map[string]time.Time)}
+
+// warningLimiter holds back repeated warnings, forgetting each one once WarningInterval has passed so it never grows beyond the Setups warned about recently
+type warningLimiter struct {
+	mutex sync.Mutex
+	sent  map[string]time.Time
+}
+
+// allow reports whether a warning about the Setup with the given ID should be sent now, recording it if so
+func (l *warningLimiter) allow(id string, now time.Time) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if sent, ok := l.sent[id]; ok && now.Sub(sent) < WarningInterval {
+		return false
+	}
+	for other, sent := range l.sent {
+		if now.Sub(sent) >= WarningInterval {
+			delete(l.sent, other)
+		}
+	}
+	l.sent[id] = now
+
+	return true
+}
@@ -0,0 +1,102 @@
+package gormcrypto_test
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	gormcrypto "github.com/danhunsaker/gorm-crypto"
+)
+
+func TestSetupStates(t *testing.T) {
+	for _, state := range []gormcrypto.SetupState{gormcrypto.StateDecryptOnly, gormcrypto.StateRetired, gormcrypto.StateCompromised} {
+		t.Run(state.String(), func(t *testing.T) {
+			config := getTestConfig()
+			keys := sortedKeys(config)
+			newest := config.Setups[keys[0]]
+			newest.State = state
+			config.Setups[keys[0]] = newest
+
+			if current := config.CurrentSetup(); !reflect.DeepEqual(current, config.Setups[keys[1]]) {
+				t.Errorf("Expected current == %v; got %v instead", config.Setups[keys[1]], current)
+			}
+		})
+	}
+
+	config := getTestConfig()
+	for key, setup := range config.Setups {
+		setup.State = gormcrypto.StateRetired
+		config.Setups[key] = setup
+	}
+	if _, err := config.ActiveSetup(); err == nil {
+		t.Error("Expected an error with every setup retired; got none")
+	}
+}
+
+func TestSetupWindows(t *testing.T) {
+	config := getTestConfig()
+	keys := sortedKeys(config)
+
+	newest := config.Setups[keys[0]]
+	newest.NotBefore = time.Now().Add(time.Hour)
+	config.Setups[keys[0]] = newest
+	if current := config.CurrentSetup(); !reflect.DeepEqual(current, config.Setups[keys[1]]) {
+		t.Errorf("Expected current == %v; got %v instead", config.Setups[keys[1]], current)
+	}
+
+	newest.NotBefore = time.Time{}
+	newest.NotAfter = time.Now().Add(-1 * time.Minute)
+	config.Setups[keys[0]] = newest
+	if current := config.CurrentSetup(); !reflect.DeepEqual(current, config.Setups[keys[1]]) {
+		t.Errorf("Expected current == %v; got %v instead", config.Setups[keys[1]], current)
+	}
+
+	newest.NotAfter = time.Now().Add(time.Hour)
+	config.Setups[keys[0]] = newest
+	if current := config.CurrentSetup(); !reflect.DeepEqual(current, newest) {
+		t.Errorf("Expected current == %v; got %v instead", newest, current)
+	}
+}
+
+func TestSetupStateExport(t *testing.T) {
+	config := getTestConfig()
+	keys := sortedKeys(config)
+
+	decryptOnly := config.Setups[keys[1]]
+	decryptOnly.State = gormcrypto.StateDecryptOnly
+	decryptOnly.NotBefore = time.Now().Add(-3 * time.Hour).UTC().Truncate(time.Second)
+	decryptOnly.NotAfter = time.Now().UTC().Truncate(time.Second)
+	config.Setups[keys[1]] = decryptOnly
+
+	compromised := config.Setups[keys[2]]
+	compromised.State = gormcrypto.StateCompromised
+	config.Setups[keys[2]] = compromised
+
+	yaml, err := config.ConfigToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported := gormcrypto.ConfigFromBytes(yaml)
+
+	if !reflect.DeepEqual(imported, config) {
+		t.Errorf("Expected %v; got %v instead", config, imported)
+	}
+
+	var state gormcrypto.SetupState
+	if err := state.UnmarshalText([]byte("bogus")); err == nil {
+		t.Error("Expected an error for an unknown state; got none")
+	}
+}
+
+func sortedKeys(config gormcrypto.Config) []time.Time {
+	keys := make([]time.Time, 0, len(config.Setups))
+	for t := range config.Setups {
+		keys = append(keys, t)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].After(keys[j])
+	})
+
+	return keys
+}
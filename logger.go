@@ -0,0 +1,98 @@
+package gormcrypto
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// RedactLogger wraps a GORM logger, so the SQL it logs shows placeholders in place of the bind parameters of encrypted and signed values,
+// and of BlindIndexes, rather than their ciphertext or plaintext. Use it as the Logger in your gorm.Config.
+// It relies on a Plugin to find each Statement's parameters; Statements run without one are logged just as the wrapped logger would log them.
+func RedactLogger(inner logger.Interface) logger.Interface {
+	return redactLogger{Interface: inner}
+}
+
+// PRIVATE
+
+// The placeholders logged in place of redacted parameters
+const (
+	redactedValue = "<encrypted>"
+	redactedIndex = "<blind index>"
+)
+
+// envelopePrefix is the magic prefix of every value the cryptypes package stores in a binary Storage; see cryptypes.IsEnvelope
+var envelopePrefix = []byte("GCE")
+
+var binderType = reflect.TypeOf((*Binder)(nil)).Elem()
+
+type redactLogger struct {
+	logger.Interface
+}
+
+// LogMode returns a copy of the wrapped logger with a new log level, still wrapped
+func (l redactLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return redactLogger{Interface: l.Interface.LogMode(level)}
+}
+
+// Trace logs a Statement's SQL, explaining it with redacted parameters when the Statement can be found in its context
+func (l redactLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	stmt, ok := ctx.Value(statementContextKey{}).(*gorm.Statement)
+	if !ok || stmt.DB == nil {
+		l.Interface.Trace(ctx, begin, fc, err)
+		return
+	}
+
+	l.Interface.Trace(ctx, begin, func() (string, int64) {
+		vars := make([]interface{}, len(stmt.Vars))
+		for i, value := range stmt.Vars {
+			vars[i] = redactVar(value)
+		}
+
+		return stmt.Dialector.Explain(stmt.SQL.String(), vars...), stmt.RowsAffected
+	}, err)
+}
+
+// redactVar replaces a bind parameter with a placeholder if it holds an encrypted or signed value, or a BlindIndex
+func redactVar(value interface{}) interface{} {
+	switch v := value.(type) {
+	case BlindIndex:
+		return redactedIndex
+	case driver.Valuer:
+		if t := reflect.TypeOf(v); t.Implements(binderType) || reflect.PtrTo(t).Implements(binderType) {
+			return redactedValue
+		}
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return value
+		}
+		// Other Valuers, such as those of fields using the cryptypes serializers, only show what they hold once they're converted
+		if converted, err := v.Value(); err == nil && enveloped(converted) {
+			return redactedValue
+		}
+	default:
+		if enveloped(value) {
+			return redactedValue
+		}
+	}
+
+	return value
+}
+
+// enveloped reports whether a bind parameter holds a value stored by the cryptypes package
+func enveloped(value interface{}) bool {
+	switch v := value.(type) {
+	case []byte:
+		return bytes.HasPrefix(v, envelopePrefix)
+	case string:
+		text := []byte(strings.TrimPrefix(v, `"`))
+		return bytes.HasPrefix(text, envelopePrefix) || bytes.HasPrefix(text, []byte(TextPrefix))
+	}
+
+	return false
+}
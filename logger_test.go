@@ -0,0 +1,59 @@
+package gormcrypto_test
+
+import (
+	"bytes"
+	"log"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gormcrypto "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type redactLoggerTestModel struct {
+	ID       uint
+	Name     string
+	SSN      cryptypes.EncryptedString
+	Nickname string `gorm:"serializer:encrypted"`
+}
+
+func TestRedactLogger(t *testing.T) {
+	var out bytes.Buffer
+	inner := logger.New(log.New(&out, "", 0), logger.Config{LogLevel: logger.Info, Colorful: false})
+
+	plugin, err := gormcrypto.NewPlugin(getTestConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "logger.db")), &gorm.Config{Logger: gormcrypto.RedactLogger(inner)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Use(plugin); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&redactLoggerTestModel{}); err != nil {
+		t.Fatal(err)
+	}
+
+	out.Reset()
+	model := redactLoggerTestModel{Name: "Alice", SSN: cryptypes.EncryptedString{Raw: "111-11-1111"}, Nickname: "Al"}
+	if err := db.Create(&model).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&model).Update("ssn", "222-22-2222").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	logged := out.String()
+	if strings.Contains(logged, "<binary>") || strings.Count(logged, `"<encrypted>"`) != 3 {
+		t.Errorf("Expected every encrypted parameter to be redacted; got %v instead", logged)
+	}
+	if !strings.Contains(logged, `"Alice"`) {
+		t.Errorf("Expected other parameters to be logged; got %v instead", logged)
+	}
+}
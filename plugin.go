@@ -0,0 +1,354 @@
+package gormcrypto
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// PluginName is the name gormcrypto registers itself under with GORM
+const PluginName = "gormcrypto"
+
+// Binder is implemented by values which can be bound to a specific Config, such as the types in the cryptypes package.
+// Values which aren't bound to a Config fall back to the global one set by Init.
+type Binder interface {
+	// BindConfig attaches a specific Config to the value, which is then used instead of the global one
+	BindConfig(*Config)
+	// BoundConfig returns the Config attached to the value, or nil if it uses the global one
+	BoundConfig() *Config
+	// ScannedValue returns the DB value most recently passed to Scan, or nil if there wasn't one
+	ScannedValue() interface{}
+}
+
+// StatementBinder is implemented by values which can be bound to the context of the statement reading or writing them, such as the types in the cryptypes package.
+// The context is passed on to any KMS their Setup uses, so KMS calls are cancelled along with the statement, and share its deadline.
+type StatementBinder interface {
+	// BindStatementContext sets the context passed on to any KMS the value's Setup uses; nil unbinds the value from any context
+	BindStatementContext(context.Context)
+	// StatementContext returns the context the value is bound to, or context.Background() if it isn't bound to one
+	StatementContext() context.Context
+}
+
+// Plugin is a GORM plugin which gives a single *gorm.DB its own Config, instead of using the global one.
+// Register it with db.Use(), once per *gorm.DB that should use gormcrypto.
+type Plugin struct {
+	Config Config
+}
+
+// NewPlugin creates a Plugin which will use the provided Config
+func NewPlugin(c Config) (*Plugin, error) {
+	if len(c.Setups) < 1 {
+		return nil, errors.New("database cryptography configuration incomplete")
+	}
+
+	return &Plugin{Config: c}, nil
+}
+
+// Name identifies the Plugin to GORM
+func (*Plugin) Name() string {
+	return PluginName
+}
+
+// Initialize registers the callbacks which bind the Plugin's Config - and their rows, if requested - to values as they are written and read,
+// which keep BlindIndex fields up to date, which encrypt plain values given for encrypted fields in maps and clause.Set,
+// and which add the Config to each Statement's context
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	if len(p.Config.Setups) < 1 {
+		return errors.New("database cryptography configuration incomplete")
+	}
+
+	if err := p.carryConfig(db); err != nil {
+		return err
+	}
+	if err := db.Callback().Create().Before("gorm:create").Register("gormcrypto:bind", p.bindWrite(true)); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("gormcrypto:bind", p.bindWrite(false)); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().After("gorm:query").Before("gorm:preload").Register("gormcrypto:bind", p.bindRead); err != nil {
+		return err
+	}
+	if err := db.Callback().Create().Before("gorm:create").After("gormcrypto:bind").Register("gormcrypto:blind_index", p.blindIndex(true)); err != nil {
+		return err
+	}
+	if err := db.Callback().Create().After("gorm:create").Register("gormcrypto:bind_context", p.rebindCreated); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").After("gormcrypto:bind").Register("gormcrypto:blind_index", p.blindIndex(false)); err != nil {
+		return err
+	}
+	if err := db.Callback().Create().Before("gorm:create").After("gormcrypto:blind_index").Register("gormcrypto:assign", p.assignCreated); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").After("gormcrypto:blind_index").Register("gormcrypto:assign", p.assignUpdated); err != nil {
+		return err
+	}
+
+	atomic.AddInt32(&pluginsInUse, 1)
+
+	return nil
+}
+
+// ConfigFor returns the Config used by a *gorm.DB; that of its Plugin if it has one, or the global Config otherwise
+func ConfigFor(db *gorm.DB) Config {
+	if plugin, ok := db.Config.Plugins[PluginName].(*Plugin); ok {
+		return plugin.Config
+	}
+
+	return GlobalConfig()
+}
+
+// WithConfig returns a copy of ctx which carries a Config, for code which only has a context to find its Config with
+func WithConfig(ctx context.Context, c Config) context.Context {
+	return context.WithValue(ctx, configContextKey{}, c)
+}
+
+// ConfigFromContext returns the Config carried by ctx, if any; a Plugin adds its Config to the context of every Statement it handles
+func ConfigFromContext(ctx context.Context) (Config, bool) {
+	if ctx == nil {
+		return Config{}, false
+	}
+	c, ok := ctx.Value(configContextKey{}).(Config)
+
+	return c, ok
+}
+
+// PluginsInUse reports whether any Plugin has been registered with a *gorm.DB in this process
+func PluginsInUse() bool {
+	return atomic.LoadInt32(&pluginsInUse) > 0
+}
+
+// PRIVATE
+
+var pluginsInUse int32
+
+type configContextKey struct{}
+
+type statementContextKey struct{}
+
+// carryConfig registers callbacks which add the Plugin's Config, the Plugin, and the Statement itself, to the context of every Statement before it runs,
+// so code which only sees the context - such as GORM serializers, loggers, and fields prepared by BindScans - can find them.
+// Only queries bind the rows they read once they're read, so only they mark the values they scan as pending.
+func (p *Plugin) carryConfig(db *gorm.DB) error {
+	carry := func(pending bool) func(*gorm.DB) {
+		return func(db *gorm.DB) {
+			if _, ok := ConfigFromContext(db.Statement.Context); !ok {
+				db.Statement.Context = WithConfig(db.Statement.Context, p.Config)
+			}
+			if plugin, _ := db.Statement.Context.Value(pluginContextKey{}).(*Plugin); plugin != p {
+				db.Statement.Context = context.WithValue(db.Statement.Context, pluginContextKey{}, p)
+			}
+			if marked, ok := db.Statement.Context.Value(pendingContextKey{}).(bool); !ok || marked != pending {
+				db.Statement.Context = context.WithValue(db.Statement.Context, pendingContextKey{}, pending)
+			}
+			if stmt, _ := db.Statement.Context.Value(statementContextKey{}).(*gorm.Statement); stmt != db.Statement {
+				db.Statement.Context = context.WithValue(db.Statement.Context, statementContextKey{}, db.Statement)
+			}
+		}
+	}
+
+	if err := db.Callback().Create().Before("gorm:create").Register("gormcrypto:config", carry(false)); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().Before("gorm:query").Register("gormcrypto:config", carry(true)); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("gormcrypto:config", carry(false)); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().Before("gorm:delete").Register("gormcrypto:config", carry(false)); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("gorm:row").Register("gormcrypto:config", carry(false)); err != nil {
+		return err
+	}
+	if err := db.Callback().Raw().Before("gorm:raw").Register("gormcrypto:config", carry(false)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *Plugin) bindWrite(create bool) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil || db.Statement.Schema == nil {
+			return
+		}
+
+		writes := writesField(db.Statement, create)
+		p.bindAll(db, db.Statement.ReflectValue, false, writes)
+
+		if db.Statement.Dest == nil {
+			return
+		}
+
+		dest := reflect.ValueOf(db.Statement.Dest)
+		if reflect.Indirect(dest).Kind() == reflect.Struct && !reflect.Indirect(dest).CanAddr() {
+			// Values passed to Updates() directly can't be bound, so bind an addressable copy instead
+			copied := reflect.New(dest.Type())
+			copied.Elem().Set(dest)
+			db.Statement.Dest = copied.Interface()
+			dest = copied
+		}
+		p.bindAll(db, dest, false, writes)
+	}
+}
+
+func (p *Plugin) bindRead(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	p.bindAll(db, db.Statement.ReflectValue, true, nil)
+	if db.Error == nil {
+		checkSignatures(db)
+	}
+}
+
+// bindAll binds every Binder field of the values given, skipping fields which won't be written, if writes is set.
+// Binding a field makes it non-zero, so binding fields GORM would otherwise skip would have them written after all.
+func (p *Plugin) bindAll(db *gorm.DB, value reflect.Value, rescan bool, writes func(*schema.Field, bool) bool) {
+	eachModel(db, value, func(model reflect.Value) {
+		for _, field := range db.Statement.Schema.Fields {
+			fieldValue := field.ReflectValueOf(db.Statement.Context, model)
+			if !fieldValue.CanAddr() {
+				continue
+			}
+			if writes != nil && !writes(field, fieldValue.IsZero()) {
+				continue
+			}
+
+			if binder, ok := fieldValue.Addr().Interface().(Binder); ok {
+				bindStatement(db.Statement.Context, binder)
+				db.AddError(bindStorage(field, binder))
+				db.AddError(bindSignaturePolicy(field, binder))
+				requireContext(field, binder)
+				rebound := bindContext(db, field, model, binder, rescan)
+				db.AddError(p.bind(binder, rescan, rebound))
+				if rescan {
+					// Values read are only decrypted as they're scanned, so they don't need to keep the statement alive afterwards
+					bindStatement(nil, binder)
+				}
+			}
+		}
+	})
+}
+
+// bind binds a value to the Plugin's Config, scanning it again if rescan is set and it wasn't read with that Config,
+// or if reading it held back an error which binding it to its row may have fixed
+func (p *Plugin) bind(binder Binder, rescan, rebound bool) error {
+	held := false
+	if pending, ok := binder.(PendingBinder); ok {
+		held = pending.HeldBack()
+		pending.BindPending(false)
+	}
+
+	bound := binder.BoundConfig() == &p.Config
+	if bound && !rebound && !held {
+		return nil
+	}
+
+	source := binder.ScannedValue()
+	binder.BindConfig(&p.Config)
+
+	if scanner, ok := binder.(sql.Scanner); ok && rescan && source != nil && (!bound || held) {
+		return scanner.Scan(source)
+	}
+
+	return nil
+}
+
+// bindStatement binds a value to the context of the statement reading or writing it, or unbinds it from any, given nil
+func bindStatement(ctx context.Context, value interface{}) {
+	if binder, ok := value.(StatementBinder); ok {
+		binder.BindStatementContext(ctx)
+	}
+}
+
+// blindIndex creates a callback which computes the BlindIndex fields of every value being written, using the active Setup.
+// Indexes are only computed for fields GORM will actually write, and are added to any explicit selection of those fields.
+func (p *Plugin) blindIndex(create bool) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil || db.Statement.Schema == nil || db.Statement.Dest == nil {
+			return
+		}
+
+		specs, err := blindIndexSpecs(db.Statement.Schema)
+		if err != nil {
+			db.AddError(err)
+			return
+		}
+		if len(specs) < 1 {
+			return
+		}
+
+		writes := writesField(db.Statement, create)
+		selected, restricted := db.Statement.SelectAndOmitColumns(create, !create)
+		for _, spec := range specs {
+			if restricted && selected[spec.source.DBName] && !selected[spec.field.DBName] {
+				db.Statement.Selects = append(db.Statement.Selects, spec.field.DBName)
+			}
+		}
+
+		eachModel(db, reflect.ValueOf(db.Statement.Dest), func(model reflect.Value) {
+			if !model.CanAddr() {
+				return
+			}
+
+			for _, spec := range specs {
+				source, zero := spec.source.ValueOf(db.Statement.Context, model)
+				if !writes(spec.source, zero) {
+					continue
+				}
+
+				setup, err := p.Config.ActiveSetup()
+				if err != nil {
+					db.AddError(err)
+					return
+				}
+				index, err := spec.index(setup, source)
+				if err != nil {
+					db.AddError(err)
+					continue
+				}
+				db.AddError(spec.field.Set(db.Statement.Context, model, index))
+			}
+		})
+	}
+}
+
+// writesField reports whether GORM will write a field during the Statement, following the same rules it does:
+// selected fields are always written, omitted fields never are, and updates skip zero fields unless they're selected
+func writesField(stmt *gorm.Statement, create bool) func(*schema.Field, bool) bool {
+	selected, restricted := stmt.SelectAndOmitColumns(create, !create)
+
+	return func(field *schema.Field, zero bool) bool {
+		if write, ok := selected[field.DBName]; ok {
+			return write
+		}
+
+		return !restricted && (create || !zero)
+	}
+}
+
+// eachModel calls fn for every value of the Statement's model type found in value, which may be a struct, a slice, or a pointer to either
+func eachModel(db *gorm.DB, value reflect.Value, fn func(reflect.Value)) {
+	value = reflect.Indirect(value)
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			eachModel(db, value.Index(i), fn)
+		}
+	case reflect.Struct:
+		if value.Type() == db.Statement.Schema.ModelType {
+			fn(value)
+		}
+	}
+}
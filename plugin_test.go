@@ -0,0 +1,263 @@
+package gormcrypto_test
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	gormcrypto "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+	"github.com/danhunsaker/gorm-crypto/encoding"
+	"github.com/danhunsaker/gorm-crypto/encryption"
+	"github.com/danhunsaker/gorm-crypto/kms"
+	"github.com/danhunsaker/gorm-crypto/serializing"
+	"github.com/danhunsaker/gorm-crypto/signing"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type pluginTestModel struct {
+	ID     uint
+	Secret cryptypes.EncryptedString
+	Signed cryptypes.SignedEncryptedString
+}
+
+func TestPluginSeparateConfigs(t *testing.T) {
+	piiDB := openTestDB(t, "", newTestConfig(newTestSetup(t, "PIIEncryptionKeyThatIs32BytesLng", "PIISigningKeyThatIs32BytesLong!!")), &pluginTestModel{})
+	statsDB := openTestDB(t, "", newTestConfig(newTestSetup(t, "StatsEncryptionKeyThatIs32Bytes!", "StatsSigningKeyThatIs32BytesLong")), &pluginTestModel{})
+
+	expected := pluginTestModel{
+		Secret: cryptypes.EncryptedString{Raw: "Test"},
+		Signed: cryptypes.SignedEncryptedString{Raw: "Test"},
+	}
+	if err := piiDB.Create(&expected).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var actual pluginTestModel
+	if err := piiDB.First(&actual, expected.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if actual.Secret.Raw != expected.Secret.Raw {
+		t.Errorf("Expected %v; got %v instead", expected.Secret.Raw, actual.Secret.Raw)
+	}
+	if actual.Signed.Raw != expected.Signed.Raw || !actual.Signed.Valid {
+		t.Errorf("Expected valid %v; got %v (valid = %v) instead", expected.Signed.Raw, actual.Signed.Raw, actual.Signed.Valid)
+	}
+	if actual.Secret.BoundConfig() != &piiDB.Config.Plugins[gormcrypto.PluginName].(*gormcrypto.Plugin).Config {
+		t.Error("Expected value to be bound to the plugin's Config")
+	}
+
+	var raw []byte
+	if err := piiDB.Table("plugin_test_models").Select("secret").Where("id = ?", expected.ID).Row().Scan(&raw); err != nil {
+		t.Fatal(err)
+	}
+	if err := statsDB.Exec("INSERT INTO plugin_test_models (id, secret) VALUES (?, ?)", expected.ID, raw).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := statsDB.First(&pluginTestModel{}, expected.ID).Error; err == nil {
+		t.Error("Expected an error decrypting with the wrong Config; got none")
+	}
+}
+
+type pluginNullTestModel struct {
+	ID     uint
+	Secret cryptypes.NullEncryptedString
+	Signed cryptypes.NullSignedEncryptedString
+}
+
+func TestPluginScansNulls(t *testing.T) {
+	db := openTestDB(t, "", newTestConfig(newTestSetup(t, "NullEncryptionKeyThatIs32BytesLg", "NullSigningKeyThatIs32BytesLong!")), &pluginTestModel{})
+	if err := db.AutoMigrate(&pluginNullTestModel{}); err != nil {
+		t.Fatal(err)
+	}
+
+	null := pluginNullTestModel{Secret: cryptypes.NullEncryptedString{Empty: true}, Signed: cryptypes.NullSignedEncryptedString{Empty: true}}
+	set := pluginNullTestModel{Secret: cryptypes.NullEncryptedString{Raw: "Test"}, Signed: cryptypes.NullSignedEncryptedString{Raw: "Test"}}
+	if err := db.Create(&null).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&set).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var actual pluginNullTestModel
+	if err := db.First(&actual, null.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if !actual.Secret.Empty || !actual.Signed.Empty || !actual.Signed.Valid {
+		t.Errorf("Expected NULLs to be read as empty; got %+v instead", actual)
+	}
+
+	var again pluginNullTestModel
+	if err := db.First(&again, set.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if again.Secret.Empty || again.Secret.Raw != "Test" || again.Signed.Empty || again.Signed.Raw != "Test" {
+		t.Errorf("Expected values to be read as set; got %+v instead", again)
+	}
+
+	var all []pluginNullTestModel
+	if err := db.Order("id").Find(&all).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || !all[0].Secret.Empty || all[1].Secret.Empty {
+		t.Errorf("Expected only the first row to be empty; got %+v instead", all)
+	}
+
+	var selected pluginNullTestModel
+	if err := db.Select("id").First(&selected, null.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if selected.Secret.Empty || selected.Signed.Empty {
+		t.Errorf("Expected unselected fields to be left alone; got %+v instead", selected)
+	}
+}
+
+type pluginHookTestModel struct {
+	ID     uint
+	Secret cryptypes.EncryptedString
+	found  string
+}
+
+func (pluginHookTestModel) TableName() string {
+	return "plugin_test_models"
+}
+
+func (m *pluginHookTestModel) AfterFind(*gorm.DB) error {
+	m.found = m.Secret.Raw
+	return nil
+}
+
+func TestPluginStatements(t *testing.T) {
+	db := openTestDB(t, "", newTestConfig(newTestSetup(t, "StmtEncryptionKeyThatIs32Bytes!!", "StmtSigningKeyThatIs32BytesLong!")), &pluginTestModel{})
+
+	expected := pluginTestModel{Secret: cryptypes.EncryptedString{Raw: "Test"}, Signed: cryptypes.SignedEncryptedString{Raw: "Test"}}
+	if err := db.Create(&expected).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var hooked pluginHookTestModel
+	if err := db.First(&hooked, expected.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if hooked.found != "Test" {
+		t.Errorf("Expected AfterFind to see %v; got %v instead", "Test", hooked.found)
+	}
+
+	var raw pluginTestModel
+	if err := db.Raw("SELECT * FROM plugin_test_models WHERE id = ?", expected.ID).Scan(&raw).Error; err != nil {
+		t.Fatal(err)
+	}
+	if raw.Secret.Raw != "Test" || raw.Signed.Raw != "Test" || !raw.Signed.Valid {
+		t.Errorf("Expected raw queries to use the Plugin's Config; got %+v instead", raw)
+	}
+
+	var secret cryptypes.EncryptedString
+	if err := db.Table("plugin_test_models").Select("secret").Where("id = ?", expected.ID).Row().Scan(&secret); err == nil {
+		t.Errorf("Expected an error scanning a value with no Config bound; got %v instead", secret.Raw)
+	}
+}
+
+func TestPluginGlobalErrors(t *testing.T) {
+	openTestDB(t, "", newTestConfig(newTestSetup(t, "UnusedEncryptionKeyThatIs32Bytes", "UnusedSigningKeyThatIs32BytesLng")), &pluginTestModel{})
+	if err := gormcrypto.Init(getTestConfig()); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "global.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&pluginTestModel{}); err != nil {
+		t.Fatal(err)
+	}
+	row := pluginTestModel{Secret: cryptypes.EncryptedString{Raw: "Test"}, Signed: cryptypes.SignedEncryptedString{Raw: "Test"}}
+	if err := db.Create(&row).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var stored []byte
+	if err := db.Table("plugin_test_models").Select("secret").Where("id = ?", row.ID).Row().Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+	var envelope cryptypes.Envelope
+	if err := envelope.UnmarshalBinary(stored); err != nil {
+		t.Fatal(err)
+	}
+	envelope.SetupID = "missing"
+	tampered, _ := envelope.MarshalBinary()
+	if err := db.Exec("UPDATE plugin_test_models SET secret = ? WHERE id = ?", tampered, row.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.First(&pluginTestModel{}, row.ID).Error; err == nil {
+		t.Error("Expected an error reading a value no Setup matches, despite a Plugin being used elsewhere; got none")
+	}
+}
+
+func TestPluginIncomplete(t *testing.T) {
+	if _, err := gormcrypto.NewPlugin(gormcrypto.Config{}); err == nil {
+		t.Error("Expected an error for an empty Config; got none")
+	}
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Use(&gormcrypto.Plugin{}); err == nil {
+		t.Error("Expected an error for an empty Config; got none")
+	}
+}
+
+type pluginTestContextKey struct{}
+
+// contextKeyProvider records the value each context it's given carries for pluginTestContextKey
+type contextKeyProvider struct {
+	*kms.Fake
+	seen []interface{}
+}
+
+func (p *contextKeyProvider) WrapKey(ctx context.Context, key []byte) ([]byte, error) {
+	p.seen = append(p.seen, ctx.Value(pluginTestContextKey{}))
+	return p.Fake.WrapKey(ctx, key)
+}
+
+func (p *contextKeyProvider) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	p.seen = append(p.seen, ctx.Value(pluginTestContextKey{}))
+	return p.Fake.UnwrapKey(ctx, wrapped)
+}
+
+func TestPluginStatementContext(t *testing.T) {
+	provider := &contextKeyProvider{Fake: kms.NewFake()}
+	dek := encryption.NewDEK(provider)
+	dek.SetCacheTTL(0)
+
+	db := openTestDB(t, "", newTestConfig(gormcrypto.Setup{
+		Encoder:    encoding.Base64{},
+		Serializer: serializing.JSON{},
+		Encrypter:  dek,
+		Signer:     signing.NewED25519FromSeed("SigningKeyThatShouldBe32BytesToo"),
+	}), &pluginTestModel{})
+
+	expected := pluginTestModel{
+		Secret: cryptypes.EncryptedString{Raw: "Test"},
+		Signed: cryptypes.SignedEncryptedString{Raw: "Test"},
+	}
+	if err := db.WithContext(context.WithValue(context.Background(), pluginTestContextKey{}, "create")).Create(&expected).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var actual pluginTestModel
+	if err := db.WithContext(context.WithValue(context.Background(), pluginTestContextKey{}, "query")).First(&actual, expected.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if actual.Secret.Raw != expected.Secret.Raw || actual.Signed.Raw != expected.Signed.Raw {
+		t.Errorf("Expected %v; got %v instead", expected, actual)
+	}
+
+	if seen := []interface{}{"create", "create", "query", "query"}; !reflect.DeepEqual(provider.seen, seen) {
+		t.Errorf("Expected KMS calls with contexts from %v; got %v instead", seen, provider.seen)
+	}
+}
@@ -0,0 +1,84 @@
+package rotation
+
+import (
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// Checkpointer stores how far a Rotator has gotten through each model, so an interrupted run can pick up where it left off.
+// Checkpoints are opaque strings; an empty string means the model should be processed from the start.
+type Checkpointer interface {
+	// Load returns the saved checkpoint for a model, if any
+	Load(model string) (string, error)
+	// Save records a checkpoint for a model
+	Save(model, checkpoint string) error
+}
+
+// MemoryCheckpoints keeps checkpoints in memory, so runs can only resume within the same process
+type MemoryCheckpoints struct {
+	mutex       sync.Mutex
+	checkpoints map[string]string
+}
+
+// NewMemoryCheckpoints creates a new MemoryCheckpoints value
+func NewMemoryCheckpoints() *MemoryCheckpoints {
+	return &MemoryCheckpoints{checkpoints: make(map[string]string)}
+}
+
+// Load returns the saved checkpoint for a model, if any
+func (m *MemoryCheckpoints) Load(model string) (string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.checkpoints[model], nil
+}
+
+// Save records a checkpoint for a model
+func (m *MemoryCheckpoints) Save(model, checkpoint string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.checkpoints[model] = checkpoint
+
+	return nil
+}
+
+// Checkpoint is the row stored by TableCheckpoints for each model
+type Checkpoint struct {
+	Model      string `gorm:"primaryKey;size:191"`
+	Checkpoint string
+}
+
+// TableName keeps the checkpoints table from clashing with application tables
+func (Checkpoint) TableName() string {
+	return "gormcrypto_rotation_checkpoints"
+}
+
+// TableCheckpoints keeps checkpoints in a DB table, so runs can resume after a restart
+type TableCheckpoints struct {
+	db *gorm.DB
+}
+
+// NewTableCheckpoints creates a new TableCheckpoints value, creating its table if needed
+func NewTableCheckpoints(db *gorm.DB) (*TableCheckpoints, error) {
+	if err := db.AutoMigrate(&Checkpoint{}); err != nil {
+		return nil, err
+	}
+
+	return &TableCheckpoints{db: db}, nil
+}
+
+// Load returns the saved checkpoint for a model, if any
+func (t *TableCheckpoints) Load(model string) (string, error) {
+	var saved Checkpoint
+
+	err := t.db.Where(&Checkpoint{Model: model}).Limit(1).Find(&saved).Error
+
+	return saved.Checkpoint, err
+}
+
+// Save records a checkpoint for a model
+func (t *TableCheckpoints) Save(model, checkpoint string) error {
+	return t.db.Save(&Checkpoint{Model: model, Checkpoint: checkpoint}).Error
+}
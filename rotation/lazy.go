@@ -0,0 +1,272 @@
+package rotation
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sync"
+
+	gc "github.com/danhunsaker/gorm-crypto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// LazyPluginName is the name a Lazy rotator registers itself under with GORM
+const LazyPluginName = "gormcrypto:lazy_rotation"
+
+// DefaultQueueSize is the number of rows a Lazy rotator running in the background can hold waiting to be rewritten, unless told otherwise
+const DefaultQueueSize = 1000
+
+// LazyMode chooses when a Lazy rotator rewrites the stale rows it finds
+type LazyMode int
+
+// The LazyModes a Lazy rotator can run in
+const (
+	// RotateImmediately rewrites stale rows as soon as the query which read them finishes, as an AfterFind hook would
+	RotateImmediately LazyMode = iota
+	// RotateInBackground queues stale rows for a background worker to rewrite, so queries aren't slowed down by the rewrites
+	RotateInBackground
+)
+
+// Lazy is a GORM plugin which rotates rows as they're read, so values written by older Setups age out of frequently used data on their own,
+// alongside - or instead of - running a Rotator over whole tables.
+// Rows holding any value written by a non-current Setup are rewritten under the current Setup, exactly as a Rotator would rewrite them.
+// Rewrites only go ahead if the rewritten columns still hold the values which were read, so changes made in the meantime are never overwritten.
+// Register it with db.Use(); it runs after the gormcrypto Plugin, if there is one, so rows are inspected with the Plugin's Config.
+type Lazy struct {
+	// Mode chooses whether stale rows are rewritten immediately or in the background
+	Mode LazyMode
+	// QueueSize is the number of rows which can wait to be rewritten in the background; rows found while the queue is full are left for the next read
+	QueueSize int
+	// OnRotate, if set, is called after every row which is rewritten
+	OnRotate func(table string, primaryKey interface{})
+	// OnError, if set, is called with every error rewriting a row; otherwise errors are passed to the Warn method of the DB's Config.
+	// Errors never fail the query which read the row.
+	OnError func(error)
+
+	db      *gorm.DB
+	queue   chan lazyRewrite
+	closed  bool
+	mutex   sync.RWMutex
+	workers sync.WaitGroup
+}
+
+// NewLazy creates a Lazy rotator which rewrites stale rows in the given LazyMode
+func NewLazy(mode LazyMode) *Lazy {
+	return &Lazy{Mode: mode, QueueSize: DefaultQueueSize}
+}
+
+// Name identifies the Lazy rotator to GORM
+func (*Lazy) Name() string {
+	return LazyPluginName
+}
+
+// Initialize registers the callback which finds stale rows as they're read, and starts the background worker if there is one
+func (l *Lazy) Initialize(db *gorm.DB) error {
+	l.db = db
+	if l.Mode == RotateInBackground {
+		size := l.QueueSize
+		if size < 1 {
+			size = DefaultQueueSize
+		}
+		l.queue = make(chan lazyRewrite, size)
+		l.workers.Add(1)
+		go l.work()
+	}
+
+	return db.Callback().Query().After("gormcrypto:bind").Register(LazyPluginName, l.found)
+}
+
+// Close stops the background worker once every row already queued has been rewritten.
+// Rows found afterwards are left for the next read; Close does nothing for a Lazy rotator running in RotateImmediately mode.
+func (l *Lazy) Close() {
+	l.mutex.Lock()
+	if l.queue != nil && !l.closed {
+		l.closed = true
+		close(l.queue)
+	}
+	l.mutex.Unlock()
+
+	l.workers.Wait()
+}
+
+// PRIVATE
+
+// lazyRewrite is a stale row waiting to be rewritten, along with the values it was read with
+type lazyRewrite struct {
+	table    string
+	row      reflect.Value
+	primary  *schema.Field
+	stale    []string
+	expected map[string]interface{}
+}
+
+// found is the query callback which finds stale rows among those just read, and rewrites or queues them
+func (l *Lazy) found(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil || db.RowsAffected < 1 || len(db.Statement.Schema.PrimaryFields) != 1 {
+		return
+	}
+
+	fields := cryptFields(db.Statement.Schema)
+	if len(fields) < 1 {
+		return
+	}
+
+	setup, err := gc.ConfigFor(db).ActiveSetup()
+	if err != nil {
+		return
+	}
+	current := setup.Identifier()
+
+	eachRow(db.Statement.ReflectValue, db.Statement.Schema.ModelType, func(row reflect.Value) {
+		stale, invalid := inspect(db.Statement.Context, row, fields, current)
+		if invalid || len(stale) < 1 {
+			return
+		}
+
+		rewrite, err := newLazyRewrite(db, row, fields, stale, l.queue != nil)
+		if err != nil {
+			l.report(err)
+			return
+		}
+
+		if l.queue == nil {
+			l.rewrite(db.Session(&gorm.Session{NewDB: true}), rewrite)
+			return
+		}
+		l.enqueue(rewrite)
+	})
+}
+
+// enqueue hands a stale row to the background worker, unless its queue is full or closed
+func (l *Lazy) enqueue(rewrite lazyRewrite) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	if l.closed {
+		return
+	}
+	select {
+	case l.queue <- rewrite:
+	default:
+	}
+}
+
+// newLazyRewrite records the values a stale row was read with, exactly as their columns held them.
+// Rows queued for the background worker are copied, and their gormcrypto values scanned again from those column values, rather than sharing
+// slices, maps, and documents with the row the caller was given, so changes made to it after it's read are never written.
+func newLazyRewrite(db *gorm.DB, row reflect.Value, fields []*schema.Field, stale []string, queued bool) (lazyRewrite, error) {
+	rewrite := lazyRewrite{
+		table:    db.Statement.Table,
+		row:      reflect.New(row.Type()),
+		primary:  db.Statement.Schema.PrimaryFields[0],
+		stale:    stale,
+		expected: storedValues(db, row, fields),
+	}
+	rewrite.row.Elem().Set(row)
+	if !queued {
+		return rewrite, nil
+	}
+
+	for _, field := range fields {
+		if err := rescan(db.Statement.Context, field, row, rewrite.row.Elem()); err != nil {
+			return rewrite, fmt.Errorf("%s: copying row: %w", rewrite.table, err)
+		}
+	}
+
+	return rewrite, nil
+}
+
+// rescan replaces a field of a copied row with a fresh value, scanned from the column value the original was read from,
+// and bound to the same Config, row context, Storage, and SignaturePolicy
+func rescan(ctx context.Context, field *schema.Field, original, copied reflect.Value) error {
+	source := field.ReflectValueOf(ctx, original).Addr().Interface().(gc.Binder)
+	stored := source.ScannedValue()
+	if stored == nil {
+		return nil
+	}
+
+	fresh := reflect.New(field.FieldType)
+	binder := fresh.Interface().(gc.Binder)
+	binder.BindConfig(source.BoundConfig())
+	if from, ok := source.(gc.ContextBinder); ok {
+		binder.(gc.ContextBinder).BindContext(from.BoundContext())
+	}
+	if from, ok := source.(gc.StorageBinder); ok {
+		if storage, bound := from.BoundStorage(); bound {
+			binder.(gc.StorageBinder).BindStorage(storage)
+		}
+	}
+	if from, ok := source.(gc.SignatureBinder); ok {
+		if policy, bound := from.BoundSignaturePolicy(); bound {
+			binder.(gc.SignatureBinder).BindSignaturePolicy(policy)
+		}
+	}
+	statement, bindable := binder.(gc.StatementBinder)
+	if bindable {
+		statement.BindStatementContext(ctx)
+	}
+
+	scanner, ok := binder.(sql.Scanner)
+	if !ok {
+		return nil
+	}
+	err := scanner.Scan(stored)
+	if bindable {
+		statement.BindStatementContext(nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	return field.Set(ctx, copied, fresh.Elem().Interface())
+}
+
+// rewrite writes a stale row's values again under the current Setup, unless the row has changed since it was read
+func (l *Lazy) rewrite(db *gorm.DB, rewrite lazyRewrite) {
+	key := rewrite.primary.ReflectValueOf(db.Statement.Context, rewrite.row.Elem()).Interface()
+
+	rewritten, err := rewriteRow(db, rewrite.table, rewrite.row.Interface(), rewrite.stale, rewrite.expected)
+	if err != nil {
+		l.report(fmt.Errorf("%s: rotating row %v: %w", rewrite.table, key, err))
+		return
+	}
+	if rewritten && l.OnRotate != nil {
+		l.OnRotate(rewrite.table, key)
+	}
+}
+
+// work rewrites queued rows until the queue is closed
+func (l *Lazy) work() {
+	defer l.workers.Done()
+
+	for rewrite := range l.queue {
+		l.rewrite(l.db.Session(&gorm.Session{NewDB: true, Context: context.Background()}), rewrite)
+	}
+}
+
+func (l *Lazy) report(err error) {
+	if l.OnError != nil {
+		l.OnError(err)
+		return
+	}
+
+	gc.ConfigFor(l.db).Warn("%v", err)
+}
+
+// eachRow calls fn for every addressable value of the model type found in value, which may be a struct, a slice, or a pointer to either
+func eachRow(value reflect.Value, modelType reflect.Type, fn func(reflect.Value)) {
+	value = reflect.Indirect(value)
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			eachRow(value.Index(i), modelType, fn)
+		}
+	case reflect.Struct:
+		if value.Type() == modelType && value.CanAddr() {
+			fn(value)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package rotation_test
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	gc "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+	"github.com/danhunsaker/gorm-crypto/rotation"
+	"gorm.io/gorm"
+)
+
+func TestLazyImmediate(t *testing.T) {
+	oldDB, newDB, newSetup := openLazyDBs(t, "immediate.db", 3)
+
+	var rotated []interface{}
+	lazy := rotation.NewLazy(rotation.RotateImmediately)
+	lazy.OnRotate = func(table string, key interface{}) { rotated = append(rotated, key) }
+	lazy.OnError = func(err error) { t.Error(err) }
+	if err := newDB.Use(lazy); err != nil {
+		t.Fatal(err)
+	}
+
+	var row rotationTestModel
+	if err := newDB.First(&row, 2).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(rotated) != 1 || rotated[0] != uint(2) {
+		t.Errorf("Expected row 2 to be rotated; got %v instead", rotated)
+	}
+	if row.Secret.Raw != "Test" {
+		t.Errorf("Expected %v; got %v instead", "Test", row.Secret.Raw)
+	}
+
+	if err := newDB.Where("id = ?", 2).Find(&[]rotationTestModel{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(rotated) != 1 {
+		t.Errorf("Expected a current row not to be rotated again; got %v instead", rotated)
+	}
+
+	var unrotated []rotationTestModel
+	if err := oldDB.Where("id <> ?", 2).Find(&unrotated).Error; err != nil {
+		t.Errorf("Expected rows which weren't read to be left alone; got %v", err)
+	}
+	if err := oldDB.First(&rotationTestModel{}, 2).Error; err == nil {
+		t.Error("Expected the rotated row to be unreadable with only the old Setup; got no error")
+	}
+
+	if meta, err := row.Secret.Metadata(); err != nil || meta.IsCurrent() {
+		t.Errorf("Expected the value read to keep the metadata it was read with; got %+v (%v) instead", meta, err)
+	}
+	if err := newDB.First(&row, 2).Error; err != nil {
+		t.Fatal(err)
+	}
+	if meta, err := row.Secret.Metadata(); err != nil || meta.SetupID != newSetup.Identifier() {
+		t.Errorf("Expected the row to be written by %v; got %+v (%v) instead", newSetup.Identifier(), meta, err)
+	}
+}
+
+func TestLazyBackground(t *testing.T) {
+	_, newDB, newSetup := openLazyDBs(t, "background.db", 5)
+
+	var mutex sync.Mutex
+	rotated := 0
+	lazy := rotation.NewLazy(rotation.RotateInBackground)
+	lazy.OnRotate = func(string, interface{}) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		rotated++
+	}
+	lazy.OnError = func(err error) { t.Error(err) }
+	if err := newDB.Use(lazy); err != nil {
+		t.Fatal(err)
+	}
+
+	var rows []rotationTestModel
+	if err := newDB.Find(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	for i := range rows {
+		rows[i].Secret.Raw = "Changed after reading"
+	}
+	lazy.Close()
+
+	if rotated != 5 {
+		t.Errorf("Expected 5 rows to be rotated; got %d instead", rotated)
+	}
+	assertSetup(t, newDB, newSetup.Identifier())
+
+	if err := newDB.Find(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range rows {
+		if row.Secret.Raw != "Test" {
+			t.Errorf("Expected rows to be rotated as they were read; got %v instead", row.Secret.Raw)
+		}
+	}
+}
+
+func TestLazyConflict(t *testing.T) {
+	oldDB, newDB, _ := openLazyDBs(t, "conflict.db", 1)
+
+	rotated := 0
+	lazy := rotation.NewLazy(rotation.RotateImmediately)
+	lazy.OnRotate = func(string, interface{}) { rotated++ }
+	if err := newDB.Use(lazy); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate another writer changing the row between it being read and it being rotated
+	err := newDB.Callback().Query().After("gormcrypto:bind").Before(rotation.LazyPluginName).Register("test:concurrent", func(db *gorm.DB) {
+		if db.Statement.Table == "rotation_test_models" {
+			db.AddError(oldDB.Model(&rotationTestModel{ID: 1}).Updates(&rotationTestModel{Secret: cryptypes.EncryptedString{Raw: "Concurrent"}}).Error)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := newDB.First(&rotationTestModel{}, 1).Error; err != nil {
+		t.Fatal(err)
+	}
+	if rotated != 0 {
+		t.Errorf("Expected the changed row not to be rotated; got %d rotations instead", rotated)
+	}
+
+	var row rotationTestModel
+	if err := oldDB.First(&row, 1).Error; err != nil || row.Secret.Raw != "Concurrent" {
+		t.Errorf("Expected the concurrent change to survive; got %v (%v) instead", row.Secret.Raw, err)
+	}
+}
+
+func TestLazyStorage(t *testing.T) {
+	oldDB, newDB := openStorageDBs(t, "lazy-storage.db", 2)
+
+	rotated := 0
+	lazy := rotation.NewLazy(rotation.RotateImmediately)
+	lazy.OnRotate = func(string, interface{}) { rotated++ }
+	lazy.OnError = func(err error) { t.Error(err) }
+	if err := newDB.Use(lazy); err != nil {
+		t.Fatal(err)
+	}
+
+	var rows []rotationStorageTestModel
+	if err := newDB.Find(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	if rotated != 2 {
+		t.Errorf("Expected 2 rows to be rotated; got %d instead", rotated)
+	}
+	if err := oldDB.Find(&rows).Error; err == nil {
+		t.Error("Expected rotated rows to be unreadable with only the old Setup; got no error")
+	}
+}
+
+type rotationBytesTestModel struct {
+	ID   uint
+	Data cryptypes.EncryptedByteSlice
+}
+
+func TestLazyBackgroundCopies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "copies.db")
+	oldSetup, newSetup := getSetups(t)
+	oldDB := openDB(t, path, map[time.Time]gc.Setup{time.Now().Add(-1 * time.Hour): oldSetup})
+	newDB := openDB(t, path, map[time.Time]gc.Setup{time.Now().Add(-1 * time.Hour): oldSetup, time.Now(): newSetup})
+	if err := oldDB.AutoMigrate(&rotationBytesTestModel{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := oldDB.Create(&rotationBytesTestModel{Data: cryptypes.EncryptedByteSlice{Raw: []byte("Test")}}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// Hold the background worker back until the row has been changed in memory
+	release := make(chan struct{})
+	if err := newDB.Callback().Update().Before("gorm:update").Register("test:release", func(*gorm.DB) { <-release }); err != nil {
+		t.Fatal(err)
+	}
+
+	rotated := 0
+	lazy := rotation.NewLazy(rotation.RotateInBackground)
+	lazy.OnRotate = func(string, interface{}) { rotated++ }
+	lazy.OnError = func(err error) { t.Error(err) }
+	if err := newDB.Use(lazy); err != nil {
+		t.Fatal(err)
+	}
+
+	var rows []rotationBytesTestModel
+	if err := newDB.Find(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	copy(rows[0].Data.Raw, "Edit")
+	close(release)
+	lazy.Close()
+
+	if rotated != 1 {
+		t.Errorf("Expected 1 row to be rotated; got %d instead", rotated)
+	}
+	var row rotationBytesTestModel
+	if err := newDB.First(&row, rows[0].ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if string(row.Data.Raw) != "Test" {
+		t.Errorf("Expected the row to be rotated as it was read; got %q instead", row.Data.Raw)
+	}
+}
+
+// openLazyDBs creates rows with an old Setup, and returns DBs which only know the old Setup, and which use a newer one, along with that newer Setup
+func openLazyDBs(t *testing.T, name string, rows int) (*gorm.DB, *gorm.DB, gc.Setup) {
+	path := filepath.Join(t.TempDir(), name)
+	oldSetup, newSetup := getSetups(t)
+	oldDB := openDB(t, path, map[time.Time]gc.Setup{time.Now().Add(-1 * time.Hour): oldSetup})
+	newDB := openDB(t, path, map[time.Time]gc.Setup{time.Now().Add(-1 * time.Hour): oldSetup, time.Now(): newSetup})
+
+	for i := 0; i < rows; i++ {
+		if err := oldDB.Create(&rotationTestModel{Secret: cryptypes.EncryptedString{Raw: "Test"}, Plain: "Test"}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return oldDB, newDB, newSetup
+}
@@ -0,0 +1,352 @@
+// Package rotation re-encrypts and re-signs existing rows, so values written under older Setups end up under the current one.
+// Once no rows use an older Setup anymore, its keys can safely be retired.
+package rotation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	gc "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// DefaultBatchSize is the number of rows a Rotator reads and rewrites per transaction unless told otherwise
+const DefaultBatchSize = 100
+
+// Progress reports how far a Rotator has gotten through a single model's table
+type Progress struct {
+	// Model is the name of the table being rotated
+	Model string
+	// Scanned is the number of rows read so far
+	Scanned int
+	// Rotated is the number of rows rewritten to the current Setup so far
+	Rotated int
+	// Skipped is the number of rows left alone because a signed value in them failed verification, including rows which couldn't be read because of it
+	Skipped int
+	// Changed is the number of rows left alone because they were changed between being read and rewritten; the next Run rotates them
+	Changed int
+	// Done indicates the whole table has been processed
+	Done bool
+}
+
+// Rotator walks the tables of the models registered with it, and rewrites every row holding values from a non-current Setup.
+// Rows are processed in batches of BatchSize, each in its own transaction, with a checkpoint saved after each batch so an interrupted run can resume.
+// Rewrites only go ahead if the rewritten columns still hold the values which were read, so changes made in the meantime are never overwritten.
+type Rotator struct {
+	// BatchSize is the number of rows to process per transaction
+	BatchSize int
+	// Throttle is how long to pause between batches, to limit the load placed on the DB
+	Throttle time.Duration
+	// Checkpoints records how far each model has been processed
+	Checkpoints Checkpointer
+	// OnProgress, if set, is called after every batch
+	OnProgress func(Progress)
+
+	db     *gorm.DB
+	models []interface{}
+}
+
+// New creates a Rotator which will rotate the given models through the given DB
+func New(db *gorm.DB, models ...interface{}) *Rotator {
+	return &Rotator{
+		BatchSize:   DefaultBatchSize,
+		Checkpoints: NewMemoryCheckpoints(),
+		db:          db,
+		models:      models,
+	}
+}
+
+// Register adds more models to the Rotator
+func (r *Rotator) Register(models ...interface{}) *Rotator {
+	r.models = append(r.models, models...)
+
+	return r
+}
+
+// Run rotates every registered model in turn, returning the final Progress of each
+func (r *Rotator) Run(ctx context.Context) ([]Progress, error) {
+	config := gc.ConfigFor(r.db)
+	if len(config.Setups) < 1 {
+		return nil, errors.New("database cryptography configuration incomplete")
+	}
+	setup, err := config.ActiveSetup()
+	if err != nil {
+		return nil, err
+	}
+	current := setup.Identifier()
+
+	results := make([]Progress, 0, len(r.models))
+	for _, model := range r.models {
+		progress, err := r.rotate(ctx, model, current)
+		results = append(results, progress)
+		if err != nil {
+			return results, err
+		}
+	}
+
+	return results, nil
+}
+
+// PRIVATE
+
+func (r *Rotator) rotate(ctx context.Context, model interface{}, current string) (Progress, error) {
+	db := r.db.WithContext(ctx).Unscoped()
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return Progress{}, err
+	}
+	sch := stmt.Schema
+	progress := Progress{Model: sch.Table}
+
+	if len(sch.PrimaryFields) != 1 {
+		return progress, fmt.Errorf("%s: rotation requires exactly one primary key", sch.Table)
+	}
+	primary := sch.PrimaryFields[0]
+
+	fields := cryptFields(sch)
+	if len(fields) < 1 {
+		progress.Done = true
+		return progress, nil
+	}
+
+	last, err := r.loadCheckpoint(sch.Table, primary)
+	if err != nil {
+		return progress, err
+	}
+
+	batchSize := r.BatchSize
+	if batchSize < 1 {
+		batchSize = DefaultBatchSize
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return progress, err
+		}
+
+		keys := reflect.New(reflect.SliceOf(primary.IndirectFieldType))
+		err := db.Transaction(func(tx *gorm.DB) error {
+			query := tx.Model(model).Order(clause.OrderByColumn{Column: clause.Column{Name: primary.DBName}}).Limit(batchSize)
+			if last != nil {
+				query = query.Where(clause.Gt{Column: clause.Column{Name: primary.DBName}, Value: last})
+			}
+			if err := query.Pluck(primary.DBName, keys.Interface()).Error; err != nil {
+				return err
+			}
+
+			rows, skipped, err := readBatch(tx, model, sch, primary, keys.Elem())
+			if err != nil {
+				return err
+			}
+			progress.Scanned += skipped
+			progress.Skipped += skipped
+
+			for i := 0; i < rows.Len(); i++ {
+				row := rows.Index(i)
+				stale, invalid := inspect(ctx, row, fields, current)
+
+				switch {
+				case invalid:
+					progress.Skipped++
+				case len(stale) > 0:
+					rewritten, err := rewriteRow(tx, sch.Table, row.Interface(), stale, storedValues(tx, row, fields))
+					if err != nil {
+						return err
+					}
+					if rewritten {
+						progress.Rotated++
+					} else {
+						progress.Changed++
+					}
+				}
+				progress.Scanned++
+			}
+
+			return nil
+		})
+		if err != nil {
+			return progress, err
+		}
+
+		found := keys.Elem()
+		if found.Len() < 1 {
+			break
+		}
+		last = found.Index(found.Len() - 1).Interface()
+
+		if err := r.saveCheckpoint(sch.Table, last); err != nil {
+			return progress, err
+		}
+		if r.OnProgress != nil {
+			r.OnProgress(progress)
+		}
+
+		if found.Len() < batchSize {
+			break
+		}
+		if r.Throttle > 0 {
+			select {
+			case <-ctx.Done():
+				return progress, ctx.Err()
+			case <-time.After(r.Throttle):
+			}
+		}
+	}
+
+	progress.Done = true
+	if err := r.Checkpoints.Save(sch.Table, ""); err != nil {
+		return progress, err
+	}
+	if r.OnProgress != nil {
+		r.OnProgress(progress)
+	}
+
+	return progress, nil
+}
+
+func (r *Rotator) loadCheckpoint(table string, primary *schema.Field) (interface{}, error) {
+	saved, err := r.Checkpoints.Load(table)
+	if err != nil || saved == "" {
+		return nil, err
+	}
+
+	last := reflect.New(primary.FieldType)
+	if err := json.Unmarshal([]byte(saved), last.Interface()); err != nil {
+		return nil, fmt.Errorf("%s: invalid checkpoint: %w", table, err)
+	}
+
+	return last.Elem().Interface(), nil
+}
+
+func (r *Rotator) saveCheckpoint(table string, last interface{}) error {
+	encoded, err := json.Marshal(last)
+	if err != nil {
+		return err
+	}
+
+	return r.Checkpoints.Save(table, string(encoded))
+}
+
+// cryptFields finds the fields of a model which hold gormcrypto values
+func cryptFields(sch *schema.Schema) []*schema.Field {
+	binderType := reflect.TypeOf((*gc.Binder)(nil)).Elem()
+
+	fields := make([]*schema.Field, 0)
+	for _, field := range sch.Fields {
+		if field.DBName != "" && reflect.PtrTo(field.FieldType).Implements(binderType) {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// readBatch reads the rows of a batch by their primary keys. Rows which can't be read because their signatures are invalid - under SignatureError,
+// or rejected by a SignatureChecker - fail the whole query, so the batch is read again a row at a time, and those rows are counted as skipped.
+func readBatch(tx *gorm.DB, model interface{}, sch *schema.Schema, primary *schema.Field, keys reflect.Value) (reflect.Value, int, error) {
+	batch := reflect.New(reflect.SliceOf(reflect.PtrTo(sch.ModelType)))
+	if keys.Len() < 1 {
+		return batch.Elem(), 0, nil
+	}
+
+	err := tx.Model(model).Where(clause.IN{Column: clause.Column{Name: primary.DBName}, Values: values(keys)}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: primary.DBName}}).Find(batch.Interface()).Error
+	if !errors.Is(err, gc.ErrSignatureInvalid) {
+		return batch.Elem(), 0, err
+	}
+
+	rows, skipped := reflect.MakeSlice(batch.Elem().Type(), 0, keys.Len()), 0
+	for i := 0; i < keys.Len(); i++ {
+		row := reflect.New(sch.ModelType)
+		err := tx.Model(model).Where(clause.Eq{Column: clause.Column{Name: primary.DBName}, Value: keys.Index(i).Interface()}).Find(row.Interface()).Error
+		switch {
+		case errors.Is(err, gc.ErrSignatureInvalid):
+			skipped++
+		case err != nil:
+			return rows, skipped, err
+		default:
+			rows = reflect.Append(rows, row)
+		}
+	}
+
+	return rows, skipped, nil
+}
+
+// values converts a slice of primary keys into the values of an IN condition
+func values(keys reflect.Value) []interface{} {
+	out := make([]interface{}, keys.Len())
+	for i := range out {
+		out[i] = keys.Index(i).Interface()
+	}
+
+	return out
+}
+
+// storedValues records the values a row's fields were read with, exactly as their columns held them, keyed by column
+func storedValues(db *gorm.DB, row reflect.Value, fields []*schema.Field) map[string]interface{} {
+	row = reflect.Indirect(row)
+	expected := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if stored := field.ReflectValueOf(db.Statement.Context, row).Addr().Interface().(gc.Binder).ScannedValue(); stored != nil {
+			expected[field.DBName] = stored
+		}
+	}
+
+	return expected
+}
+
+// rewriteRow writes the stale columns of a row again under the current Setup, unless they no longer hold the values they were read with.
+// It reports whether the row was rewritten; the row's primary key is added to the conditions by GORM, as the row is also the Model.
+func rewriteRow(db *gorm.DB, table string, row interface{}, stale []string, expected map[string]interface{}) (bool, error) {
+	conditions := make([]clause.Expression, 0, len(stale))
+	for _, column := range stale {
+		conditions = append(conditions, clause.Eq{Column: clause.Column{Name: column}, Value: expected[column]})
+	}
+
+	result := db.Table(table).Model(row).Where(clause.And(conditions...)).Select(stale).UpdateColumns(row)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// inspect finds the columns of a row whose values were written by a non-current Setup, and checks whether any of its signed values are invalid.
+// Only those columns are rewritten, so values which were never scanned - such as NULLs - are left exactly as they were.
+func inspect(ctx context.Context, row reflect.Value, fields []*schema.Field, current string) (stale []string, invalid bool) {
+	for _, field := range fields {
+		value := field.ReflectValueOf(ctx, row)
+		if value.Addr().Interface().(gc.Binder).ScannedValue() == nil {
+			continue
+		}
+
+		if valid := value.FieldByName("Valid"); valid.IsValid() && valid.Kind() == reflect.Bool && !valid.Bool() {
+			invalid = true
+		}
+
+		if !written(value.Addr().Interface(), current) {
+			stale = append(stale, field.DBName)
+		}
+	}
+
+	return
+}
+
+// written reports whether a scanned value's Envelope was written by the current Setup; legacy values, and any without Metadata, never were
+func written(value interface{}, current string) bool {
+	reader, ok := value.(interface {
+		Metadata() (cryptypes.Metadata, error)
+	})
+	if !ok {
+		return false
+	}
+
+	meta, err := reader.Metadata()
+
+	return err == nil && meta.Version > 0 && meta.SetupID == current
+}
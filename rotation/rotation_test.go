@@ -0,0 +1,354 @@
+package rotation_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gc "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+	"github.com/danhunsaker/gorm-crypto/encoding"
+	"github.com/danhunsaker/gorm-crypto/encryption"
+	"github.com/danhunsaker/gorm-crypto/rotation"
+	"github.com/danhunsaker/gorm-crypto/serializing"
+	"github.com/danhunsaker/gorm-crypto/signing"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type rotationTestModel struct {
+	ID       uint
+	Secret   cryptypes.EncryptedString
+	Optional cryptypes.NullSignedEncryptedString
+	Plain    string
+}
+
+type rotationStorageTestModel struct {
+	ID   uint
+	Text cryptypes.EncryptedString       `gormcrypto:"storage:text"`
+	JSON cryptypes.SignedEncryptedString `gormcrypto:"storage:json"`
+}
+
+type rotationSignedTestModel struct {
+	ID   uint
+	Note cryptypes.SignedString `gormcrypto:"signature:error"`
+}
+
+func TestRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rotation.db")
+	oldSetup, newSetup := getSetups(t)
+	oldDB := openDB(t, path, map[time.Time]gc.Setup{time.Now().Add(-1 * time.Hour): oldSetup})
+	newDB := openDB(t, path, map[time.Time]gc.Setup{time.Now().Add(-1 * time.Hour): oldSetup, time.Now(): newSetup})
+
+	for i := 0; i < 5; i++ {
+		row := rotationTestModel{
+			Secret:   cryptypes.EncryptedString{Raw: "Test"},
+			Optional: cryptypes.NullSignedEncryptedString{Empty: i%2 == 0, Raw: "Test"},
+			Plain:    "Test",
+		}
+		if err := oldDB.Create(&row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	checkpoints, err := rotation.NewTableCheckpoints(newDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updates := 0
+	rotator := rotation.New(newDB, &rotationTestModel{})
+	rotator.BatchSize = 2
+	rotator.Checkpoints = checkpoints
+	rotator.OnProgress = func(rotation.Progress) { updates++ }
+
+	results, err := rotator.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result; got %d instead", len(results))
+	}
+	if results[0].Scanned != 5 || results[0].Rotated != 5 || results[0].Skipped != 0 || !results[0].Done {
+		t.Errorf("Expected 5 rows scanned and rotated; got %+v instead", results[0])
+	}
+	if updates != 4 {
+		t.Errorf("Expected 4 progress updates; got %d instead", updates)
+	}
+	if saved, _ := checkpoints.Load(results[0].Model); saved != "" {
+		t.Errorf("Expected checkpoint to be cleared; got %q instead", saved)
+	}
+
+	assertSetup(t, newDB, newSetup.Identifier())
+
+	var rows []rotationTestModel
+	if err := newDB.Find(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	for i, row := range rows {
+		if row.Secret.Raw != "Test" || row.Plain != "Test" {
+			t.Errorf("Expected values to survive rotation; got %+v instead", row)
+		}
+		if i%2 == 1 && (row.Optional.Raw != "Test" || !row.Optional.Valid) {
+			t.Errorf("Expected values to survive rotation; got %+v instead", row.Optional)
+		}
+	}
+
+	var nulls int64
+	if err := newDB.Model(&rotationTestModel{}).Where("optional IS NULL").Count(&nulls).Error; err != nil {
+		t.Fatal(err)
+	}
+	if nulls != 3 {
+		t.Errorf("Expected NULL values to survive rotation; got %d NULLs instead of 3", nulls)
+	}
+	if err := oldDB.Find(&rows).Error; err == nil {
+		t.Error("Expected rotated rows to be unreadable with only the old Setup; got no error")
+	}
+
+	results, err = rotator.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Scanned != 5 || results[0].Rotated != 0 {
+		t.Errorf("Expected 5 rows scanned and none rotated; got %+v instead", results[0])
+	}
+}
+
+func TestRotationResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resume.db")
+	oldSetup, newSetup := getSetups(t)
+	oldDB := openDB(t, path, map[time.Time]gc.Setup{time.Now().Add(-1 * time.Hour): oldSetup})
+	newDB := openDB(t, path, map[time.Time]gc.Setup{time.Now().Add(-1 * time.Hour): oldSetup, time.Now(): newSetup})
+
+	for i := 0; i < 4; i++ {
+		if err := oldDB.Create(&rotationTestModel{Secret: cryptypes.EncryptedString{Raw: "Test"}}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	checkpoints := rotation.NewMemoryCheckpoints()
+	checkpoints.Save("rotation_test_models", "2")
+
+	rotator := rotation.New(newDB).Register(&rotationTestModel{})
+	rotator.Checkpoints = checkpoints
+	results, err := rotator.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Scanned != 2 || results[0].Rotated != 2 {
+		t.Errorf("Expected 2 rows scanned and rotated; got %+v instead", results[0])
+	}
+
+	var rows []rotationTestModel
+	if err := oldDB.Where("id <= ?", 2).Find(&rows).Error; err != nil {
+		t.Errorf("Expected rows before the checkpoint to be left alone; got %v", err)
+	}
+}
+
+func TestRotationCancelled(t *testing.T) {
+	_, newSetup := getSetups(t)
+	db := openDB(t, filepath.Join(t.TempDir(), "cancel.db"), map[time.Time]gc.Setup{time.Now(): newSetup})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := rotation.New(db, &rotationTestModel{}).Run(ctx); err != context.Canceled {
+		t.Errorf("Expected %v; got %v instead", context.Canceled, err)
+	}
+}
+
+func TestRotationConflict(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "conflict.db")
+	oldSetup, newSetup := getSetups(t)
+	oldDB := openDB(t, path, map[time.Time]gc.Setup{time.Now().Add(-1 * time.Hour): oldSetup})
+	newDB := openDB(t, path, map[time.Time]gc.Setup{time.Now().Add(-1 * time.Hour): oldSetup, time.Now(): newSetup})
+
+	for i := 0; i < 2; i++ {
+		if err := oldDB.Create(&rotationTestModel{Secret: cryptypes.EncryptedString{Raw: "Test"}}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Simulate another writer changing a row between it being read and it being rotated
+	changed := false
+	err := newDB.Callback().Query().After("gormcrypto:bind").Register("test:concurrent", func(db *gorm.DB) {
+		if _, read := db.Statement.Dest.(*[]*rotationTestModel); !read || changed {
+			return
+		}
+		changed = true
+		db.AddError(db.Session(&gorm.Session{NewDB: true}).Model(&rotationTestModel{ID: 1}).Updates(&rotationTestModel{Secret: cryptypes.EncryptedString{Raw: "Concurrent"}}).Error)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := rotation.New(newDB, &rotationTestModel{}).Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Scanned != 2 || results[0].Rotated != 1 || results[0].Changed != 1 {
+		t.Errorf("Expected 1 row rotated and 1 left alone; got %+v instead", results[0])
+	}
+
+	var row rotationTestModel
+	if err := newDB.First(&row, 1).Error; err != nil || row.Secret.Raw != "Concurrent" {
+		t.Errorf("Expected the concurrent change to survive; got %v (%v) instead", row.Secret.Raw, err)
+	}
+}
+
+func TestRotationStorage(t *testing.T) {
+	oldDB, newDB := openStorageDBs(t, "storage.db", 3)
+
+	results, err := rotation.New(newDB, &rotationStorageTestModel{}).Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Scanned != 3 || results[0].Rotated != 3 || results[0].Changed != 0 {
+		t.Errorf("Expected 3 rows scanned and rotated; got %+v instead", results[0])
+	}
+
+	var rows []rotationStorageTestModel
+	if err := oldDB.Find(&rows).Error; err == nil {
+		t.Error("Expected rotated rows to be unreadable with only the old Setup; got no error")
+	}
+	if err := newDB.Find(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range rows {
+		if row.Text.Raw != "Text" || row.JSON.Raw != "JSON" || !row.JSON.Valid {
+			t.Errorf("Expected values to survive rotation; got %+v instead", row)
+		}
+	}
+}
+
+func TestRotationInvalidSignature(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "invalid.db")
+	oldSetup, newSetup := getSetups(t)
+	oldDB := openDB(t, path, map[time.Time]gc.Setup{time.Now().Add(-1 * time.Hour): oldSetup})
+	newDB := openDB(t, path, map[time.Time]gc.Setup{time.Now().Add(-1 * time.Hour): oldSetup, time.Now(): newSetup})
+	if err := oldDB.AutoMigrate(&rotationSignedTestModel{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := oldDB.Create(&rotationSignedTestModel{Note: cryptypes.SignedString{Raw: "Test"}}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Tamper with one row, so it can't be read under its field's SignaturePolicy
+	var stored []byte
+	if err := oldDB.Table("rotation_signed_test_models").Select("note").Where("id = ?", 2).Row().Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+	var envelope cryptypes.Envelope
+	if err := envelope.UnmarshalBinary(stored); err != nil {
+		t.Fatal(err)
+	}
+	envelope.Raw[len(envelope.Raw)-1] ^= 1
+	forged, err := envelope.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := oldDB.Table("rotation_signed_test_models").Where("id = ?", 2).Update("note", forged).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	rotator := rotation.New(newDB, &rotationSignedTestModel{})
+	rotator.BatchSize = 2
+	results, err := rotator.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Scanned != 3 || results[0].Rotated != 2 || results[0].Skipped != 1 || !results[0].Done {
+		t.Errorf("Expected 2 rows rotated and the tampered one skipped; got %+v instead", results[0])
+	}
+}
+
+func assertSetup(t *testing.T, db *gorm.DB, expected string) {
+	rows, err := db.Table("rotation_test_models").Select("secret").Rows()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var raw []byte
+		var envelope cryptypes.Envelope
+
+		if err := rows.Scan(&raw); err != nil {
+			t.Fatal(err)
+		}
+		if err := envelope.UnmarshalBinary(raw); err != nil {
+			t.Fatal(err)
+		}
+		if envelope.SetupID != expected {
+			t.Errorf("Expected setup %v; got %v instead", expected, envelope.SetupID)
+		}
+	}
+}
+
+func getSetups(t *testing.T) (gc.Setup, gc.Setup) {
+	aes, err := encryption.NewAES256GCM("OldEncryptionKeyThatIs32BytesLng")
+	if err != nil {
+		t.Fatal(err)
+	}
+	xchacha, err := encryption.NewXChaCha20Poly1305("NewEncryptionKeyThatIs32BytesLng")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return gc.Setup{
+		Encoder:    encoding.Hex{},
+		Serializer: serializing.GOB{},
+		Encrypter:  aes,
+		Signer:     signing.NewED25519FromSeed("OldSigningKeyThatIs32BytesLong!!"),
+	}, gc.Setup{
+		Encoder:    encoding.Base64{},
+		Serializer: serializing.JSON{},
+		Encrypter:  xchacha,
+		Signer:     signing.NewED25519FromSeed("NewSigningKeyThatIs32BytesLong!!"),
+	}
+}
+
+func openDB(t *testing.T, path string, setups map[time.Time]gc.Setup) *gorm.DB {
+	plugin, err := gc.NewPlugin(gc.Config{Setups: setups})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Use(plugin); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&rotationTestModel{}); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+// openStorageDBs creates rows in text and JSON Storages with an old Setup, and returns DBs which only know the old Setup, and which use a newer one
+func openStorageDBs(t *testing.T, name string, rows int) (*gorm.DB, *gorm.DB) {
+	path := filepath.Join(t.TempDir(), name)
+	oldSetup, newSetup := getSetups(t)
+	oldDB := openDB(t, path, map[time.Time]gc.Setup{time.Now().Add(-1 * time.Hour): oldSetup})
+	newDB := openDB(t, path, map[time.Time]gc.Setup{time.Now().Add(-1 * time.Hour): oldSetup, time.Now(): newSetup})
+	if err := oldDB.AutoMigrate(&rotationStorageTestModel{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < rows; i++ {
+		row := rotationStorageTestModel{Text: cryptypes.EncryptedString{Raw: "Text"}, JSON: cryptypes.SignedEncryptedString{Raw: "JSON"}}
+		if err := oldDB.Create(&row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return oldDB, newDB
+}
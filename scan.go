@@ -0,0 +1,105 @@
+package gormcrypto
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// PendingBinder is implemented by values which a Plugin binds to their rows once the rest of the row has been read, such as the types in the cryptypes package.
+// Pending values hold back errors which binding them to their rows would fix, until the Plugin scans them again.
+type PendingBinder interface {
+	// BindPending marks the value as waiting for a Plugin to bind it to its row, or not
+	BindPending(bool)
+	// HeldBack reports whether an error was held back the last time the value was scanned
+	HeldBack() bool
+}
+
+// BindScans prepares a field of a model's schema, so the values GORM scans into it are bound to the Config of the statement reading them,
+// and to the Storage and SignaturePolicy the field is tagged with, before they're read.
+// The types in the cryptypes package call it as GORM parses the schemas they're used in, so it's only needed for Binders of your own.
+// Values scanned by a Plugin's queries are marked as pending, so errors only binding them to their rows would fix are held back until then;
+// every other error is reported as the value is read.
+func BindScans(field *schema.Field) {
+	if _, ok := reflect.New(field.IndirectFieldType).Interface().(Binder); !ok || field.Serializer != nil {
+		return
+	}
+
+	set := field.Set
+	field.NewValuePool = capturePool{}
+	field.Set = func(ctx context.Context, model reflect.Value, v interface{}) error {
+		captured, ok := v.(*capturedScan)
+		if !ok {
+			return set(ctx, model, v)
+		}
+		if captured.value == nil && field.FieldType.Kind() == reflect.Ptr {
+			return set(ctx, model, nil)
+		}
+
+		value := reflect.New(field.IndirectFieldType)
+		if err := bindScan(ctx, field, value.Interface().(Binder)); err != nil {
+			return err
+		}
+		if err := value.Interface().(sql.Scanner).Scan(captured.value); err != nil {
+			return err
+		}
+		bindStatement(nil, value.Interface())
+
+		return set(ctx, model, value.Interface())
+	}
+}
+
+// PRIVATE
+
+type pluginContextKey struct{}
+
+type pendingContextKey struct{}
+
+// capturedScan holds a DB value GORM scanned for a field prepared by BindScans, until GORM sets the field with it, along with the statement's context
+type capturedScan struct {
+	value interface{}
+}
+
+// Scan copies the DB value, since drivers may reuse the memory it's held in once the next row is read
+func (c *capturedScan) Scan(value interface{}) error {
+	if binary, ok := value.([]byte); ok {
+		value = append([]byte(nil), binary...)
+	}
+	c.value = value
+
+	return nil
+}
+
+// capturePool gives GORM a fresh capturedScan for every value it scans into a field prepared by BindScans
+type capturePool struct{}
+
+func (capturePool) Get() interface{} {
+	return &capturedScan{}
+}
+
+func (capturePool) Put(interface{}) {}
+
+// bindScan binds a value about to be scanned to the Config of the statement reading it - that of its Plugin, or one added with WithConfig -
+// to the statement's context, and to the Storage and SignaturePolicy its field is tagged with, and requires it to be bound to its row if it's tagged to be.
+// Values read by a Plugin's queries are pending, as the Plugin binds them to their rows once the whole row is read.
+func bindScan(ctx context.Context, field *schema.Field, binder Binder) error {
+	if plugin, ok := ctx.Value(pluginContextKey{}).(*Plugin); ok {
+		binder.BindConfig(&plugin.Config)
+		if pending, ok := binder.(PendingBinder); ok {
+			queried, _ := ctx.Value(pendingContextKey{}).(bool)
+			pending.BindPending(queried)
+		}
+	} else if config, ok := ConfigFromContext(ctx); ok {
+		binder.BindConfig(&config)
+	}
+	bindStatement(ctx, binder)
+	requireContext(field, binder)
+
+	if err := bindStorage(field, binder); err != nil {
+		return err
+	}
+
+	return bindSignaturePolicy(field, binder)
+}
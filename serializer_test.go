@@ -0,0 +1,89 @@
+package gormcrypto_test
+
+import (
+	"testing"
+
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+)
+
+type serializerTestAddress struct {
+	City    string
+	Country string
+}
+
+type serializerTestModel struct {
+	cryptypes.Signatures `gorm:"-"`
+	ID                   uint
+	Email                string                 `gorm:"serializer:encrypted"`
+	Age                  *int                   `gorm:"serializer:signed"`
+	Address              serializerTestAddress  `gorm:"serializer:signedencrypted"`
+	Balance              complex128             `gorm:"serializer:encrypted"`
+	Missing              *serializerTestAddress `gorm:"serializer:encrypted"`
+}
+
+type serializerTestUnrecorded struct {
+	ID  uint
+	Age *int `gorm:"serializer:signed"`
+}
+
+func (serializerTestUnrecorded) TableName() string {
+	return "serializer_test_models"
+}
+
+func TestSerializers(t *testing.T) {
+	db := openTestDB(t, "", getTestConfig(), &serializerTestModel{})
+
+	age := 42
+	expected := serializerTestModel{Email: "user@example.com", Age: &age, Address: serializerTestAddress{City: "Springfield", Country: "US"}, Balance: 1 - 2i}
+	if err := db.Create(&expected).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var stored []byte
+	if err := db.Table("serializer_test_models").Select("email").Where("id = ?", expected.ID).Row().Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if !cryptypes.IsEnvelope(stored) {
+		t.Errorf("Expected an encrypted envelope; got %q instead", stored)
+	}
+
+	var actual serializerTestModel
+	if err := db.First(&actual, expected.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if actual.Email != expected.Email || actual.Age == nil || *actual.Age != age || actual.Address != expected.Address || actual.Balance != expected.Balance || actual.Missing != nil {
+		t.Errorf("Expected %+v; got %+v instead", expected, actual)
+	}
+	if !actual.Valid("Age") || !actual.Valid("Address") {
+		t.Errorf("Expected valid signatures; got %v instead", actual.Signatures)
+	}
+
+	other := 7
+	tampered := serializerTestModel{Email: "other@example.com", Age: &other}
+	if err := db.Create(&tampered).Error; err != nil {
+		t.Fatal(err)
+	}
+	envelope := cryptypes.Envelope{}
+	if err := db.Table("serializer_test_models").Select("age").Where("id = ?", tampered.ID).Row().Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if err := envelope.UnmarshalBinary(stored); err != nil {
+		t.Fatal(err)
+	}
+	envelope.Raw = []byte("13")
+	forged, _ := envelope.MarshalBinary()
+	if err := db.Exec("UPDATE serializer_test_models SET age = ? WHERE id = ?", forged, tampered.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var recorded serializerTestModel
+	if err := db.First(&recorded, tampered.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if recorded.Valid("Age") {
+		t.Error("Expected the forged signature to be recorded as invalid")
+	}
+	if err := db.First(&serializerTestUnrecorded{}, tampered.ID).Error; err == nil {
+		t.Error("Expected an error reading a forged signature without a SignatureRecorder; got none")
+	}
+}
@@ -0,0 +1,162 @@
+package gormcrypto
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// SignaturePolicy decides what happens when a signed value read from the DB fails verification
+type SignaturePolicy int
+
+// The SignaturePolicies a Config or field can use
+const (
+	// SignatureFlag reads the value anyway, and flags it by setting its Valid field to false; this is the default
+	SignatureFlag SignaturePolicy = iota
+	// SignatureError refuses to read the value, returning ErrSignatureInvalid from Scan, which fails the query
+	SignatureError
+	// SignatureZero flags the value as SignatureFlag does, and also clears its Raw value, so unverified data can't be used by mistake
+	SignatureZero
+	// SignatureCallback flags the value as SignatureFlag does, then passes it to the Config's OnInvalidSignature hook
+	SignatureCallback
+)
+
+// MarshalPolicy decides how signed values whose signatures failed verification as they were read are marshaled to JSON, text, and YAML.
+// It's separate from the SignaturePolicy values are read under, so values can be read and flagged, say, without ever being marshaled as trustworthy.
+type MarshalPolicy int
+
+// The MarshalPolicies a Config can use
+const (
+	// MarshalNull marshals values whose signatures are invalid as null, or as empty text; this is the default
+	MarshalNull MarshalPolicy = iota
+	// MarshalValue marshals values whose signatures are invalid as they are, as though they were valid
+	MarshalValue
+	// MarshalError refuses to marshal values whose signatures are invalid, returning ErrSignatureInvalid
+	MarshalError
+)
+
+// ErrSignatureInvalid is returned when a signed value is used in a way that requires its signature to be valid, but it isn't
+var ErrSignatureInvalid = errors.New("value's signature is invalid")
+
+// SignatureBinder is implemented by values which can be told what to do when their signatures fail verification, such as the types in the cryptypes package.
+// A Plugin binds fields tagged with `gormcrypto:"signature:error"` (or the name of any other SignaturePolicy) to that policy as they're read;
+// values which aren't bound to a SignaturePolicy use that of their Config.
+type SignatureBinder interface {
+	// BindSignaturePolicy sets the SignaturePolicy the value follows, instead of that of its Config
+	BindSignaturePolicy(SignaturePolicy)
+	// BoundSignaturePolicy returns the SignaturePolicy the value is bound to, if any
+	BoundSignaturePolicy() (SignaturePolicy, bool)
+	// SignatureFailed reports whether the signature of the value most recently scanned failed verification
+	SignatureFailed() bool
+}
+
+// SignatureChecker is implemented by models which decide whether a row can be used at all once some of its signed fields have failed verification.
+// A Plugin calls CheckSignatures for every row read with invalid signatures, with the names of the fields which failed, once the row is fully bound;
+// returning an error rejects the row, failing the query.
+// GORM's own AfterFind hooks run afterwards, so they see each field as its SignaturePolicy left it, but can't tell which fields failed.
+type SignatureChecker interface {
+	CheckSignatures(tx *gorm.DB, invalid []string) error
+}
+
+// String converts the SignaturePolicy to the name used for it in tags
+func (p SignaturePolicy) String() string {
+	if name, ok := signaturePolicyNames[p]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("unknown(%d)", int(p))
+}
+
+// MarshalText converts the SignaturePolicy to the name used for it in tags
+func (p SignaturePolicy) MarshalText() ([]byte, error) {
+	if _, ok := signaturePolicyNames[p]; !ok {
+		return nil, fmt.Errorf("unknown signature policy %d", int(p))
+	}
+
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText converts a SignaturePolicy name from a tag back into a SignaturePolicy
+func (p *SignaturePolicy) UnmarshalText(text []byte) error {
+	for policy, name := range signaturePolicyNames {
+		if name == string(text) {
+			*p = policy
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown signature policy %q", text)
+}
+
+// FieldSignaturePolicy returns the SignaturePolicy a field is tagged with, if any, or an error if the tag doesn't name a SignaturePolicy
+func FieldSignaturePolicy(field *schema.Field) (SignaturePolicy, bool, error) {
+	var policy SignaturePolicy
+
+	name, ok := schema.ParseTagSetting(field.Tag.Get("gormcrypto"), ";")["SIGNATURE"]
+	if !ok {
+		return policy, false, nil
+	}
+	if err := policy.UnmarshalText([]byte(name)); err != nil {
+		return policy, false, fmt.Errorf("%s.%s: %w", field.Schema.Name, field.Name, err)
+	}
+
+	return policy, true, nil
+}
+
+// PRIVATE
+
+var signaturePolicyNames = map[SignaturePolicy]string{
+	SignatureFlag:     "flag",
+	SignatureError:    "error",
+	SignatureZero:     "zero",
+	SignatureCallback: "callback",
+}
+
+// bindSignaturePolicy binds a field to the SignaturePolicy it's tagged with, if any
+func bindSignaturePolicy(field *schema.Field, value interface{}) error {
+	binder, ok := value.(SignatureBinder)
+	if !ok {
+		return nil
+	}
+
+	policy, ok, err := FieldSignaturePolicy(field)
+	if err != nil || !ok {
+		return err
+	}
+	if bound, ok := binder.BoundSignaturePolicy(); !ok || bound != policy {
+		binder.BindSignaturePolicy(policy)
+	}
+
+	return nil
+}
+
+// checkSignatures passes every row just read whose signed fields failed verification to the row's CheckSignatures method, if it's a SignatureChecker
+func checkSignatures(db *gorm.DB) {
+	if !reflect.PtrTo(db.Statement.Schema.ModelType).Implements(reflect.TypeOf((*SignatureChecker)(nil)).Elem()) {
+		return
+	}
+
+	eachModel(db, db.Statement.ReflectValue, func(model reflect.Value) {
+		if !model.CanAddr() {
+			return
+		}
+
+		var invalid []string
+		for _, field := range db.Statement.Schema.Fields {
+			fieldValue := field.ReflectValueOf(db.Statement.Context, model)
+			if !fieldValue.CanAddr() {
+				continue
+			}
+			if binder, ok := fieldValue.Addr().Interface().(SignatureBinder); ok && binder.SignatureFailed() {
+				invalid = append(invalid, field.Name)
+			}
+		}
+
+		if len(invalid) > 0 {
+			db.AddError(model.Addr().Interface().(SignatureChecker).CheckSignatures(db, invalid))
+		}
+	})
+}
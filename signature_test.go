@@ -0,0 +1,114 @@
+package gormcrypto_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	gormcrypto "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+	"gorm.io/gorm"
+)
+
+type signatureTestModel struct {
+	ID     uint
+	Note   cryptypes.SignedString
+	Strict cryptypes.SignedString `gormcrypto:"signature:error"`
+	Count  int                    `gorm:"serializer:signed" gormcrypto:"signature:zero"`
+}
+
+type signatureTestChecked struct {
+	ID   uint
+	Note cryptypes.SignedString
+}
+
+func (signatureTestChecked) TableName() string {
+	return "signature_test_models"
+}
+
+func (s *signatureTestChecked) CheckSignatures(tx *gorm.DB, invalid []string) error {
+	return fmt.Errorf("row %d: %v: %w", s.ID, invalid, gormcrypto.ErrSignatureInvalid)
+}
+
+type signatureTestHooked struct {
+	ID    uint
+	Note  cryptypes.SignedString
+	found cryptypes.SignedString
+}
+
+func (signatureTestHooked) TableName() string {
+	return "signature_test_models"
+}
+
+func (s *signatureTestHooked) AfterFind(*gorm.DB) error {
+	s.found = s.Note
+	return nil
+}
+
+func TestSignaturePolicy(t *testing.T) {
+	config := getTestConfig()
+	config.SignaturePolicy = gormcrypto.SignatureZero
+	db := openTestDB(t, "", config, &signatureTestModel{})
+
+	row := signatureTestModel{Note: cryptypes.SignedString{Raw: "trusted"}, Strict: cryptypes.SignedString{Raw: "trusted"}, Count: 42}
+	if err := db.Create(&row).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var actual signatureTestModel
+	if err := db.First(&actual, row.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if !actual.Note.Valid || !actual.Strict.Valid || actual.Count != 42 {
+		t.Errorf("Expected valid signatures; got %+v instead", actual)
+	}
+
+	forge(t, db, row.ID, "note", "count")
+	actual = signatureTestModel{}
+	if err := db.First(&actual, row.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if actual.Note.Valid || actual.Note.Raw != "" || actual.Count != 0 {
+		t.Errorf("Expected forged values to be cleared; got %+v instead", actual)
+	}
+
+	var hooked signatureTestHooked
+	if err := db.First(&hooked, row.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if hooked.found.Valid || hooked.found.Raw != "" {
+		t.Errorf("Expected AfterFind to see the forged value cleared; got %+v instead", hooked.found)
+	}
+
+	err := db.First(&signatureTestChecked{}, row.ID).Error
+	if !errors.Is(err, gormcrypto.ErrSignatureInvalid) {
+		t.Errorf("Expected the SignatureChecker to reject the row; got %v instead", err)
+	}
+
+	forge(t, db, row.ID, "strict")
+	if err := db.First(&signatureTestModel{}, row.ID).Error; !errors.Is(err, gormcrypto.ErrSignatureInvalid) {
+		t.Errorf("Expected %v; got %v instead", gormcrypto.ErrSignatureInvalid, err)
+	}
+}
+
+// forge replaces the signed values in a row's columns with ones which don't match their signatures
+func forge(t *testing.T, db *gorm.DB, id uint, columns ...string) {
+	for _, column := range columns {
+		var stored []byte
+		if err := db.Table("signature_test_models").Select(column).Where("id = ?", id).Row().Scan(&stored); err != nil {
+			t.Fatal(err)
+		}
+		var envelope cryptypes.Envelope
+		if err := envelope.UnmarshalBinary(stored); err != nil {
+			t.Fatal(err)
+		}
+		envelope.Raw = []byte(`"13"`)
+		if column == "count" {
+			envelope.Raw = []byte("13")
+		}
+		forged, _ := envelope.MarshalBinary()
+		if err := db.Exec("UPDATE signature_test_models SET "+column+" = ? WHERE id = ?", forged, id).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -1,6 +1,11 @@
 // Package signing defines the various signing Algorithms supported by the gormcrypto package
 package signing
 
+import (
+	"errors"
+	"fmt"
+)
+
 // Algorithm defines an interface that signing types must implement to be usable with gormcrypto.
 // A type implementing signing.Algorithm will supplement a value's' serialized representation with a cryptographic signature, or verify the same.
 // The types implemented here wrap the Go standard (and extended) library's various (non-deprecated) crypto packages.
@@ -15,8 +20,18 @@ type Algorithm interface {
 	Verify([]byte, []byte) (bool, error)
 }
 
+// Creator configures an Algorithm based on a configuration map, reporting any problems with that configuration
+type Creator func(map[string]interface{}) (Algorithm, error)
+
 // RegisterAlgo adds an Algorithm to the internal algos map so it can be used in YAML configs
 func RegisterAlgo(name string, creator func(map[string]interface{}) Algorithm) {
+	Register(name, func(m map[string]interface{}) (Algorithm, error) {
+		return creator(m), nil
+	})
+}
+
+// Register adds an Algorithm to the internal algos map so it can be used in YAML configs, using a Creator which can report errors
+func Register(name string, creator Creator) {
 	algos[name] = creator
 }
 
@@ -29,13 +44,29 @@ func SupportedAlgos() []string {
 	return keys
 }
 
-// FromYaml configures an Algorithm automatically based on a name and a configuration map
+// FromYaml configures an Algorithm automatically based on a name and a configuration map.
+// It returns nil if the Algorithm can't be configured; use New to find out why.
 func FromYaml(name string, config map[string]interface{}) Algorithm {
-	return algos[name](config)
+	algo, _ := New(name, config)
+
+	return algo
 }
 
-var algos map[string]func(map[string]interface{}) Algorithm
+// New configures an Algorithm automatically based on a name and a configuration map, reporting any problems it finds
+func New(name string, config map[string]interface{}) (Algorithm, error) {
+	creator, ok := algos[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownAlgorithm, name)
+	}
+
+	return creator(config)
+}
+
+// ErrUnknownAlgorithm is returned by New when no Algorithm has been registered under the requested name
+var ErrUnknownAlgorithm = errors.New("unknown signing algorithm")
+
+var algos map[string]Creator
 
 func init() {
-	algos = make(map[string]func(map[string]interface{}) Algorithm, 0)
+	algos = make(map[string]Creator, 0)
 }
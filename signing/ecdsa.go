@@ -6,22 +6,32 @@ import (
 	"crypto/sha256"
 	"crypto/x509"
 	"encoding/hex"
+
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
 )
 
 func init() {
-	RegisterAlgo("ecdsa", func(m map[string]interface{}) Algorithm {
-		data, _ := hex.DecodeString(m["key"].(string))
-		privKey, _ := x509.ParseECPrivateKey(data)
-		algo := NewECDSA(privKey, &privKey.PublicKey)
-		return algo
+	Register("ecdsa", func(m map[string]interface{}) (Algorithm, error) {
+		data, err := keyconfig.Hex(m, "key")
+		if err != nil {
+			return nil, err
+		}
+		privKey, err := x509.ParseECPrivateKey(data)
+		keyconfig.Wipe(data)
+		if err != nil {
+			return nil, keyconfig.Errorf("key", "%w: %v", keyconfig.ErrKeyEncoding, err)
+		}
+
+		return NewECDSA(privKey, &privKey.PublicKey), nil
 	})
 }
 
 // ECDSA supports ECDSA
 type ECDSA struct {
 	Algorithm
-	private *ecdsa.PrivateKey
-	public  *ecdsa.PublicKey
+	private     *ecdsa.PrivateKey
+	public      *ecdsa.PublicKey
+	fingerprint []byte
 }
 
 // Name identifies the Algorithm as a string for exporting configurations
@@ -31,6 +41,10 @@ func (ECDSA) Name() string {
 
 // Config converts an Algorthim's internal configuration into a map for export
 func (s ECDSA) Config() map[string]interface{} {
+	if s.private == nil {
+		return nil
+	}
+
 	key, _ := x509.MarshalECPrivateKey(s.private)
 
 	return map[string]interface{}{
@@ -38,22 +52,54 @@ func (s ECDSA) Config() map[string]interface{} {
 	}
 }
 
+// KeyFingerprint returns a one-way hash of the public key, computed as the Algorithm was created
+func (s ECDSA) KeyFingerprint() []byte {
+	return s.fingerprint
+}
+
 // NewECDSA creates a new ECDSA value
 func NewECDSA(privateKey *ecdsa.PrivateKey, publicKey *ecdsa.PublicKey) *ECDSA {
-	return &ECDSA{
-		private: privateKey,
-		public:  publicKey,
+	s := &ECDSA{private: privateKey, public: publicKey}
+	public := publicKey
+	if public == nil && privateKey != nil {
+		public = &privateKey.PublicKey
 	}
+	if public != nil {
+		if encoded, err := x509.MarshalPKIXPublicKey(public); err == nil {
+			s.fingerprint = keyconfig.Fingerprint(encoded)
+		}
+	}
+
+	return s
 }
 
 // Sign ::: ECDSA
 func (s *ECDSA) Sign(plain []byte) ([]byte, error) {
+	if s.private == nil {
+		return nil, keyconfig.ErrDestroyed
+	}
+
 	hash := sha256.Sum256(plain)
 	return ecdsa.SignASN1(rand.Reader, s.private, hash[:])
 }
 
 // Verify ::: ECDSA
 func (s *ECDSA) Verify(plain []byte, signature []byte) (bool, error) {
+	if s.private == nil {
+		return false, keyconfig.ErrDestroyed
+	}
+
 	hash := sha256.Sum256(plain)
 	return ecdsa.VerifyASN1(s.public, hash[:], signature), nil
 }
+
+// Destroy wipes the private key, after which the ECDSA value refuses to sign or verify anything.
+// Copies the crypto/ecdsa package keeps internally can't be reached, so they're left for the GC.
+func (s *ECDSA) Destroy() {
+	if s.private == nil {
+		return
+	}
+
+	keyconfig.WipeInt(s.private.D)
+	s.private, s.public = nil, nil
+}
@@ -3,20 +3,29 @@ package signing
 import (
 	"crypto/ed25519"
 	"encoding/hex"
+
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
 )
 
 func init() {
-	RegisterAlgo("ed25519", func(m map[string]interface{}) Algorithm {
-		seed, _ := hex.DecodeString(m["key"].(string))
-		return NewED25519FromSeed(string(seed))
+	Register("ed25519", func(m map[string]interface{}) (Algorithm, error) {
+		seed, err := keyconfig.HexSized(m, "key", 32)
+		if err != nil {
+			return nil, err
+		}
+
+		defer keyconfig.Wipe(seed)
+
+		return NewED25519FromSeedBytes(seed), nil
 	})
 }
 
 // ED25519 supports ED25519
 type ED25519 struct {
 	Algorithm
-	private *ed25519.PrivateKey
-	public  *ed25519.PublicKey
+	private     *ed25519.PrivateKey
+	public      *ed25519.PublicKey
+	fingerprint []byte
 }
 
 // Name identifies the Algorithm as a string for exporting configurations
@@ -26,36 +35,70 @@ func (ED25519) Name() string {
 
 // Config converts an Algorthim's internal configuration into a map for export
 func (s ED25519) Config() map[string]interface{} {
+	if s.private == nil {
+		return nil
+	}
+
 	return map[string]interface{}{
 		"key": hex.EncodeToString(s.private.Seed()),
 	}
 }
 
+// KeyFingerprint returns a one-way hash of the public key, computed as the Algorithm was created
+func (s ED25519) KeyFingerprint() []byte {
+	return s.fingerprint
+}
+
 // NewED25519 creates a new ED25519 value
 func NewED25519(privateKey *ed25519.PrivateKey, publicKey *ed25519.PublicKey) *ED25519 {
-	return &ED25519{
-		private: privateKey,
-		public:  publicKey,
+	s := &ED25519{private: privateKey, public: publicKey}
+	if publicKey != nil {
+		s.fingerprint = keyconfig.Fingerprint(*publicKey)
+	} else if privateKey != nil {
+		s.fingerprint = keyconfig.Fingerprint(privateKey.Public().(ed25519.PublicKey))
 	}
+
+	return s
 }
 
 // NewED25519FromSeed creates a new ED25519FromSeed value
 func NewED25519FromSeed(seed string) *ED25519 {
-	privateKey := ed25519.NewKeyFromSeed([]byte(seed))
+	return NewED25519FromSeedBytes([]byte(seed))
+}
+
+// NewED25519FromSeedBytes creates a new ED25519 value from a seed.
+// The private key is derived from the seed, so the caller should wipe its own copy of the seed once it's no longer needed.
+func NewED25519FromSeedBytes(seed []byte) *ED25519 {
+	privateKey := ed25519.NewKeyFromSeed(seed)
 	publicKey := privateKey.Public().(ed25519.PublicKey)
 
-	return &ED25519{
-		private: &privateKey,
-		public:  &publicKey,
-	}
+	return NewED25519(&privateKey, &publicKey)
 }
 
 // Sign ::: ED25519
 func (s *ED25519) Sign(plain []byte) ([]byte, error) {
+	if s.private == nil {
+		return nil, keyconfig.ErrDestroyed
+	}
+
 	return ed25519.Sign(*s.private, plain), nil
 }
 
 // Verify ::: ED25519
 func (s *ED25519) Verify(plain []byte, signature []byte) (bool, error) {
+	if s.private == nil {
+		return false, keyconfig.ErrDestroyed
+	}
+
 	return ed25519.Verify(*s.public, plain, signature), nil
 }
+
+// Destroy wipes the private key, after which the ED25519 value refuses to sign or verify anything
+func (s *ED25519) Destroy() {
+	if s.private == nil {
+		return
+	}
+
+	keyconfig.Wipe(*s.private)
+	s.private, s.public = nil, nil
+}
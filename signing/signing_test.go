@@ -5,11 +5,13 @@ import (
 	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
+	"errors"
 	"math/big"
 	"reflect"
 	"sort"
 	"testing"
 
+	"github.com/danhunsaker/gorm-crypto/keyconfig"
 	"github.com/danhunsaker/gorm-crypto/signing"
 )
 
@@ -36,6 +38,34 @@ func TestSigning(t *testing.T) {
 	}
 }
 
+func TestDestroy(t *testing.T) {
+	for _, signer := range getAlgos() {
+		t.Run(reflect.TypeOf(signer).String(), func(t *testing.T) {
+			signed, err := signer.Sign([]byte("Test"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			destroyer, ok := signer.(keyconfig.Destroyer)
+			if !ok {
+				t.Fatalf("Expected %T to implement keyconfig.Destroyer", signer)
+			}
+			destroyer.Destroy()
+			destroyer.Destroy()
+
+			if _, err := signer.Sign([]byte("Test")); !errors.Is(err, keyconfig.ErrDestroyed) {
+				t.Errorf("Expected %v; got %v instead", keyconfig.ErrDestroyed, err)
+			}
+			if _, err := signer.Verify([]byte("Test"), signed); !errors.Is(err, keyconfig.ErrDestroyed) {
+				t.Errorf("Expected %v; got %v instead", keyconfig.ErrDestroyed, err)
+			}
+			if config := signer.Config(); config != nil {
+				t.Errorf("Expected %v; got %v instead", nil, config)
+			}
+		})
+	}
+}
+
 func TestExports(t *testing.T) {
 	for _, crypto := range getAlgos() {
 		t.Run(reflect.TypeOf(crypto).String(), func(t *testing.T) {
@@ -88,3 +118,29 @@ func getAlgos() []signing.Algorithm {
 		signing.NewED25519FromSeed(string(singleKey)),
 	}
 }
+
+func TestConfigErrors(t *testing.T) {
+	tests := map[string]struct {
+		name     string
+		config   map[string]interface{}
+		expected error
+	}{
+		"unknown":   {"bogus", nil, signing.ErrUnknownAlgorithm},
+		"missing":   {"ed25519", map[string]interface{}{}, keyconfig.ErrMissingKey},
+		"type":      {"ed25519", map[string]interface{}{"key": 42}, keyconfig.ErrKeyEncoding},
+		"length":    {"ed25519", map[string]interface{}{"key": "abcdef"}, keyconfig.ErrKeyLength},
+		"malformed": {"ecdsa", map[string]interface{}{"key": "abcdef"}, keyconfig.ErrKeyEncoding},
+	}
+
+	for label, test := range tests {
+		t.Run(label, func(t *testing.T) {
+			algo, err := signing.New(test.name, test.config)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("Expected %v; got %v instead", test.expected, err)
+			}
+			if algo != nil {
+				t.Errorf("Expected nil; got %v instead", algo)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package gormcrypto
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Storage selects the kind of column values are stored in, and so the form they're written in.
+// Binary Storages write values as they are; text Storages write them as text, for replication tools and CDC pipelines which can't handle binary columns.
+// Values can be read back from any Storage, whichever they're written in now.
+type Storage int
+
+// The prefixes which start every value the cryptypes package stores in a text Storage.
+// TextPrefix starts the Base64 encoding of a value's whole binary form, as written by Value.
+// TextHeaderPrefix starts the form written when the value's payload - the output of its Setup's Encoder, usually - is text already:
+// the rest of the Envelope in Base64 and the payload's length, each followed by a colon, then the payload as it is.
+// Colons never appear in Base64, so neither form can be mistaken for the other.
+const (
+	TextPrefix       = "GCT"
+	TextHeaderPrefix = TextPrefix + ":"
+)
+
+// The Storages values can be written in
+const (
+	// StorageBinary stores values in binary columns, such as BLOB, BYTEA, or varbinary(max)
+	StorageBinary Storage = iota
+	// StorageMediumBinary stores values in MEDIUMBLOB columns on MySQL, and as StorageBinary does elsewhere
+	StorageMediumBinary
+	// StorageLongBinary stores values in LONGBLOB columns on MySQL, and as StorageBinary does elsewhere
+	StorageLongBinary
+	// StorageText stores values as text, in columns such as TEXT, nvarchar(max), or STRING
+	StorageText
+	// StorageMediumText stores values in MEDIUMTEXT columns on MySQL, and as StorageText does elsewhere
+	StorageMediumText
+	// StorageLongText stores values in LONGTEXT columns on MySQL, and as StorageText does elsewhere
+	StorageLongText
+	// StorageJSON stores values as JSON strings, in JSON or JSONB columns where the dialect has them, and as StorageText does elsewhere
+	StorageJSON
+)
+
+// StorageBinder is implemented by values which can be told which Storage to write themselves in, such as the types in the cryptypes package.
+// A Plugin binds fields tagged with `gormcrypto:"storage:text"` (or the name of any other Storage) to that Storage as they're read and written;
+// values which aren't bound to a Storage use that of the Setup writing them.
+type StorageBinder interface {
+	// BindStorage sets the Storage the value is written in, instead of that of its Setup
+	BindStorage(Storage)
+	// BoundStorage returns the Storage the value is bound to, if any
+	BoundStorage() (Storage, bool)
+}
+
+// String converts the Storage to the name used for it in YAML configs and tags
+func (s Storage) String() string {
+	if name, ok := storageNames[s]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("unknown(%d)", int(s))
+}
+
+// MarshalText converts the Storage to the name used for it in YAML configs and tags
+func (s Storage) MarshalText() ([]byte, error) {
+	if _, ok := storageNames[s]; !ok {
+		return nil, fmt.Errorf("unknown storage %d", int(s))
+	}
+
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText converts a Storage name from a YAML config or tag back into a Storage
+func (s *Storage) UnmarshalText(text []byte) error {
+	for storage, name := range storageNames {
+		if name == string(text) {
+			*s = storage
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown storage %q", text)
+}
+
+// Text reports whether the Storage writes values as text
+func (s Storage) Text() bool {
+	switch s {
+	case StorageText, StorageMediumText, StorageLongText, StorageJSON:
+		return true
+	}
+
+	return false
+}
+
+// ColumnType returns the column type to use for the Storage with the named dialect, or an empty string if the dialect isn't known
+func (s Storage) ColumnType(dialect string) string {
+	if types, ok := storageColumnTypes[dialect]; ok {
+		return types[s]
+	}
+
+	return ""
+}
+
+// Value converts a value's binary form into the form the Storage writes it in.
+// Text Storages write the Base64 encoding of the binary form after TextPrefix, quoted as TextValue does.
+// The cryptypes package only falls back to this for values whose payloads aren't text already, writing the rest after TextHeaderPrefix instead.
+func (s Storage) Value(binary []byte) driver.Value {
+	if !s.Text() {
+		return binary
+	}
+
+	return s.TextValue(TextPrefix + base64.StdEncoding.EncodeToString(binary))
+}
+
+// TextValue converts a value's text form into the form the Storage writes it in.
+// Text Storages write it as it is, and StorageJSON quotes it as a JSON string; binary Storages write its bytes.
+func (s Storage) TextValue(text string) driver.Value {
+	if !s.Text() {
+		return []byte(text)
+	}
+	if s == StorageJSON {
+		quoted, _ := json.Marshal(text)
+		return string(quoted)
+	}
+
+	return text
+}
+
+// FieldStorage returns the Storage a field is tagged with, if any, or an error if the tag doesn't name a Storage
+func FieldStorage(field *schema.Field) (Storage, bool, error) {
+	var storage Storage
+
+	name, ok := schema.ParseTagSetting(field.Tag.Get("gormcrypto"), ";")["STORAGE"]
+	if !ok {
+		return storage, false, nil
+	}
+	if err := storage.UnmarshalText([]byte(name)); err != nil {
+		return storage, false, fmt.Errorf("%s.%s: %w", field.Schema.Name, field.Name, err)
+	}
+
+	return storage, true, nil
+}
+
+// StorageFor returns the Storage values of a field are written in when using a *gorm.DB; the one it's tagged with, or that of the active Setup otherwise
+func StorageFor(db *gorm.DB, field *schema.Field) (Storage, error) {
+	storage, ok, err := FieldStorage(field)
+	if err != nil || ok {
+		return storage, err
+	}
+
+	return ConfigFor(db).CurrentSetup().Storage, nil
+}
+
+// PRIVATE
+
+var storageNames = map[Storage]string{
+	StorageBinary:       "binary",
+	StorageMediumBinary: "medium-binary",
+	StorageLongBinary:   "long-binary",
+	StorageText:         "text",
+	StorageMediumText:   "medium-text",
+	StorageLongText:     "long-text",
+	StorageJSON:         "json",
+}
+
+var storageColumnTypes = map[string]map[Storage]string{
+	"bigquery": {
+		StorageBinary: "BYTES", StorageMediumBinary: "BYTES", StorageLongBinary: "BYTES",
+		StorageText: "STRING", StorageMediumText: "STRING", StorageLongText: "STRING", StorageJSON: "JSON",
+	},
+	"clickhouse": {
+		StorageBinary: "String", StorageMediumBinary: "String", StorageLongBinary: "String",
+		StorageText: "String", StorageMediumText: "String", StorageLongText: "String", StorageJSON: "String",
+	},
+	"mysql": {
+		StorageBinary: "BLOB", StorageMediumBinary: "MEDIUMBLOB", StorageLongBinary: "LONGBLOB",
+		StorageText: "TEXT", StorageMediumText: "MEDIUMTEXT", StorageLongText: "LONGTEXT", StorageJSON: "JSON",
+	},
+	"postgres": {
+		StorageBinary: "BYTEA", StorageMediumBinary: "BYTEA", StorageLongBinary: "BYTEA",
+		StorageText: "TEXT", StorageMediumText: "TEXT", StorageLongText: "TEXT", StorageJSON: "JSONB",
+	},
+	"sqlite": {
+		StorageBinary: "BLOB", StorageMediumBinary: "BLOB", StorageLongBinary: "BLOB",
+		StorageText: "TEXT", StorageMediumText: "TEXT", StorageLongText: "TEXT", StorageJSON: "TEXT",
+	},
+	"sqlserver": {
+		StorageBinary: "varbinary(max)", StorageMediumBinary: "varbinary(max)", StorageLongBinary: "varbinary(max)",
+		StorageText: "nvarchar(max)", StorageMediumText: "nvarchar(max)", StorageLongText: "nvarchar(max)", StorageJSON: "nvarchar(max)",
+	},
+}
+
+// bindStorage binds a field to the Storage it's tagged with, if any
+func bindStorage(field *schema.Field, value interface{}) error {
+	binder, ok := value.(StorageBinder)
+	if !ok {
+		return nil
+	}
+
+	storage, ok, err := FieldStorage(field)
+	if err != nil || !ok {
+		return err
+	}
+	if bound, ok := binder.BoundStorage(); !ok || bound != storage {
+		binder.BindStorage(storage)
+	}
+
+	return nil
+}
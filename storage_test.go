@@ -0,0 +1,181 @@
+package gormcrypto_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	gormcrypto "github.com/danhunsaker/gorm-crypto"
+	"github.com/danhunsaker/gorm-crypto/cryptypes"
+)
+
+type storageTestModel struct {
+	ID     uint
+	Secret cryptypes.EncryptedString
+	Note   cryptypes.EncryptedString       `gormcrypto:"storage:text"`
+	Doc    cryptypes.SignedEncryptedString `gormcrypto:"storage:json"`
+	Blob   cryptypes.EncryptedString       `gormcrypto:"storage:long-binary"`
+}
+
+type storageBadTagModel struct {
+	ID     uint
+	Secret cryptypes.EncryptedString `gormcrypto:"storage:bogus"`
+}
+
+func TestStorage(t *testing.T) {
+	db := openTestDB(t, "", newTestConfig(newTestSetup(t, "StorageEncryptionKeyIs32BytesLng", "StorageSigningKeyThatIs32BytesLg")), &storageTestModel{})
+
+	columns, err := db.Migrator().ColumnTypes(&storageTestModel{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedTypes := map[string]string{"secret": "BLOB", "note": "TEXT", "doc": "TEXT", "blob": "BLOB"}
+	for _, column := range columns {
+		if expected, ok := expectedTypes[column.Name()]; ok && !strings.EqualFold(column.DatabaseTypeName(), expected) {
+			t.Errorf("Expected %s to be %v; got %v instead", column.Name(), expected, column.DatabaseTypeName())
+		}
+	}
+
+	expected := storageTestModel{
+		Secret: cryptypes.EncryptedString{Raw: "Secret"},
+		Note:   cryptypes.EncryptedString{Raw: "Note"},
+		Doc:    cryptypes.SignedEncryptedString{Raw: "Doc"},
+		Blob:   cryptypes.EncryptedString{Raw: "Blob"},
+	}
+	if err := db.Create(&expected).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&storageTestModel{ID: expected.ID}).Updates(map[string]interface{}{"note": "Updated"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	expected.Note.Raw = "Updated"
+
+	var secret, blob []byte
+	var note, doc string
+	if err := db.Table("storage_test_models").Select("secret", "note", "doc", "blob").Where("id = ?", expected.ID).Row().Scan(&secret, &note, &doc, &blob); err != nil {
+		t.Fatal(err)
+	}
+	if !cryptypes.IsEnvelope(secret) || !cryptypes.IsEnvelope(blob) {
+		t.Errorf("Expected binary envelopes; got %q and %q instead", secret, blob)
+	}
+	if !strings.HasPrefix(note, gormcrypto.TextHeaderPrefix) {
+		t.Errorf("Expected a text envelope; got %q instead", note)
+	}
+	var unquoted string
+	if err := json.Unmarshal([]byte(doc), &unquoted); err != nil || !strings.HasPrefix(unquoted, gormcrypto.TextHeaderPrefix) {
+		t.Errorf("Expected a JSON string envelope; got %q instead", doc)
+	}
+
+	// The Setup's Encoder already made the payload text-safe, so it's written as it is, after the rest of the Envelope, rather than encoded again
+	fields := strings.SplitN(strings.TrimPrefix(note, gormcrypto.TextHeaderPrefix), ":", 3)
+	if len(fields) < 3 {
+		t.Fatalf("Expected a header, size, and payload; got %q instead", note)
+	}
+	header, err := base64.StdEncoding.DecodeString(fields[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var envelope cryptypes.Envelope
+	if err := envelope.UnmarshalBinary(header); err != nil {
+		t.Fatal(err)
+	}
+	if len(envelope.Raw) > 0 {
+		t.Errorf("Expected the payload to be left out of the header; got %q instead", envelope.Raw)
+	}
+	plugin := db.Config.Plugins[gormcrypto.PluginName].(*gormcrypto.Plugin)
+	if _, err := plugin.Config.CurrentSetup().Encoder.Decode([]byte(fields[2])); err != nil || fields[1] != strconv.Itoa(len(fields[2])) {
+		t.Errorf("Expected the Encoder's output as the payload; got %q (%v) instead", fields[2], err)
+	}
+
+	// Values written in text before payloads were kept as they are can still be read
+	legacy := gormcrypto.TextPrefix + base64.StdEncoding.EncodeToString(secret)
+	if err := db.Table("storage_test_models").Where("id = ?", expected.ID).Update("note", legacy).Error; err != nil {
+		t.Fatal(err)
+	}
+	expected.Note.Raw = expected.Secret.Raw
+
+	var actual storageTestModel
+	if err := db.First(&actual, expected.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if actual.Secret.Raw != expected.Secret.Raw || actual.Note.Raw != expected.Note.Raw || actual.Blob.Raw != expected.Blob.Raw {
+		t.Errorf("Expected %v; got %v instead", expected, actual)
+	}
+	if actual.Doc.Raw != expected.Doc.Raw || !actual.Doc.Valid {
+		t.Errorf("Expected valid %v; got %v (valid = %v) instead", expected.Doc.Raw, actual.Doc.Raw, actual.Doc.Valid)
+	}
+
+	for key, setup := range plugin.Config.Setups {
+		setup.Storage = gormcrypto.StorageText
+		plugin.Config.Setups[key] = setup
+	}
+	if err := db.Save(&actual).Error; err != nil {
+		t.Fatal(err)
+	}
+	var stored interface{}
+	if err := db.Table("storage_test_models").Select("secret").Where("id = ?", expected.ID).Row().Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if text, ok := stored.(string); !ok || !strings.HasPrefix(text, gormcrypto.TextHeaderPrefix) {
+		t.Errorf("Expected the Setup's Storage to write text; got %q instead", stored)
+	}
+	if err := db.First(&actual, expected.ID).Error; err != nil || actual.Secret.Raw != expected.Secret.Raw {
+		t.Errorf("Expected %v; got %v (%v) instead", expected.Secret.Raw, actual.Secret.Raw, err)
+	}
+
+	if err := db.Create(&storageBadTagModel{Secret: cryptypes.EncryptedString{Raw: "Test"}}).Error; err == nil || !strings.Contains(err.Error(), "unknown storage") {
+		t.Errorf("Expected an error for an unknown storage tag; got %v instead", err)
+	}
+}
+
+func TestStorageColumnTypes(t *testing.T) {
+	for dialect, expected := range map[string][]string{
+		"mysql":     {"BLOB", "MEDIUMBLOB", "LONGBLOB", "TEXT", "MEDIUMTEXT", "LONGTEXT", "JSON"},
+		"postgres":  {"BYTEA", "BYTEA", "BYTEA", "TEXT", "TEXT", "TEXT", "JSONB"},
+		"sqlserver": {"varbinary(max)", "varbinary(max)", "varbinary(max)", "nvarchar(max)", "nvarchar(max)", "nvarchar(max)", "nvarchar(max)"},
+		"bigquery":  {"BYTES", "BYTES", "BYTES", "STRING", "STRING", "STRING", "JSON"},
+	} {
+		for storage := gormcrypto.StorageBinary; storage <= gormcrypto.StorageJSON; storage++ {
+			if actual := storage.ColumnType(dialect); actual != expected[storage] {
+				t.Errorf("Expected %v for %v on %v; got %v instead", expected[storage], storage, dialect, actual)
+			}
+		}
+	}
+
+	if actual := gormcrypto.StorageText.ColumnType("unknown"); actual != "" {
+		t.Errorf("Expected no column type for an unknown dialect; got %v instead", actual)
+	}
+	if gormcrypto.Storage(42).Text() {
+		t.Error("Expected an unknown storage not to be text")
+	}
+}
+
+func TestStorageExport(t *testing.T) {
+	config := getTestConfig()
+	keys := sortedKeys(config)
+
+	text := config.Setups[keys[0]]
+	text.Storage = gormcrypto.StorageJSON
+	config.Setups[keys[0]] = text
+
+	yaml, err := config.ConfigToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(yaml), "storage: json") {
+		t.Errorf("Expected the Storage to be exported; got %s instead", yaml)
+	}
+	imported := gormcrypto.ConfigFromBytes(yaml)
+
+	if !reflect.DeepEqual(imported, config) {
+		t.Errorf("Expected %v; got %v instead", config, imported)
+	}
+
+	var storage gormcrypto.Storage
+	if err := storage.UnmarshalText([]byte("bogus")); err == nil {
+		t.Error("Expected an error for an unknown storage; got none")
+	}
+}